@@ -25,6 +25,10 @@ func main() {
 		ChapterHandler:        application.ChapterHandler,
 		TranslationJobHandler: application.TranslationJobHandler,
 		MiscellaneousHandler:  application.MiscellaneousHandler,
+		WebhookHandler:        application.WebhookHandler,
+		EpubImportHandler:     application.EpubImportHandler,
+		GlossaryHandler:       application.GlossaryHandler,
+		MemoryHandler:         application.MemoryHandler,
 		UserService:           application.UserService,
 	}
 
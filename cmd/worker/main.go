@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"simple-go/internal/app"
+)
+
+func main() {
+	// Initialize worker dependencies
+	worker, err := app.InitializeWorker()
+	if err != nil {
+		log.Fatalf("Failed to initialize worker: %v", err)
+	}
+	defer worker.Queue.Close()
+
+	// Stop gracefully on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go worker.Webhooks.RunDeliveryLoop(ctx)
+	go worker.Imports.RunImportLoop(ctx)
+
+	if err := worker.TranslationWorker.Run(ctx); err != nil {
+		log.Fatalf("Translation worker exited: %v", err)
+	}
+}
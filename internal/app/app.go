@@ -1,7 +1,12 @@
 package app
 
 import (
+	"time"
+
+	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/quota"
 	"simple-go/internal/handler"
+	"simple-go/internal/repository"
 	"simple-go/internal/repository/gormrepo"
 	"simple-go/internal/service"
 	"simple-go/pkg/auth"
@@ -10,6 +15,7 @@ import (
 	"simple-go/pkg/database"
 	"simple-go/pkg/logger"
 	"simple-go/pkg/queue"
+	"simple-go/pkg/webhook"
 
 	"github.com/casbin/casbin/v2"
 	"gorm.io/gorm"
@@ -22,6 +28,10 @@ type App struct {
 	UserHandler           *handler.UserHandler
 	NovelHandler          *handler.NovelHandler
 	TranslationJobHandler *handler.TranslationJobHandler
+	WebhookHandler        *handler.WebhookHandler
+	EpubImportHandler     *handler.EpubImportHandler
+	GlossaryHandler       *handler.GlossaryHandler
+	MemoryHandler         *handler.TranslationMemoryHandler
 	ChapterHandler        *handler.ChapterHandler
 	MiscellaneousHandler  *handler.MiscellaneousHandler
 	UserService           *service.UserService
@@ -48,6 +58,8 @@ func Initialize() (*App, error) {
 	chapterRepo := gormrepo.NewChapterRepository(db)
 	mediaRepo := gormrepo.NewMediaRepository(db)
 	jobRepo := gormrepo.NewTranslationJobRepository(db)
+	glossaryRepo := gormrepo.NewGlossaryRepository(db)
+	quotaRepo := gormrepo.NewQuotaRepository(db)
 	uow := gormrepo.NewUnitOfWork(db)
 
 	enforcer, err := casbinpkg.NewEnforcer(db, cfg.Casbin.ModelPath)
@@ -77,12 +89,18 @@ func Initialize() (*App, error) {
 	}
 
 	// Initialize services
-	authService := service.NewAuthService(uow, userRepo, roleRepo, jwtManager, permissionService)
+	quotaService := service.NewQuotaService(newQuotaPolicy(cfg), userRepo, jobRepo, quotaRepo)
+	authService := service.NewAuthService(uow, userRepo, roleRepo, jwtManager, permissionService, quotaService)
 	userService := service.NewUserService(userRepo, roleRepo)
 	volumeService := service.NewVolumeService(uow, volumeRepo, chapterRepo, mediaService)
 	novelService := service.NewNovelService(uow, novelRepo, mediaService, volumeService, epubService)
-	chapterService := service.NewChapterService(uow, chapterRepo)
-	jobService := service.NewTranslationJobService(uow, jobRepo, novelRepo, volumeRepo, chapterRepo, redisQueue)
+	memoryService := newTranslationMemoryService(cfg, db, chapterRepo, volumeRepo)
+	chapterService := service.NewChapterService(uow, chapterRepo, userRepo, memoryService)
+	webhookService := newWebhookService(cfg, db)
+	eventService := service.NewTranslationEventService(redisQueue, webhookService)
+	jobService := service.NewTranslationJobService(uow, jobRepo, novelRepo, volumeRepo, chapterRepo, redisQueue, eventService, memoryService, quotaService)
+	glossaryService := service.NewGlossaryService(uow, glossaryRepo, novelRepo)
+	importService := newEpubImportService(cfg, db, uow, novelService)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
@@ -90,6 +108,10 @@ func Initialize() (*App, error) {
 	novelHandler := handler.NewNovelHandler(novelService)
 	chapterHandler := handler.NewChapterHandler(chapterService, volumeService)
 	translationJobHandler := handler.NewTranslationJobHandler(jobService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	importHandler := handler.NewEpubImportHandler(importService)
+	glossaryHandler := handler.NewGlossaryHandler(glossaryService)
+	memoryHandler := handler.NewTranslationMemoryHandler(memoryService)
 	miscellaneousHandler := handler.NewMiscellaneousHandler()
 
 	return &App{
@@ -102,8 +124,91 @@ func Initialize() (*App, error) {
 		UserService:           userService,
 		MediaService:          mediaService,
 		TranslationJobHandler: translationJobHandler,
+		WebhookHandler:        webhookHandler,
+		EpubImportHandler:     importHandler,
+		GlossaryHandler:       glossaryHandler,
+		MemoryHandler:         memoryHandler,
 		MiscellaneousHandler:  miscellaneousHandler,
 		JWTManager:            jwtManager,
 		Enforcer:              enforcer,
 	}, nil
 }
+
+// newQuotaPolicy converts the configured translation quotas
+func newQuotaPolicy(cfg *config.Config) quota.Policy {
+	limits := func(l config.QuotaLimits) quota.Limits {
+		return quota.Limits{
+			DailyCharacters:   l.DailyCharacters,
+			MonthlyCharacters: l.MonthlyCharacters,
+			MaxActiveJobs:     l.MaxActiveJobs,
+		}
+	}
+
+	roles := make(map[string]quota.Limits, len(cfg.Quota.Roles))
+	for name, l := range cfg.Quota.Roles {
+		roles[name] = limits(l)
+	}
+
+	return quota.Policy{
+		Default: limits(cfg.Quota.Default),
+		Roles:   roles,
+		Global:  limits(cfg.Quota.Global),
+	}
+}
+
+// newWebhookService is shared by the API, which records deliveries, and the
+// worker, which sends them
+func newWebhookService(cfg *config.Config, db *gorm.DB) *service.WebhookService {
+	return service.NewWebhookService(
+		gormrepo.NewWebhookRepository(db),
+		webhook.NewSender(time.Duration(cfg.Webhook.TimeoutSeconds)*time.Second),
+		service.WebhookOptions{
+			RetryPolicy: job.RetryPolicy{
+				MaxAttempts: cfg.Webhook.MaxAttempts,
+				BaseBackoff: time.Duration(cfg.Webhook.RetryBackoffSeconds) * time.Second,
+				MaxBackoff:  time.Duration(cfg.Webhook.RetryMaxBackoffSeconds) * time.Second,
+			},
+			DeliveryInterval: time.Duration(cfg.Webhook.DeliveryIntervalSeconds) * time.Second,
+			// Longer than a request can take, so a claimed delivery is never sent twice
+			DeliveryLease: 2 * time.Duration(cfg.Webhook.TimeoutSeconds) * time.Second,
+			BatchSize:     50,
+		},
+	)
+}
+
+// newEpubImportService is shared by the API, which queues uploaded EPUBs, and
+// the worker, which imports them
+func newEpubImportService(
+	cfg *config.Config,
+	db *gorm.DB,
+	uow repository.UnitOfWork,
+	novelService *service.NovelService,
+) *service.EpubImportService {
+	return service.NewEpubImportService(
+		uow,
+		gormrepo.NewEpubImportRepository(db),
+		novelService,
+		service.EpubImportOptions{
+			PollInterval: time.Duration(cfg.Import.PollIntervalSeconds) * time.Second,
+			Lease:        time.Duration(cfg.Import.LeaseSeconds) * time.Second,
+			ChunkSize:    cfg.Import.ChunkSize,
+			MaxAttempts:  cfg.Import.MaxAttempts,
+		},
+	)
+}
+
+// newTranslationMemoryService is shared by the API, which learns accepted
+// translations, and the worker, which reuses them
+func newTranslationMemoryService(
+	cfg *config.Config,
+	db *gorm.DB,
+	chapterRepo repository.ChapterRepository,
+	volumeRepo repository.VolumeRepository,
+) *service.TranslationMemoryService {
+	return service.NewTranslationMemoryService(
+		gormrepo.NewTranslationMemoryRepository(db),
+		chapterRepo,
+		volumeRepo,
+		float64(cfg.Memory.FuzzyMinPercent)/100,
+	)
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"simple-go/internal/domain/job"
+	"simple-go/internal/repository/gormrepo"
+	"simple-go/internal/service"
+	"simple-go/pkg/config"
+	"simple-go/pkg/database"
+	"simple-go/pkg/queue"
+	"simple-go/pkg/translator"
+
+	"gorm.io/gorm"
+)
+
+type Worker struct {
+	Config            *config.Config
+	DB                *gorm.DB
+	Queue             *queue.RedisQueue
+	TranslationWorker *service.TranslationWorkerService
+	Webhooks          *service.WebhookService
+	Imports           *service.EpubImportService
+}
+
+// InitializeWorker wires the dependencies needed by the translation worker binary
+func InitializeWorker() (*Worker, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := database.Connect(&cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unlike the API, the worker cannot do anything useful without Redis
+	redisQueue, err := queue.NewRedisQueue(cfg.Redis.URL, cfg.Redis.QueueName)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := translator.New(cfg.Translator)
+	if err != nil {
+		return nil, err
+	}
+
+	novelRepo := gormrepo.NewNovelRepository(db)
+	volumeRepo := gormrepo.NewVolumeRepository(db)
+	chapterRepo := gormrepo.NewChapterRepository(db)
+	jobRepo := gormrepo.NewTranslationJobRepository(db)
+	mediaRepo := gormrepo.NewMediaRepository(db)
+	uow := gormrepo.NewUnitOfWork(db)
+	webhookService := newWebhookService(cfg, db)
+	memoryService := newTranslationMemoryService(cfg, db, chapterRepo, volumeRepo)
+
+	// EPUB imports upload covers and save novels like the API does
+	mediaService := service.NewMediaService(mediaRepo, service.NewUploadService(nil, cfg.Media.ImgBBAPIKey, cfg.Media.ImgBBTTL))
+	volumeService := service.NewVolumeService(uow, volumeRepo, chapterRepo, mediaService)
+	novelService := service.NewNovelService(uow, novelRepo, mediaService, volumeService, service.NewEpubService())
+	importService := newEpubImportService(cfg, db, uow, novelService)
+
+	translationWorker := service.NewTranslationWorkerService(
+		uow,
+		jobRepo,
+		novelRepo,
+		volumeRepo,
+		chapterRepo,
+		redisQueue,
+		engine,
+		memoryService,
+		service.NewTranslationEventService(redisQueue, webhookService),
+		service.TranslationWorkerOptions{
+			WorkerID:          workerID(cfg.Worker.ID),
+			PollTimeout:       time.Duration(cfg.Worker.PollTimeoutSeconds) * time.Second,
+			LeaseDuration:     time.Duration(cfg.Worker.LeaseSeconds) * time.Second,
+			HeartbeatInterval: time.Duration(cfg.Worker.HeartbeatSeconds) * time.Second,
+			ReaperInterval:    time.Duration(cfg.Worker.ReaperIntervalSeconds) * time.Second,
+			RetryPolicy: job.RetryPolicy{
+				MaxAttempts: cfg.Worker.MaxAttempts,
+				BaseBackoff: time.Duration(cfg.Worker.RetryBackoffSeconds) * time.Second,
+				MaxBackoff:  time.Duration(cfg.Worker.RetryMaxBackoffSeconds) * time.Second,
+			},
+			QAPolicy: job.QAPolicy{
+				MaxErrors:   cfg.QA.MaxErrors,
+				MaxWarnings: cfg.QA.MaxWarnings,
+			},
+			TurnSubtasks: cfg.Worker.TurnSubtasks,
+		},
+	)
+
+	return &Worker{
+		Config:            cfg,
+		DB:                db,
+		Queue:             redisQueue,
+		TranslationWorker: translationWorker,
+		Webhooks:          webhookService,
+		Imports:           importService,
+	}, nil
+}
+
+// workerID falls back to hostname-pid so that replicas get distinct lease owners
+func workerID(configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "worker"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
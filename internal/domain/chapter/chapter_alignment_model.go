@@ -0,0 +1,43 @@
+package chapter
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SegmentPair links source segments to the target segments translating
+// them, by index into the translatable segments of each side's content
+type SegmentPair struct {
+	Source []int `json:"source"`
+	Target []int `json:"target"`
+}
+
+// ChapterAlignment stores how the segments of a chapter's translation in
+// TargetLang line up with its translation in SourceLang. The hashes identify
+// the texts that were aligned so the alignment is rebuilt once either side
+// changes; Outdated forces a rebuild as well.
+type ChapterAlignment struct {
+	ID         string        `gorm:"type:uuid;primaryKey"`
+	ChapterID  string        `gorm:"type:uuid;not null;uniqueIndex:idx_chapter_alignment_pair,priority:1"`
+	SourceLang string        `gorm:"type:varchar(10);not null;uniqueIndex:idx_chapter_alignment_pair,priority:2"`
+	TargetLang string        `gorm:"type:varchar(10);not null;uniqueIndex:idx_chapter_alignment_pair,priority:3"`
+	SourceHash string        `gorm:"type:char(64);not null"`
+	TargetHash string        `gorm:"type:char(64);not null"`
+	Pairs      []SegmentPair `gorm:"type:jsonb;serializer:json;not null"`
+	Outdated   bool          `gorm:"not null;default:false"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time     `gorm:"autoUpdateTime"`
+}
+
+func (a *ChapterAlignment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (ChapterAlignment) TableName() string {
+	return "chapter_alignments"
+}
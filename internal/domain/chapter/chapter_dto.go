@@ -25,8 +25,94 @@ type ChapterResponseDTO struct {
 	WordCount         *int      `json:"word_count"`
 	Title             string    `json:"title"`
 	Content           string    `json:"content"`
+	Lang              string    `json:"lang,omitempty"`
+	TranslationStatus string    `json:"translation_status,omitempty"`
+	Outdated          bool      `json:"outdated,omitempty"`
 	NextChapterID     *string   `json:"next_chapter_id"`
 	PreviousChapterID *string   `json:"previous_chapter_id"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 }
+
+type AssignReviewerDTO struct {
+	ReviewerID string `json:"reviewer_id" binding:"required,uuid"`
+}
+
+type ReviewChapterTranslationDTO struct {
+	Action string  `json:"action" binding:"required,oneof=approve reject"`
+	Note   *string `json:"note"`
+	// Title and Content let the reviewer correct the text while approving
+	Title   *string `json:"title" binding:"omitempty,min=1"`
+	Content *string `json:"content" binding:"omitempty,min=1"`
+}
+
+// ReviewQueueQueryDTO filters the review queue. Assignee is a user ID, "me"
+// for the caller or "none" for translations nobody has picked up yet.
+type ReviewQueueQueryDTO struct {
+	Status   string `form:"status" binding:"omitempty,oneof=machine_draft in_review approved rejected"`
+	Lang     string `form:"lang"`
+	NovelID  string `form:"novel_id" binding:"omitempty,uuid"`
+	Assignee string `form:"assignee"`
+}
+
+type ChapterTranslationReviewDTO struct {
+	ID         string     `json:"id"`
+	ChapterID  string     `json:"chapter_id"`
+	Lang       string     `json:"lang"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	Origin     string     `json:"origin"`
+	SourceLang *string    `json:"source_lang,omitempty"`
+	PivotLang  *string    `json:"pivot_lang,omitempty"`
+	Outdated   bool       `json:"outdated"`
+	ReviewerID *string    `json:"reviewer_id,omitempty"`
+	ReviewedBy *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote *string    `json:"review_note,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// RevisionQueryDTO filters the pending revisions of approved translations
+type RevisionQueryDTO struct {
+	Lang    string `form:"lang"`
+	NovelID string `form:"novel_id" binding:"omitempty,uuid"`
+}
+
+// ChapterTranslationRevisionDTO is a pending revision. Content is only
+// included when a single revision is requested.
+type ChapterTranslationRevisionDTO struct {
+	ID            string    `json:"id"`
+	TranslationID string    `json:"translation_id"`
+	ChapterID     string    `json:"chapter_id"`
+	Lang          string    `json:"lang"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content,omitempty"`
+	SourceLang    *string   `json:"source_lang,omitempty"`
+	PivotLang     *string   `json:"pivot_lang,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type BilingualQueryDTO struct {
+	Source string `form:"source" binding:"required"`
+	Target string `form:"target" binding:"required,nefield=Source"`
+}
+
+// BilingualChapterDTO pairs the segments of two translations of a chapter.
+// Most segments pair one to one; merged or split paragraphs put several
+// segments on one side and unmatched ones leave a side empty.
+type BilingualChapterDTO struct {
+	ChapterID    string                `json:"chapter_id"`
+	SourceLang   string                `json:"source_lang"`
+	TargetLang   string                `json:"target_lang"`
+	TargetStatus string                `json:"target_status"`
+	Title        BilingualSegmentDTO   `json:"title"`
+	Segments     []BilingualSegmentDTO `json:"segments"`
+}
+
+type BilingualSegmentDTO struct {
+	Seq    int      `json:"seq"`
+	Source []string `json:"source"`
+	Target []string `json:"target"`
+}
@@ -1,20 +1,26 @@
 package chapter
 
 func MapChapterToDTO(c Chapter, lang string, nextID, prevID *string) ChapterResponseDTO {
-	selected := SelectTranslation(c.Translations, lang)
-
-	return ChapterResponseDTO{
+	res := ChapterResponseDTO{
 		ID:                c.ID,
 		VolumeID:          c.VolumeID,
 		Number:            c.Number,
 		WordCount:         c.WordCount,
-		Title:             selected.Title,
-		Content:           selected.Content,
 		NextChapterID:     nextID,
 		PreviousChapterID: prevID,
 		CreatedAt:         c.CreatedAt,
 		UpdatedAt:         c.UpdatedAt,
 	}
+
+	if selected := SelectTranslation(c.Translations, lang); selected != nil {
+		res.Title = selected.Title
+		res.Content = selected.Content
+		res.Lang = selected.Lang
+		res.TranslationStatus = selected.Status
+		res.Outdated = selected.Outdated
+	}
+
+	return res
 }
 
 func MapChapterAndTranslationToDTO(c Chapter, t ChapterTranslation) ChapterResponseDTO {
@@ -25,9 +31,63 @@ func MapChapterAndTranslationToDTO(c Chapter, t ChapterTranslation) ChapterRespo
 		WordCount:         c.WordCount,
 		Title:             t.Title,
 		Content:           t.Content,
+		Lang:              t.Lang,
+		TranslationStatus: t.Status,
+		Outdated:          t.Outdated,
 		NextChapterID:     nil,
 		PreviousChapterID: nil,
 		CreatedAt:         c.CreatedAt,
 		UpdatedAt:         c.UpdatedAt,
 	}
 }
+
+func MapTranslationToReviewDTO(t ChapterTranslation) ChapterTranslationReviewDTO {
+	return ChapterTranslationReviewDTO{
+		ID:         t.ID,
+		ChapterID:  t.ChapterID,
+		Lang:       t.Lang,
+		Title:      t.Title,
+		Status:     t.Status,
+		Origin:     t.Origin,
+		SourceLang: t.SourceLang,
+		PivotLang:  t.PivotLang,
+		Outdated:   t.Outdated,
+		ReviewerID: t.ReviewerID,
+		ReviewedBy: t.ReviewedBy,
+		ReviewedAt: t.ReviewedAt,
+		ReviewNote: t.ReviewNote,
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+func MapRevisionToDTO(r ChapterTranslationRevision) ChapterTranslationRevisionDTO {
+	return ChapterTranslationRevisionDTO{
+		ID:            r.ID,
+		TranslationID: r.TranslationID,
+		ChapterID:     r.ChapterID,
+		Lang:          r.Lang,
+		Title:         r.Title,
+		Content:       r.Content,
+		SourceLang:    r.SourceLang,
+		PivotLang:     r.PivotLang,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
+
+func MapRevisionsToDTOs(revisions []ChapterTranslationRevision) []ChapterTranslationRevisionDTO {
+	res := make([]ChapterTranslationRevisionDTO, len(revisions))
+	for i, r := range revisions {
+		res[i] = MapRevisionToDTO(r)
+	}
+	return res
+}
+
+func MapTranslationsToReviewDTOs(translations []ChapterTranslation) []ChapterTranslationReviewDTO {
+	res := make([]ChapterTranslationReviewDTO, len(translations))
+	for i, t := range translations {
+		res[i] = MapTranslationToReviewDTO(t)
+	}
+	return res
+}
@@ -16,6 +16,7 @@ type Chapter struct {
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 
 	Translations []ChapterTranslation `gorm:"foreignKey:ChapterID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Alignments   []ChapterAlignment   `gorm:"foreignKey:ChapterID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 }
 
 func (c *Chapter) BeforeCreate(tx *gorm.DB) error {
@@ -21,3 +21,15 @@ func SelectTranslation(translations []ChapterTranslation, lang string) *ChapterT
 
 	return &translations[0]
 }
+
+// PivotTranslation returns the translation in lang a pivot translation can
+// start from. Only approved translations qualify, so unreviewed machine output
+// is never translated again as if it were checked.
+func PivotTranslation(translations []ChapterTranslation, lang string) *ChapterTranslation {
+	for i := range translations {
+		if translations[i].Lang == lang && translations[i].IsApproved() {
+			return &translations[i]
+		}
+	}
+	return nil
+}
@@ -7,6 +7,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// Review lifecycle of a chapter translation. Machine output starts as a
+// draft and only approved translations are served to readers.
+const (
+	TranslationStatusMachineDraft = "machine_draft"
+	TranslationStatusInReview     = "in_review"
+	TranslationStatusApproved     = "approved"
+	TranslationStatusRejected     = "rejected"
+)
+
+// Origin of a chapter translation's text
+const (
+	TranslationOriginHuman   = "human"
+	TranslationOriginMachine = "machine"
+)
+
 type ChapterTranslation struct {
 	ID        string    `gorm:"type:uuid;primaryKey"`
 	ChapterID string    `gorm:"type:uuid;not null;index"`
@@ -15,15 +30,71 @@ type ChapterTranslation struct {
 	Content   string    `gorm:"type:text;not null"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	Status     string     `gorm:"type:varchar(20);not null;default:'approved';index"`
+	Origin     string     `gorm:"type:varchar(10);not null;default:'human'"`
+	ReviewerID *string    `gorm:"type:uuid;index"`
+	ReviewedBy *string    `gorm:"type:uuid"`
+	ReviewedAt *time.Time `gorm:"type:timestamp"`
+	ReviewNote *string    `gorm:"type:text"`
+
+	// Provenance of machine output: the language it was translated from and,
+	// for pivot translations, the intermediate language it went through
+	SourceLang *string `gorm:"type:varchar(10)"`
+	PivotLang  *string `gorm:"type:varchar(10)"`
+	// Outdated is set when the text the translation was made from is
+	// replaced, and cleared once the translation itself is replaced
+	Outdated bool `gorm:"not null;default:false"`
+
+	Revision *ChapterTranslationRevision `gorm:"foreignKey:TranslationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 }
 
 func (ct *ChapterTranslation) BeforeCreate(tx *gorm.DB) error {
 	if ct.ID == "" {
 		ct.ID = uuid.New().String()
 	}
+	if ct.Status == "" {
+		ct.Status = TranslationStatusApproved
+	}
+	if ct.Origin == "" {
+		ct.Origin = TranslationOriginHuman
+	}
 	return nil
 }
 
 func (ChapterTranslation) TableName() string {
 	return "chapter_translations"
 }
+
+// IsApproved reports whether the translation may be shown to readers
+func (ct ChapterTranslation) IsApproved() bool {
+	return ct.Status == TranslationStatusApproved
+}
+
+// ApprovedTranslations filters out translations still under review
+func ApprovedTranslations(translations []ChapterTranslation) []ChapterTranslation {
+	approved := make([]ChapterTranslation, 0, len(translations))
+	for _, t := range translations {
+		if t.IsApproved() {
+			approved = append(approved, t)
+		}
+	}
+	return approved
+}
+
+// IsMachineDraft reports whether the translation is unreviewed or rejected
+// machine output, which newer machine output may simply replace
+func (ct ChapterTranslation) IsMachineDraft() bool {
+	return ct.Origin == TranslationOriginMachine &&
+		(ct.Status == TranslationStatusMachineDraft || ct.Status == TranslationStatusRejected)
+}
+
+// ReviewQueueFilter selects translations for the review queue. Empty fields
+// match anything.
+type ReviewQueueFilter struct {
+	Statuses   []string
+	Lang       string
+	NovelID    string
+	ReviewerID string
+	Unassigned bool
+}
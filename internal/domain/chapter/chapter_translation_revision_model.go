@@ -0,0 +1,36 @@
+package chapter
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChapterTranslationRevision is machine output for a translation that must
+// not be overwritten, because it was approved or written by a person. The
+// translation stays live until a reviewer accepts the revision. A translation
+// has at most one pending revision; newer output replaces it.
+type ChapterTranslationRevision struct {
+	ID            string    `gorm:"type:uuid;primaryKey"`
+	TranslationID string    `gorm:"type:uuid;not null;uniqueIndex"`
+	ChapterID     string    `gorm:"type:uuid;not null;index"`
+	Lang          string    `gorm:"type:varchar(10);not null;index"`
+	Title         string    `gorm:"type:varchar(500);not null"`
+	Content       string    `gorm:"type:text;not null"`
+	SourceLang    *string   `gorm:"type:varchar(10)"`
+	PivotLang     *string   `gorm:"type:varchar(10)"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+func (r *ChapterTranslationRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (ChapterTranslationRevision) TableName() string {
+	return "chapter_translation_revisions"
+}
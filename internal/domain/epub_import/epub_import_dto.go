@@ -0,0 +1,30 @@
+package epubimport
+
+import (
+	"time"
+
+	"simple-go/internal/domain/novel"
+)
+
+type EpubImportResponseDTO struct {
+	ID               string     `json:"id"`
+	Kind             string     `json:"kind"`
+	FileName         string     `json:"file_name"`
+	FileSize         int64      `json:"file_size"`
+	Status           string     `json:"status"`
+	Stage            string     `json:"stage"`
+	SourceType       *string    `json:"source_type"`
+	NovelID          *string    `json:"novel_id"`
+	TotalChapters    int        `json:"total_chapters"`
+	ImportedChapters int        `json:"imported_chapters"`
+	Progress         int        `json:"progress"`
+	Attempts         int        `json:"attempts"`
+	ErrorMessage     *string    `json:"error_message"`
+	StartedAt        *time.Time `json:"started_at"`
+	FinishedAt       *time.Time `json:"finished_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	UpdateContent bool                      `json:"update_content,omitempty"`
+	MergeResult   *novel.EpubMergeResultDTO `json:"merge_result,omitempty"`
+}
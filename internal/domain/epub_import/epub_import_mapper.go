@@ -0,0 +1,25 @@
+package epubimport
+
+func MapEpubImportToDTO(i EpubImport) EpubImportResponseDTO {
+	return EpubImportResponseDTO{
+		ID:               i.ID,
+		Kind:             i.Kind,
+		FileName:         i.FileName,
+		FileSize:         i.FileSize,
+		Status:           i.Status,
+		Stage:            i.Stage,
+		SourceType:       i.SourceType,
+		NovelID:          i.NovelID,
+		TotalChapters:    i.TotalChapters,
+		ImportedChapters: i.ImportedChapters,
+		Progress:         i.Progress(),
+		Attempts:         i.Attempts,
+		ErrorMessage:     i.ErrorMessage,
+		StartedAt:        i.StartedAt,
+		FinishedAt:       i.FinishedAt,
+		CreatedAt:        i.CreatedAt,
+		UpdatedAt:        i.UpdatedAt,
+		UpdateContent:    i.UpdateContent,
+		MergeResult:      i.MergeResult,
+	}
+}
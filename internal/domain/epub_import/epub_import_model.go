@@ -0,0 +1,93 @@
+package epubimport
+
+import (
+	"time"
+
+	"simple-go/internal/domain/novel"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	EpubImportStatusPending    = "PENDING"
+	EpubImportStatusInProgress = "IN_PROGRESS"
+	EpubImportStatusCompleted  = "COMPLETED"
+	EpubImportStatusFailed     = "FAILED"
+
+	// Stages of an import in progress
+	EpubImportStageQueued   = "queued"
+	EpubImportStageParsing  = "parsing"
+	EpubImportStageNovel    = "creating_novel" // Cover, novel, tags and volumes
+	EpubImportStageChapters = "importing_chapters"
+	EpubImportStageDone     = "done"
+
+	EpubImportKindImport = "import" // Creates a new novel
+	EpubImportKindMerge  = "merge"  // Merges the EPUB into NovelID
+)
+
+// EpubImport imports an uploaded EPUB in the background, as a new novel or
+// merged into an existing one. The worker saves chapters in chunks and
+// records how many are saved, so an import interrupted by a crash resumes
+// where it stopped.
+type EpubImport struct {
+	ID               string     `gorm:"type:uuid;primaryKey"`
+	CreatedBy        string     `gorm:"type:uuid;not null;index"`
+	Kind             string     `gorm:"type:varchar(10);not null;default:'import'"`
+	FileName         string     `gorm:"type:varchar(255);not null"`
+	FileSize         int64      `gorm:"type:bigint;not null"`
+	Status           string     `gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	Stage            string     `gorm:"type:varchar(30);not null;default:'queued'"`
+	SourceType       *string    `gorm:"type:varchar(50)"`
+	NovelID          *string    `gorm:"type:uuid;index"`
+	TotalChapters    int        `gorm:"type:int;not null;default:0"`
+	ImportedChapters int        `gorm:"type:int;not null;default:0"`
+	Attempts         int        `gorm:"type:int;not null;default:0"`
+	LeaseExpiresAt   *time.Time `gorm:"type:timestamp;index"`
+	ErrorMessage     *string    `gorm:"type:text"`
+	StartedAt        *time.Time `gorm:"type:timestamp"`
+	FinishedAt       *time.Time `gorm:"type:timestamp"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime"`
+
+	// UpdateContent and MergeResult only apply to merges. MergeResult is the
+	// diff of the chapters merged so far and is saved with every chunk.
+	UpdateContent bool                      `gorm:"not null;default:false"`
+	MergeResult   *novel.EpubMergeResultDTO `gorm:"type:jsonb;serializer:json"`
+
+	File *EpubImportFile `gorm:"foreignKey:ImportID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (i *EpubImport) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (EpubImport) TableName() string {
+	return "epub_imports"
+}
+
+// Progress is the share of chapters saved so far, in percent
+func (i EpubImport) Progress() int {
+	if i.Status == EpubImportStatusCompleted {
+		return 100
+	}
+	if i.TotalChapters == 0 {
+		return 0
+	}
+	return i.ImportedChapters * 100 / i.TotalChapters
+}
+
+// EpubImportFile holds the uploaded file until its import finishes. It is
+// kept apart from EpubImport so polling an import does not load the file.
+type EpubImportFile struct {
+	ImportID  string    `gorm:"type:uuid;primaryKey"`
+	Data      []byte    `gorm:"type:bytea;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (EpubImportFile) TableName() string {
+	return "epub_import_files"
+}
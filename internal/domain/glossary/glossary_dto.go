@@ -0,0 +1,46 @@
+package glossary
+
+import "time"
+
+type CreateGlossaryTermDTO struct {
+	SourceLang string  `json:"source_lang" binding:"required,min=2,max=10"`
+	TargetLang string  `json:"target_lang" binding:"required,min=2,max=10"`
+	SourceTerm string  `json:"source_term" binding:"required,max=255"`
+	TargetTerm string  `json:"target_term" binding:"required,max=255"`
+	Category   *string `json:"category" binding:"omitempty,oneof=character skill place term"`
+	Note       *string `json:"note"`
+}
+
+type UpdateGlossaryTermDTO struct {
+	SourceTerm *string `json:"source_term" binding:"omitempty,min=1,max=255"`
+	TargetTerm *string `json:"target_term" binding:"omitempty,min=1,max=255"`
+	Category   *string `json:"category" binding:"omitempty,oneof=character skill place term"`
+	Note       *string `json:"note"`
+}
+
+type GlossaryTermResponseDTO struct {
+	ID         string    `json:"id"`
+	NovelID    string    `json:"novel_id"`
+	SourceLang string    `json:"source_lang"`
+	TargetLang string    `json:"target_lang"`
+	SourceTerm string    `json:"source_term"`
+	TargetTerm string    `json:"target_term"`
+	Category   string    `json:"category"`
+	Note       *string   `json:"note,omitempty"`
+	CreatedBy  *string   `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GlossaryImportResultDTO summarises a CSV import. Rows that already exist
+// for the language pair are updated in place.
+type GlossaryImportResultDTO struct {
+	Created int                      `json:"created"`
+	Updated int                      `json:"updated"`
+	Errors  []GlossaryImportErrorDTO `json:"errors"`
+}
+
+type GlossaryImportErrorDTO struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
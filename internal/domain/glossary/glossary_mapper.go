@@ -0,0 +1,31 @@
+package glossary
+
+// MapGlossaryTermToDTO converts a GlossaryTerm model to GlossaryTermResponseDTO
+func MapGlossaryTermToDTO(t GlossaryTerm) GlossaryTermResponseDTO {
+	return GlossaryTermResponseDTO{
+		ID:         t.ID,
+		NovelID:    t.NovelID,
+		SourceLang: t.SourceLang,
+		TargetLang: t.TargetLang,
+		SourceTerm: t.SourceTerm,
+		TargetTerm: t.TargetTerm,
+		Category:   t.Category,
+		Note:       t.Note,
+		CreatedBy:  t.CreatedBy,
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+// MapGlossaryTermsToDTOs converts a slice of GlossaryTerm models to GlossaryTermResponseDTO
+func MapGlossaryTermsToDTOs(terms []GlossaryTerm) []GlossaryTermResponseDTO {
+	if terms == nil {
+		return []GlossaryTermResponseDTO{}
+	}
+
+	dtos := make([]GlossaryTermResponseDTO, len(terms))
+	for i, t := range terms {
+		dtos[i] = MapGlossaryTermToDTO(t)
+	}
+	return dtos
+}
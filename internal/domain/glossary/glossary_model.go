@@ -0,0 +1,53 @@
+package glossary
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// Term categories
+	CategoryCharacter = "character"
+	CategorySkill     = "skill"
+	CategoryPlace     = "place"
+	CategoryTerm      = "term"
+)
+
+// IsValidCategory reports whether category is one of the known term categories
+func IsValidCategory(category string) bool {
+	switch category {
+	case CategoryCharacter, CategorySkill, CategoryPlace, CategoryTerm:
+		return true
+	}
+	return false
+}
+
+// GlossaryTerm pins the translation of a recurring name or term of a novel
+// for one language pair. The translation worker passes the terms to the
+// translator and checks that the output uses them.
+type GlossaryTerm struct {
+	ID         string    `gorm:"type:uuid;primaryKey"`
+	NovelID    string    `gorm:"type:uuid;not null;index;uniqueIndex:idx_glossary_term_pair,priority:1"`
+	SourceLang string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_glossary_term_pair,priority:2"`
+	TargetLang string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_glossary_term_pair,priority:3"`
+	SourceTerm string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_glossary_term_pair,priority:4"`
+	TargetTerm string    `gorm:"type:varchar(255);not null"`
+	Category   string    `gorm:"type:varchar(20);not null;default:'term'"`
+	Note       *string   `gorm:"type:text"`
+	CreatedBy  *string   `gorm:"type:uuid"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (t *GlossaryTerm) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (GlossaryTerm) TableName() string {
+	return "glossary_terms"
+}
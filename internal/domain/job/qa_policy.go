@@ -0,0 +1,30 @@
+package job
+
+// QA outcomes recorded on a finished job. The findings themselves are
+// qa.Finding values.
+const (
+	QAStatusPassed  = "passed"
+	QAStatusFlagged = "flagged"
+	QAStatusFailed  = "failed"
+)
+
+// QAPolicy turns the findings of a job into its QA status. A negative limit
+// disables that limit.
+type QAPolicy struct {
+	// MaxErrors is the number of errors a job may have before it fails
+	MaxErrors int
+	// MaxWarnings is the number of warnings a job may have before it is
+	// flagged; any error flags it as well
+	MaxWarnings int
+}
+
+// Evaluate returns the QA status for a job with the given finding counts
+func (p QAPolicy) Evaluate(errors, warnings int) string {
+	switch {
+	case p.MaxErrors >= 0 && errors > p.MaxErrors:
+		return QAStatusFailed
+	case errors > 0 || (p.MaxWarnings >= 0 && warnings > p.MaxWarnings):
+		return QAStatusFlagged
+	}
+	return QAStatusPassed
+}
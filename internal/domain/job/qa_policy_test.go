@@ -0,0 +1,28 @@
+package job
+
+import "testing"
+
+func TestQAPolicyEvaluate(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           QAPolicy
+		errors, warnings int
+		want             string
+	}{
+		{"no findings", QAPolicy{MaxErrors: 0, MaxWarnings: 0}, 0, 0, QAStatusPassed},
+		{"warnings within the limit", QAPolicy{MaxErrors: 0, MaxWarnings: 5}, 0, 5, QAStatusPassed},
+		{"warnings over the limit", QAPolicy{MaxErrors: 0, MaxWarnings: 5}, 0, 6, QAStatusFlagged},
+		{"errors within the limit flag", QAPolicy{MaxErrors: 2, MaxWarnings: 5}, 2, 0, QAStatusFlagged},
+		{"errors over the limit fail", QAPolicy{MaxErrors: 2, MaxWarnings: 5}, 3, 0, QAStatusFailed},
+		{"failing wins over flagging", QAPolicy{MaxErrors: 0, MaxWarnings: 0}, 1, 10, QAStatusFailed},
+		{"error limit disabled", QAPolicy{MaxErrors: -1, MaxWarnings: 5}, 100, 0, QAStatusFlagged},
+		{"warning limit disabled", QAPolicy{MaxErrors: 0, MaxWarnings: -1}, 0, 100, QAStatusPassed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Evaluate(tt.errors, tt.warnings); got != tt.want {
+				t.Errorf("Evaluate(%d, %d) = %q, want %q", tt.errors, tt.warnings, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package job
+
+import "time"
+
+// RetryPolicy decides whether a failed subtask is attempted again and when
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// ShouldRetry reports whether a subtask that has been attempted attempts
+// times may be attempted again
+func (p RetryPolicy) ShouldRetry(attempts int) bool {
+	return attempts < p.MaxAttempts
+}
+
+// Backoff returns the delay before the next attempt, doubling BaseBackoff
+// after every attempt up to MaxBackoff
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := p.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return delay
+}
+
+// NextAttemptAt schedules the next attempt relative to now
+func (p RetryPolicy) NextAttemptAt(attempts int, now time.Time) time.Time {
+	return now.Add(p.Backoff(attempts))
+}
@@ -0,0 +1,74 @@
+package job
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TranslationJobBatch groups the jobs created together for several target
+// languages of one novel. Each language still gets its own job, since a novel
+// has at most one job per language.
+type TranslationJobBatch struct {
+	ID          string           `gorm:"type:uuid;primaryKey"`
+	NovelID     string           `gorm:"type:uuid;not null;index"`
+	TargetLangs []string         `gorm:"type:jsonb;serializer:json"`
+	CreatedBy   *string          `gorm:"type:uuid;index"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time        `gorm:"autoUpdateTime"`
+	Jobs        []TranslationJob `gorm:"foreignKey:BatchID;constraint:OnDelete:SET NULL"`
+}
+
+func (b *TranslationJobBatch) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (TranslationJobBatch) TableName() string {
+	return "translation_job_batches"
+}
+
+// TranslationJobBatchStatusEmpty is the status of a batch whose jobs were all
+// deleted; deleting a job only detaches it from its batch
+const TranslationJobBatchStatusEmpty = "EMPTY"
+
+// BatchStatus aggregates the statuses of a batch's jobs. A batch is pending
+// until one of its jobs starts and in progress until all of them finish; it
+// then reports the worst outcome among its jobs.
+func BatchStatus(jobs []TranslationJob) string {
+	if len(jobs) == 0 {
+		return TranslationJobBatchStatusEmpty
+	}
+
+	pending, finished := 0, 0
+	failed, cancelled := false, false
+	for _, j := range jobs {
+		switch j.Status {
+		case TranslationJobStatusPending:
+			pending++
+		case TranslationJobStatusFailed:
+			failed = true
+		case TranslationJobStatusCancelled:
+			cancelled = true
+		}
+		if IsTerminalJobStatus(j.Status) {
+			finished++
+		}
+	}
+
+	switch {
+	case pending == len(jobs):
+		return TranslationJobStatusPending
+	case finished < len(jobs):
+		return TranslationJobStatusInProgress
+	case failed:
+		return TranslationJobStatusFailed
+	case cancelled:
+		return TranslationJobStatusCancelled
+	default:
+		return TranslationJobStatusCompleted
+	}
+}
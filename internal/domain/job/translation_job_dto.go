@@ -1,22 +1,89 @@
 package job
 
-import "time"
+import (
+	"time"
+
+	"simple-go/pkg/qa"
+)
+
+// TranslationJobScope narrows which entities of a novel a job covers
+type TranslationJobScope struct {
+	Mode string `json:"mode" binding:"omitempty,oneof=full incremental"`
+	// ChapterFrom and ChapterTo are inclusive bounds on the chapter number
+	ChapterFrom *int     `json:"chapter_from" binding:"omitempty,min=0"`
+	ChapterTo   *int     `json:"chapter_to" binding:"omitempty,min=0"`
+	VolumeIDs   []string `json:"volume_ids" binding:"omitempty,dive,uuid"`
+}
 
 type CreateTranslationJobDTO struct {
 	NovelID    string `json:"novel_id" binding:"required"`
 	TargetLang string `json:"target_lang" binding:"required"`
+	Priority   int    `json:"priority" binding:"min=-5,max=5"`
+	// PublishMode decides when the staged results become visible; defaults
+	// to on_complete
+	PublishMode string `json:"publish_mode" binding:"omitempty,oneof=on_complete manual"`
+	// PivotLang routes the translation through an intermediate language
+	PivotLang string `json:"pivot_lang" binding:"omitempty,nefield=TargetLang"`
+	TranslationJobScope
+}
+
+// CreateTranslationJobBatchDTO creates one job per target language with the
+// same settings
+type CreateTranslationJobBatchDTO struct {
+	NovelID     string   `json:"novel_id" binding:"required"`
+	TargetLangs []string `json:"target_langs" binding:"required,min=1,max=20,dive,required"`
+	Priority    int      `json:"priority" binding:"min=-5,max=5"`
+	PublishMode string   `json:"publish_mode" binding:"omitempty,oneof=on_complete manual"`
+	PivotLang   string   `json:"pivot_lang"`
+	TranslationJobScope
+}
+
+// JobDTO returns the settings of the batch's job for one language
+func (d CreateTranslationJobBatchDTO) JobDTO(targetLang string) CreateTranslationJobDTO {
+	return CreateTranslationJobDTO{
+		NovelID:             d.NovelID,
+		TargetLang:          targetLang,
+		Priority:            d.Priority,
+		PublishMode:         d.PublishMode,
+		PivotLang:           d.PivotLang,
+		TranslationJobScope: d.TranslationJobScope,
+	}
+}
+
+type UpdateTranslationJobPriorityDTO struct {
+	Priority *int `json:"priority" binding:"required,min=-5,max=5"`
+}
+
+type RetryTranslationJobDTO struct {
+	// IncludeDone also re-translates subtasks that already succeeded
+	IncludeDone bool `json:"include_done"`
 }
 
 type TranslationJobResponseDTO struct {
 	ID                string     `json:"id"`
 	NovelID           string     `json:"novel_id"`
+	BatchID           *string    `json:"batch_id,omitempty"`
 	FromLang          string     `json:"from_lang"`
 	TargetLang        string     `json:"target_lang"`
+	PivotLang         *string    `json:"pivot_lang,omitempty"`
 	Status            string     `json:"status"`
+	Mode              string     `json:"mode"`
+	Priority          int        `json:"priority"`
+	ChapterFrom       *int       `json:"chapter_from,omitempty"`
+	ChapterTo         *int       `json:"chapter_to,omitempty"`
+	VolumeIDs         []string   `json:"volume_ids,omitempty"`
 	Progress          int        `json:"progress"`
 	TotalSubtasks     int        `json:"total_subtasks"`
 	CompletedSubtasks int        `json:"completed_subtasks"`
 	ErrorMessage      *string    `json:"error_message,omitempty"`
+	QAStatus          *string    `json:"qa_status,omitempty"`
+	QAErrors          int        `json:"qa_errors"`
+	QAWarnings        int        `json:"qa_warnings"`
+	PublishMode       string     `json:"publish_mode"`
+	PublishedBy       *string    `json:"published_by,omitempty"`
+	PublishedAt       *time.Time `json:"published_at,omitempty"`
+	CancelledBy       *string    `json:"cancelled_by,omitempty"`
+	CancelledAt       *time.Time `json:"cancelled_at,omitempty"`
 	StartedAt         *time.Time `json:"started_at,omitempty"`
 	FinishedAt        *time.Time `json:"finished_at,omitempty"`
 	CreatedAt         time.Time  `json:"created_at"`
@@ -33,13 +100,170 @@ type TranslationSubtaskResponseDTO struct {
 	Priority       int        `json:"priority"`
 	Status         string     `json:"status"`
 	ErrorMessage   *string    `json:"error_message,omitempty"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
 	StartedAt      *time.Time `json:"started_at,omitempty"`
 	FinishedAt     *time.Time `json:"finished_at,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
+
+	GlossaryHits        int      `json:"glossary_hits"`
+	GlossaryMisses      int      `json:"glossary_misses"`
+	GlossaryMissedTerms []string `json:"glossary_missed_terms,omitempty"`
+	MemorySegments      int      `json:"memory_segments"`
+	MemoryExactHits     int      `json:"memory_exact_hits"`
+	MemoryFuzzyHits     int      `json:"memory_fuzzy_hits"`
+	QAErrors            int      `json:"qa_errors"`
+	QAWarnings          int      `json:"qa_warnings"`
+	Staged              bool     `json:"staged"`
+}
+
+// TranslationJobBatchResponseDTO reports a batch with the progress of its jobs
+// added up
+type TranslationJobBatchResponseDTO struct {
+	ID                string                      `json:"id"`
+	NovelID           string                      `json:"novel_id"`
+	TargetLangs       []string                    `json:"target_langs"`
+	Status            string                      `json:"status"`
+	Progress          int                         `json:"progress"`
+	TotalSubtasks     int                         `json:"total_subtasks"`
+	CompletedSubtasks int                         `json:"completed_subtasks"`
+	Jobs              []TranslationJobResponseDTO `json:"jobs"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	UpdatedAt         time.Time                   `json:"updated_at"`
 }
 
 type TranslationJobDetailResponseDTO struct {
 	TranslationJobResponseDTO
 	Subtasks []TranslationSubtaskResponseDTO `json:"subtasks"`
+
+	Memory TranslationMemoryStatsDTO `json:"memory"`
+	// StagedSubtasks counts finished subtasks whose results are not published yet
+	StagedSubtasks int `json:"staged_subtasks"`
+}
+
+// StagedResultDTO shows the staged output of a subtask before it is published
+type StagedResultDTO struct {
+	SubtaskID  string `json:"subtask_id"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	TargetLang string `json:"target_lang"`
+	StagedResult
+}
+
+// PublishResultDTO reports what publishing or discarding staged results did
+type PublishResultDTO struct {
+	JobID     string `json:"job_id"`
+	Published int    `json:"published"`
+	Discarded int    `json:"discarded"`
+}
+
+// TranslationMemoryStatsDTO sums translation memory use over a job's subtasks.
+// HitRate is the share of segments served from memory without the translator.
+type TranslationMemoryStatsDTO struct {
+	Segments     int     `json:"segments"`
+	ExactHits    int     `json:"exact_hits"`
+	FuzzyHits    int     `json:"fuzzy_hits"`
+	HitRate      float64 `json:"hit_rate"`
+	FuzzyHitRate float64 `json:"fuzzy_hit_rate"`
+}
+
+// TranslationJobEstimateDTO previews a job without creating it: the
+// subtasks it would plan and how much text would be sent to the translator
+type TranslationJobEstimateDTO struct {
+	NovelID    string                                 `json:"novel_id"`
+	FromLang   string                                 `json:"from_lang"`
+	TargetLang string                                 `json:"target_lang"`
+	PivotLang  string                                 `json:"pivot_lang,omitempty"`
+	Mode       string                                 `json:"mode"`
+	Totals     TranslationEstimateStatsDTO            `json:"totals"`
+	ByEntity   map[string]TranslationEstimateStatsDTO `json:"by_entity_type"`
+	// AlreadyTranslated counts planned subtasks whose entity already has a
+	// translation in the target language; a full job translates them again
+	AlreadyTranslated int                  `json:"already_translated"`
+	Subtasks          []SubtaskEstimateDTO `json:"subtasks"`
+}
+
+// TranslationEstimateStatsDTO sizes source text. Segments served by exact
+// translation memory matches are not sent and so are not billable.
+type TranslationEstimateStatsDTO struct {
+	Segments           int `json:"segments"`
+	Characters         int `json:"characters"`
+	Words              int `json:"words"`
+	Tokens             int `json:"tokens"`
+	MemorySegments     int `json:"memory_segments"`
+	MemoryCharacters   int `json:"memory_characters"`
+	BillableCharacters int `json:"billable_characters"`
+	BillableTokens     int `json:"billable_tokens"`
+}
+
+// Add accumulates o into s
+func (s *TranslationEstimateStatsDTO) Add(o TranslationEstimateStatsDTO) {
+	s.Segments += o.Segments
+	s.Characters += o.Characters
+	s.Words += o.Words
+	s.Tokens += o.Tokens
+	s.MemorySegments += o.MemorySegments
+	s.MemoryCharacters += o.MemoryCharacters
+	s.BillableCharacters += o.BillableCharacters
+	s.BillableTokens += o.BillableTokens
+}
+
+type SubtaskEstimateDTO struct {
+	EntityType     string  `json:"entity_type"`
+	EntityID       string  `json:"entity_id"`
+	ParentVolumeID *string `json:"parent_volume_id,omitempty"`
+	Seq            int     `json:"seq"`
+	Priority       int     `json:"priority"`
+	HasTranslation bool    `json:"has_translation"`
+	TranslationEstimateStatsDTO
+}
+
+type QAReportQueryDTO struct {
+	Severity string `form:"severity" binding:"omitempty,oneof=error warning"`
+	Rule     string `form:"rule"`
+}
+
+// TranslationJobQAReportDTO lists the QA findings of a job. The totals cover
+// every subtask that has finished so far; the subtasks list only those with
+// findings matching the query.
+type TranslationJobQAReportDTO struct {
+	JobID    string               `json:"job_id"`
+	Status   string               `json:"status"`
+	QAStatus *string              `json:"qa_status,omitempty"`
+	Errors   int                  `json:"errors"`
+	Warnings int                  `json:"warnings"`
+	ByRule   map[string]int       `json:"by_rule"`
+	Subtasks []SubtaskQAReportDTO `json:"subtasks"`
+}
+
+type SubtaskQAReportDTO struct {
+	SubtaskID  string       `json:"subtask_id"`
+	EntityType string       `json:"entity_type"`
+	EntityID   string       `json:"entity_id"`
+	Status     string       `json:"status"`
+	Findings   []qa.Finding `json:"findings"`
+}
+
+// TranslationJobEventDTO is streamed to clients following a job's progress.
+// Only the fields relevant to the event type are set.
+type TranslationJobEventDTO struct {
+	Type              string                      `json:"type"`
+	JobID             string                      `json:"job_id"`
+	Status            string                      `json:"status,omitempty"`
+	Progress          *int                        `json:"progress,omitempty"`
+	CompletedSubtasks *int                        `json:"completed_subtasks,omitempty"`
+	TotalSubtasks     *int                        `json:"total_subtasks,omitempty"`
+	ErrorMessage      *string                     `json:"error_message,omitempty"`
+	Subtask           *TranslationSubtaskEventDTO `json:"subtask,omitempty"`
+	Timestamp         time.Time                   `json:"timestamp"`
+}
+
+type TranslationSubtaskEventDTO struct {
+	ID           string  `json:"id"`
+	EntityType   string  `json:"entity_type"`
+	EntityID     string  `json:"entity_id"`
+	Status       string  `json:"status"`
+	Attempts     int     `json:"attempts"`
+	ErrorMessage *string `json:"error_message,omitempty"`
 }
@@ -1,6 +1,12 @@
 package job
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"simple-go/pkg/qa"
+
+	"github.com/google/uuid"
+)
 
 func MapTranslationJobToDTO(job TranslationJob) TranslationJobResponseDTO {
 	// Defensive zero-value check
@@ -11,13 +17,28 @@ func MapTranslationJobToDTO(job TranslationJob) TranslationJobResponseDTO {
 	return TranslationJobResponseDTO{
 		ID:                job.ID,
 		NovelID:           job.NovelID,
+		BatchID:           job.BatchID,
 		FromLang:          job.FromLang,
 		TargetLang:        job.TargetLang,
+		PivotLang:         job.PivotLang,
 		Status:            job.Status,
+		Mode:              job.Mode,
+		Priority:          job.Priority,
+		ChapterFrom:       job.ChapterFrom,
+		ChapterTo:         job.ChapterTo,
+		VolumeIDs:         job.VolumeIDs,
 		Progress:          job.Progress,
 		TotalSubtasks:     job.TotalSubtasks,
 		CompletedSubtasks: job.CompletedSubtasks,
 		ErrorMessage:      job.ErrorMessage,
+		QAStatus:          job.QAStatus,
+		QAErrors:          job.QAErrors,
+		QAWarnings:        job.QAWarnings,
+		PublishMode:       job.PublishMode,
+		PublishedBy:       job.PublishedBy,
+		PublishedAt:       job.PublishedAt,
+		CancelledBy:       job.CancelledBy,
+		CancelledAt:       job.CancelledAt,
 		StartedAt:         job.StartedAt,
 		FinishedAt:        job.FinishedAt,
 		CreatedAt:         job.CreatedAt,
@@ -41,10 +62,22 @@ func MapTranslationSubtaskToDTO(subtask TranslationSubtask) TranslationSubtaskRe
 		Priority:       subtask.Priority,
 		Status:         subtask.Status,
 		ErrorMessage:   subtask.ErrorMessage,
+		Attempts:       subtask.Attempts,
+		NextAttemptAt:  subtask.NextAttemptAt,
 		StartedAt:      subtask.StartedAt,
 		FinishedAt:     subtask.FinishedAt,
 		CreatedAt:      subtask.CreatedAt,
 		UpdatedAt:      subtask.UpdatedAt,
+
+		GlossaryHits:        subtask.GlossaryHits,
+		GlossaryMisses:      subtask.GlossaryMisses,
+		GlossaryMissedTerms: subtask.GlossaryMissedTerms,
+		MemorySegments:      subtask.MemorySegments,
+		MemoryExactHits:     subtask.MemoryExactHits,
+		MemoryFuzzyHits:     subtask.MemoryFuzzyHits,
+		QAErrors:            subtask.QAErrors,
+		QAWarnings:          subtask.QAWarnings,
+		Staged:              subtask.IsStaged(),
 	}
 }
 
@@ -60,8 +93,145 @@ func MapTranslationJobToDetailDTO(job TranslationJob) TranslationJobDetailRespon
 		subtasks = []TranslationSubtaskResponseDTO{} // return empty slice, not nil
 	}
 
+	staged := 0
+	for _, subtask := range job.Subtasks {
+		if subtask.IsStaged() {
+			staged++
+		}
+	}
+
 	return TranslationJobDetailResponseDTO{
 		TranslationJobResponseDTO: MapTranslationJobToDTO(job),
+		Memory:                    MapMemoryStatsToDTO(job.Subtasks),
+		StagedSubtasks:            staged,
 		Subtasks:                  subtasks,
 	}
 }
+
+// MapMemoryStatsToDTO sums the translation memory counters of subtasks
+func MapMemoryStatsToDTO(subtasks []TranslationSubtask) TranslationMemoryStatsDTO {
+	var stats TranslationMemoryStatsDTO
+	for _, subtask := range subtasks {
+		stats.Segments += subtask.MemorySegments
+		stats.ExactHits += subtask.MemoryExactHits
+		stats.FuzzyHits += subtask.MemoryFuzzyHits
+	}
+
+	if stats.Segments > 0 {
+		stats.HitRate = float64(stats.ExactHits) / float64(stats.Segments)
+		stats.FuzzyHitRate = float64(stats.FuzzyHits) / float64(stats.Segments)
+	}
+	return stats
+}
+
+// MapQAReportToDTO builds the QA report of a job from its subtasks. Empty
+// severity and rule match every finding.
+func MapQAReportToDTO(job TranslationJob, severity, rule string) TranslationJobQAReportDTO {
+	report := TranslationJobQAReportDTO{
+		JobID:    job.ID,
+		Status:   job.Status,
+		QAStatus: job.QAStatus,
+		ByRule:   make(map[string]int),
+		Subtasks: make([]SubtaskQAReportDTO, 0),
+	}
+
+	for _, subtask := range job.Subtasks {
+		report.Errors += subtask.QAErrors
+		report.Warnings += subtask.QAWarnings
+
+		findings := make([]qa.Finding, 0)
+		for _, f := range subtask.QAFindings {
+			report.ByRule[f.Rule]++
+			if (severity == "" || f.Severity == severity) && (rule == "" || f.Rule == rule) {
+				findings = append(findings, f)
+			}
+		}
+		if len(findings) == 0 {
+			continue
+		}
+
+		report.Subtasks = append(report.Subtasks, SubtaskQAReportDTO{
+			SubtaskID:  subtask.ID,
+			EntityType: subtask.EntityType,
+			EntityID:   subtask.EntityID,
+			Status:     subtask.Status,
+			Findings:   findings,
+		})
+	}
+
+	return report
+}
+
+// NewJobStateEvent describes the full state of a job
+func NewJobStateEvent(eventType string, job TranslationJob) TranslationJobEventDTO {
+	progress, completed, total := job.Progress, job.CompletedSubtasks, job.TotalSubtasks
+	return TranslationJobEventDTO{
+		Type:              eventType,
+		JobID:             job.ID,
+		Status:            job.Status,
+		Progress:          &progress,
+		CompletedSubtasks: &completed,
+		TotalSubtasks:     &total,
+		ErrorMessage:      job.ErrorMessage,
+		Timestamp:         time.Now(),
+	}
+}
+
+func NewJobProgressEvent(jobID string, progress, completedSubtasks, totalSubtasks int) TranslationJobEventDTO {
+	return TranslationJobEventDTO{
+		Type:              TranslationJobEventProgress,
+		JobID:             jobID,
+		Progress:          &progress,
+		CompletedSubtasks: &completedSubtasks,
+		TotalSubtasks:     &totalSubtasks,
+		Timestamp:         time.Now(),
+	}
+}
+
+// NewSubtaskEvent reports a subtask transition; status and errorMessage
+// override the possibly stale values on the subtask
+func NewSubtaskEvent(subtask TranslationSubtask, status string, errorMessage *string) TranslationJobEventDTO {
+	return TranslationJobEventDTO{
+		Type:  TranslationJobEventSubtask,
+		JobID: subtask.JobID,
+		Subtask: &TranslationSubtaskEventDTO{
+			ID:           subtask.ID,
+			EntityType:   subtask.EntityType,
+			EntityID:     subtask.EntityID,
+			Status:       status,
+			Attempts:     subtask.Attempts,
+			ErrorMessage: errorMessage,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// MapTranslationJobBatchToDTO adds up the progress of a batch's jobs. Jobs
+// that were reused by a later job or batch are no longer part of it.
+func MapTranslationJobBatchToDTO(batch TranslationJobBatch) TranslationJobBatchResponseDTO {
+	jobs := make([]TranslationJobResponseDTO, len(batch.Jobs))
+	total, completed := 0, 0
+	for i, j := range batch.Jobs {
+		jobs[i] = MapTranslationJobToDTO(j)
+		total += j.TotalSubtasks
+		completed += j.CompletedSubtasks
+	}
+
+	progress := 0
+	if total > 0 {
+		progress = completed * 100 / total
+	}
+
+	return TranslationJobBatchResponseDTO{
+		ID:                batch.ID,
+		NovelID:           batch.NovelID,
+		TargetLangs:       batch.TargetLangs,
+		Status:            BatchStatus(batch.Jobs),
+		Progress:          progress,
+		TotalSubtasks:     total,
+		CompletedSubtasks: completed,
+		Jobs:              jobs,
+		CreatedAt:         batch.CreatedAt,
+		UpdatedAt:         batch.UpdatedAt,
+	}
+}
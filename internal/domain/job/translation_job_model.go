@@ -12,20 +12,64 @@ const (
 	TranslationJobStatusInProgress = "IN_PROGRESS"
 	TranslationJobStatusCompleted  = "COMPLETED"
 	TranslationJobStatusFailed     = "FAILED"
+	TranslationJobStatusCancelled  = "CANCELLED"
+
+	// Job modes
+	TranslationJobModeFull        = "full"        // Translate every entity in scope
+	TranslationJobModeIncremental = "incremental" // Only missing or stale target translations
+
+	// Publish modes: when staged results become visible
+	TranslationJobPublishOnComplete = "on_complete" // When the job completes
+	TranslationJobPublishManual     = "manual"      // When a reviewer publishes the job
+
+	// Job priorities; higher priorities are scheduled first. Raising a job
+	// above the default requires the translation_job prioritize permission.
+	TranslationJobPriorityMin     = -5
+	TranslationJobPriorityDefault = 0
+	TranslationJobPriorityMax     = 5
+
+	// Event types streamed to job subscribers
+	TranslationJobEventSnapshot = "snapshot" // Full job state, sent on connect and after polling
+	TranslationJobEventCreated  = "created"
+	TranslationJobEventStatus   = "status"
+	TranslationJobEventProgress = "progress"
+	TranslationJobEventSubtask  = "subtask"
 )
 
+// IsTerminalJobStatus reports whether a job with this status will not change
+// again without user action
+func IsTerminalJobStatus(status string) bool {
+	return status == TranslationJobStatusCompleted ||
+		status == TranslationJobStatusFailed ||
+		status == TranslationJobStatusCancelled
+}
+
 type TranslationJob struct {
 	ID                string               `gorm:"type:uuid;primaryKey"`
-	NovelID           string               `gorm:"type:uuid;not null;index;uniqueIndex:idx_translation_job_novel_lang,priority:1"`
+	NovelID           string               `gorm:"type:uuid;not null;index;index:idx_translation_jobs_novel_lang,priority:1"`
+	BatchID           *string              `gorm:"type:uuid;index"`
 	FromLang          string               `gorm:"type:varchar(10);not null"`
-	TargetLang        string               `gorm:"type:varchar(10);not null;uniqueIndex:idx_translation_job_novel_lang,priority:2"`
+	TargetLang        string               `gorm:"type:varchar(10);not null;index:idx_translation_jobs_novel_lang,priority:2"`
+	PivotLang         *string              `gorm:"type:varchar(10)"`
 	Status            string               `gorm:"type:varchar(20);not null;default:'PENDING'"`
+	Mode              string               `gorm:"type:varchar(20);not null;default:'full'"`
+	Priority          int                  `gorm:"type:int;not null;default:0"`
+	PublishMode       string               `gorm:"type:varchar(20);not null;default:'on_complete'"`
+	ChapterFrom       *int                 `gorm:"type:int"`
+	ChapterTo         *int                 `gorm:"type:int"`
+	VolumeIDs         []string             `gorm:"type:jsonb;serializer:json"`
 	Progress          int                  `gorm:"type:int;not null;default:0"`
 	TotalSubtasks     int                  `gorm:"type:int;not null;default:0"`
 	CompletedSubtasks int                  `gorm:"type:int;not null;default:0"`
-	StagingPrefix     *string              `gorm:"type:text"`
+	PublishedBy       *string              `gorm:"type:uuid"`
+	PublishedAt       *time.Time           `gorm:"type:timestamp"`
 	ErrorMessage      *string              `gorm:"type:text"`
+	QAStatus          *string              `gorm:"type:varchar(10)"`
+	QAErrors          int                  `gorm:"type:int;not null;default:0"`
+	QAWarnings        int                  `gorm:"type:int;not null;default:0"`
 	CreatedBy         *string              `gorm:"type:uuid;index"`
+	CancelledBy       *string              `gorm:"type:uuid"`
+	CancelledAt       *time.Time           `gorm:"type:timestamp"`
 	StartedAt         *time.Time           `gorm:"type:timestamp"`
 	FinishedAt        *time.Time           `gorm:"type:timestamp"`
 	CreatedAt         time.Time            `gorm:"autoCreateTime"`
@@ -43,3 +87,12 @@ func (j *TranslationJob) BeforeCreate(tx *gorm.DB) error {
 func (TranslationJob) TableName() string {
 	return "translation_jobs"
 }
+
+// FairShareOwner is who the job shares scheduling turns with: the user who
+// created it, or else its novel
+func (j TranslationJob) FairShareOwner() string {
+	if j.CreatedBy != nil && *j.CreatedBy != "" {
+		return "user:" + *j.CreatedBy
+	}
+	return "novel:" + j.NovelID
+}
@@ -3,6 +3,8 @@ package job
 import (
 	"time"
 
+	"simple-go/pkg/qa"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,6 +14,9 @@ const (
 	TranslationSubtaskStatusInProgress = "IN_PROGRESS"
 	TranslationSubtaskStatusDone       = "DONE"
 	TranslationSubtaskStatusFailed     = "FAILED"
+	TranslationSubtaskStatusCancelled  = "CANCELLED"
+	// DEAD_LETTER marks a subtask that kept failing after all retry attempts
+	TranslationSubtaskStatusDeadLetter = "DEAD_LETTER"
 
 	// Entity types for subtasks
 	EntityTypeChapter = "chapter"
@@ -24,6 +29,9 @@ const (
 	PriorityChapter = 200 // Processed first (lowest priority number)
 )
 
+// TranslationSubtask translates one entity of a job. Until the job is
+// published its output is staged in ResultText as a StagedResult in JSON, so
+// that any API instance can publish it.
 type TranslationSubtask struct {
 	ID             string     `gorm:"type:uuid;primaryKey"`
 	JobID          string     `gorm:"type:uuid;not null;index;uniqueIndex:idx_translation_subtask_entity,priority:1"`
@@ -33,13 +41,64 @@ type TranslationSubtask struct {
 	Seq            int        `gorm:"type:int"`
 	Priority       int        `gorm:"type:int;not null;default:100"`
 	Status         string     `gorm:"type:varchar(20);not null;default:'PENDING'"`
-	ResultPath     *string    `gorm:"type:text"`
 	ResultText     *string    `gorm:"type:text"`
 	ErrorMessage   *string    `gorm:"type:text"`
+	Attempts       int        `gorm:"type:int;not null;default:0"`
+	NextAttemptAt  *time.Time `gorm:"type:timestamp;index"`
+	LeaseOwner     *string    `gorm:"type:varchar(100);index"`
+	LeaseExpiresAt *time.Time `gorm:"type:timestamp;index"`
+	HeartbeatAt    *time.Time `gorm:"type:timestamp"`
 	StartedAt      *time.Time `gorm:"type:timestamp"`
 	FinishedAt     *time.Time `gorm:"type:timestamp"`
 	CreatedAt      time.Time  `gorm:"autoCreateTime"`
 	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+
+	// Glossary terms found in the source that the output did / did not use
+	GlossaryHits        int      `gorm:"type:int;not null;default:0"`
+	GlossaryMisses      int      `gorm:"type:int;not null;default:0"`
+	GlossaryMissedTerms []string `gorm:"type:jsonb;serializer:json"`
+	// Translation memory use: segments looked up and how many matched
+	MemorySegments  int `gorm:"type:int;not null;default:0"`
+	MemoryExactHits int `gorm:"type:int;not null;default:0"`
+	MemoryFuzzyHits int `gorm:"type:int;not null;default:0"`
+	// Automated QA findings on the output
+	QAErrors   int          `gorm:"type:int;not null;default:0"`
+	QAWarnings int          `gorm:"type:int;not null;default:0"`
+	QAFindings []qa.Finding `gorm:"type:jsonb;serializer:json"`
+}
+
+// IsStaged reports whether the subtask holds a result that was not published
+// yet
+func (s TranslationSubtask) IsStaged() bool {
+	return s.Status == TranslationSubtaskStatusDone && s.ResultText != nil
+}
+
+// StagedResult is the translation of an entity waiting to be published.
+// Chapters use Title and Content; volumes and novels Title and Description.
+type StagedResult struct {
+	Title       string  `json:"title"`
+	Content     string  `json:"content,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// Pivot is the intermediate translation of a chapter that went through
+	// the job's pivot language and had no translation in it yet
+	Pivot *StagedResult `json:"pivot,omitempty"`
+}
+
+// SubtaskResult is recorded on a subtask when it finishes successfully
+type SubtaskResult struct {
+	ResultText          *string
+	GlossaryHits        int
+	GlossaryMissedTerms []string
+	Memory              MemoryStats
+	QAFindings          []qa.Finding
+}
+
+// MemoryStats counts translation memory lookups. Exact hits are reused
+// without calling the translator; fuzzy hits are passed to it as references.
+type MemoryStats struct {
+	Segments  int
+	ExactHits int
+	FuzzyHits int
 }
 
 func (s *TranslationSubtask) BeforeCreate(tx *gorm.DB) error {
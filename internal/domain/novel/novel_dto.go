@@ -34,6 +34,8 @@ type NovelResponseDTO struct {
 	Lang             string                 `json:"lang"`
 	Title            string                 `json:"title"`
 	Description      *string                `json:"description"`
+	SourceLang       *string                `json:"source_lang,omitempty"`
+	PivotLang        *string                `json:"pivot_lang,omitempty"`
 	Tags             []tag.UpdateTagDTO     `json:"tags,omitempty"`
 	Genres           []genre.UpdateGenreDTO `json:"genres,omitempty"`
 	CreatedAt        time.Time              `json:"created_at"`
@@ -55,3 +57,82 @@ type UpdateCoverMediaDTO struct {
 	FileBytes  []byte `json:"-"`
 	UploaderID string `json:"-"`
 }
+
+// EpubPreviewDTO is what importing an EPUB would create, reported without
+// saving anything
+type EpubPreviewDTO struct {
+	SourceType       string                 `json:"source_type"`
+	Title            string                 `json:"title"`
+	OriginalAuthor   string                 `json:"original_author"`
+	Description      string                 `json:"description"`
+	Publisher        string                 `json:"publisher"`
+	DeclaredLanguage string                 `json:"declared_language"`
+	DetectedLanguage string                 `json:"detected_language"`
+	Tags             []string               `json:"tags"`
+	HasCoverImage    bool                   `json:"has_cover_image"`
+	Volumes          []EpubPreviewVolumeDTO `json:"volumes"`
+	TotalVolumes     int                    `json:"total_volumes"`
+	TotalChapters    int                    `json:"total_chapters"`
+	TotalWords       int                    `json:"total_words"`
+	Warnings         []string               `json:"warnings"`
+}
+
+type EpubPreviewVolumeDTO struct {
+	Number    int                     `json:"number"`
+	Title     string                  `json:"title"`
+	IsVirtual bool                    `json:"is_virtual"`
+	WordCount int                     `json:"word_count"`
+	Chapters  []EpubPreviewChapterDTO `json:"chapters"`
+}
+
+type EpubPreviewChapterDTO struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	WordCount int    `json:"word_count"`
+}
+
+// EpubMergeResultDTO is the diff of merging an EPUB into an existing novel
+type EpubMergeResultDTO struct {
+	SourceType    string                `json:"source_type"`
+	UpdateContent bool                  `json:"update_content"`
+	AddedVolumes  []int                 `json:"added_volumes"`
+	Added         []EpubMergeChapterDTO `json:"added"`
+	// Updated lists chapters whose content was replaced; with update_content
+	// off they are listed in Changed instead and left as they are
+	Updated []EpubMergeChapterDTO `json:"updated"`
+	Changed []EpubMergeChapterDTO `json:"changed"`
+	// Conflicts lists chapters whose number is taken by a chapter with
+	// another title; they are not imported
+	Conflicts []EpubMergeChapterDTO `json:"conflicts"`
+	Unchanged int                   `json:"unchanged"`
+	// OutdatedTranslations and OutdatedAlignments list what was made from
+	// the text of updated chapters and is now marked outdated
+	OutdatedTranslations []EpubMergeOutdatedTranslationDTO `json:"outdated_translations"`
+	OutdatedAlignments   []EpubMergeOutdatedAlignmentDTO   `json:"outdated_alignments"`
+}
+
+type EpubMergeChapterDTO struct {
+	ChapterID string `json:"chapter_id,omitempty"`
+	Volume    int    `json:"volume"`
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	// ExistingTitle is the title of the chapter a conflict was found with
+	ExistingTitle string `json:"existing_title,omitempty"`
+}
+
+type EpubMergeOutdatedTranslationDTO struct {
+	ChapterID     string `json:"chapter_id"`
+	Volume        int    `json:"volume"`
+	Number        int    `json:"number"`
+	TranslationID string `json:"translation_id"`
+	Lang          string `json:"lang"`
+	Status        string `json:"status"`
+}
+
+type EpubMergeOutdatedAlignmentDTO struct {
+	ChapterID  string `json:"chapter_id"`
+	Volume     int    `json:"volume"`
+	Number     int    `json:"number"`
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+}
@@ -17,11 +17,15 @@ func MapNovelToDTO(n Novel, lang string) NovelResponseDTO {
 		selectedLang        string
 		selectedTitle       string
 		selectedDescription string
+		sourceLang          *string
+		pivotLang           *string
 	)
 	if selected != nil {
 		selectedLang = selected.Lang
 		selectedTitle = selected.Title
 		selectedDescription = *selected.Description
+		sourceLang = selected.SourceLang
+		pivotLang = selected.PivotLang
 	}
 
 	return NovelResponseDTO{
@@ -35,6 +39,8 @@ func MapNovelToDTO(n Novel, lang string) NovelResponseDTO {
 		Lang:             selectedLang,
 		Title:            selectedTitle,
 		Description:      &selectedDescription,
+		SourceLang:       sourceLang,
+		PivotLang:        pivotLang,
 		Tags:             tag.MapTagsToUpdateDTOs(n.Tags),
 		Genres:           genre.MapGenresToUpdateDTOs(n.Genres),
 		CreatedAt:        n.CreatedAt,
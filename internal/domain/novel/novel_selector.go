@@ -21,3 +21,15 @@ func SelectTranslation(translations []NovelTranslation, lang, originalLang strin
 
 	return &translations[0]
 }
+
+// PivotTranslation returns the translation in lang a pivot translation can
+// start from. Novel translations are not reviewed, so machine output,
+// which records the language it was translated from, does not qualify.
+func PivotTranslation(translations []NovelTranslation, lang string) *NovelTranslation {
+	for i := range translations {
+		if translations[i].Lang == lang && translations[i].SourceLang == nil {
+			return &translations[i]
+		}
+	}
+	return nil
+}
@@ -15,6 +15,11 @@ type NovelTranslation struct {
 	Description *string   `gorm:"type:text"`
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+	// Provenance of machine output: the language it was translated from and,
+	// for pivot translations, the intermediate language it went through
+	SourceLang *string `gorm:"type:varchar(10)"`
+	PivotLang  *string `gorm:"type:varchar(10)"`
 }
 
 func (nt *NovelTranslation) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,34 @@
+package quota
+
+import "time"
+
+// UsageDTO reports a user's translation usage against their limits. Limits
+// of -1 are unlimited.
+type UsageDTO struct {
+	Daily      CharacterUsageDTO `json:"daily"`
+	Monthly    CharacterUsageDTO `json:"monthly"`
+	ActiveJobs ActiveJobsDTO     `json:"active_jobs"`
+}
+
+type CharacterUsageDTO struct {
+	Used      int       `json:"used"`
+	Limit     int       `json:"limit"`
+	Remaining *int      `json:"remaining,omitempty"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+type ActiveJobsDTO struct {
+	Active int `json:"active"`
+	Limit  int `json:"limit"`
+}
+
+// NewCharacterUsageDTO reports used characters against limit for a window
+// ending at resetsAt
+func NewCharacterUsageDTO(used, limit int, resetsAt time.Time) CharacterUsageDTO {
+	dto := CharacterUsageDTO{Used: used, Limit: limit, ResetsAt: resetsAt}
+	if limit >= 0 {
+		remaining := max(limit-used, 0)
+		dto.Remaining = &remaining
+	}
+	return dto
+}
@@ -0,0 +1,133 @@
+package quota
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// Limits a translation job can exceed
+	LimitDailyCharacters   = "daily_characters"
+	LimitMonthlyCharacters = "monthly_characters"
+	LimitActiveJobs        = "active_jobs"
+
+	// Scopes of a limit
+	ScopeUser   = "user"
+	ScopeGlobal = "global"
+
+	// Unlimited disables a limit
+	Unlimited = -1
+)
+
+// Usage records the characters a translation job was charged when it was
+// created. The charges are kept apart from the jobs so that they outlive jobs
+// that are deleted or retried.
+type Usage struct {
+	ID         string    `gorm:"type:uuid;primaryKey"`
+	UserID     string    `gorm:"type:uuid;not null;index:idx_translation_usage_user_created,priority:1"`
+	JobID      string    `gorm:"type:uuid;not null;index"`
+	Characters int       `gorm:"type:int;not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index;index:idx_translation_usage_user_created,priority:2"`
+}
+
+func (u *Usage) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == "" {
+		u.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (Usage) TableName() string {
+	return "translation_usage"
+}
+
+// Limits caps the translation volume of a user, or of all users together.
+// A negative value disables that limit.
+type Limits struct {
+	DailyCharacters   int
+	MonthlyCharacters int
+	MaxActiveJobs     int
+}
+
+// Policy holds the configured limits. Users get the Default limits unless one
+// of their roles has its own; with several such roles the most generous value
+// of each limit applies. Global limits apply to all users together.
+type Policy struct {
+	Default Limits
+	Roles   map[string]Limits
+	Global  Limits
+}
+
+// ForRoles returns the limits of a user with the given roles
+func (p Policy) ForRoles(roles []string) Limits {
+	var limits *Limits
+	for _, name := range roles {
+		l, ok := p.Roles[name]
+		if !ok {
+			continue
+		}
+		if limits == nil {
+			limits = &l
+			continue
+		}
+		limits.DailyCharacters = generous(limits.DailyCharacters, l.DailyCharacters)
+		limits.MonthlyCharacters = generous(limits.MonthlyCharacters, l.MonthlyCharacters)
+		limits.MaxActiveJobs = generous(limits.MaxActiveJobs, l.MaxActiveJobs)
+	}
+
+	if limits == nil {
+		return p.Default
+	}
+	return *limits
+}
+
+func generous(a, b int) int {
+	if a < 0 || b < 0 {
+		return Unlimited
+	}
+	return max(a, b)
+}
+
+// DayStart and MonthStart return the start of the UTC day and month that t
+// falls in; character usage is counted over these windows
+func DayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ExceededError is returned when creating jobs would go over a limit
+type ExceededError struct {
+	Limit string
+	Scope string
+	Max   int
+	Used  int
+	// Requested is what the jobs would add: characters, or active jobs
+	Requested int
+}
+
+func (e *ExceededError) Error() string {
+	owner := "your"
+	if e.Scope == ScopeGlobal {
+		owner = "the global"
+	}
+
+	switch e.Limit {
+	case LimitActiveJobs:
+		if e.Requested > 1 {
+			return fmt.Sprintf("%d jobs requested but only %d of %s limit of %d active translation jobs are left", e.Requested, max(e.Max-e.Used, 0), owner, e.Max)
+		}
+		return fmt.Sprintf("%s limit of %d active translation jobs is reached", owner, e.Max)
+	case LimitMonthlyCharacters:
+		return fmt.Sprintf("job needs %d characters but only %d of %s monthly quota of %d are left", e.Requested, max(e.Max-e.Used, 0), owner, e.Max)
+	default:
+		return fmt.Sprintf("job needs %d characters but only %d of %s daily quota of %d are left", e.Requested, max(e.Max-e.Used, 0), owner, e.Max)
+	}
+}
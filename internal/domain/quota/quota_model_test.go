@@ -0,0 +1,41 @@
+package quota
+
+import "testing"
+
+func TestPolicyForRoles(t *testing.T) {
+	policy := Policy{
+		Default: Limits{DailyCharacters: 100, MonthlyCharacters: 1000, MaxActiveJobs: 1},
+		Roles: map[string]Limits{
+			"author":     {DailyCharacters: 500, MonthlyCharacters: 2000, MaxActiveJobs: 2},
+			"translator": {DailyCharacters: 300, MonthlyCharacters: Unlimited, MaxActiveJobs: 5},
+			"admin":      {DailyCharacters: Unlimited, MonthlyCharacters: Unlimited, MaxActiveJobs: Unlimited},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		roles []string
+		want  Limits
+	}{
+		{"no roles", nil, policy.Default},
+		{"roles without limits", []string{"user"}, policy.Default},
+		{"one role", []string{"user", "author"}, policy.Roles["author"]},
+		{
+			"most generous value of each limit",
+			[]string{"author", "translator"},
+			Limits{DailyCharacters: 500, MonthlyCharacters: Unlimited, MaxActiveJobs: 5},
+		},
+		{
+			"unlimited wins",
+			[]string{"author", "admin"},
+			Limits{DailyCharacters: Unlimited, MonthlyCharacters: Unlimited, MaxActiveJobs: Unlimited},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.ForRoles(tt.roles); got != tt.want {
+				t.Errorf("ForRoles(%v) = %+v, want %+v", tt.roles, got, tt.want)
+			}
+		})
+	}
+}
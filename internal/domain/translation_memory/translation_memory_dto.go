@@ -0,0 +1,36 @@
+package translationmemory
+
+import "time"
+
+type LookupDTO struct {
+	SourceLang string `form:"source_lang" binding:"required,min=2,max=10"`
+	TargetLang string `form:"target_lang" binding:"required,min=2,max=10"`
+	Text       string `form:"text" binding:"required"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+// ImportDTO learns the aligned chapter translations of a novel. SourceLang
+// defaults to the original language of each volume.
+type ImportDTO struct {
+	NovelID    string  `json:"novel_id" binding:"required,uuid"`
+	SourceLang *string `json:"source_lang" binding:"omitempty,min=2,max=10"`
+	TargetLang string  `json:"target_lang" binding:"required,min=2,max=10"`
+}
+
+type MatchDTO struct {
+	ID         string     `json:"id"`
+	SourceText string     `json:"source_text"`
+	TargetText string     `json:"target_text"`
+	Similarity float64    `json:"similarity"`
+	Exact      bool       `json:"exact"`
+	UsageCount int        `json:"usage_count"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type ImportResultDTO struct {
+	Chapters int `json:"chapters"`
+	Segments int `json:"segments"`
+	// Skipped counts chapters whose source and target could not be aligned
+	Skipped int `json:"skipped"`
+}
@@ -0,0 +1,30 @@
+package translationmemory
+
+// MapEntryToMatchDTO converts an exact TranslationMemoryEntry hit to MatchDTO
+func MapEntryToMatchDTO(e TranslationMemoryEntry) MatchDTO {
+	return MatchDTO{
+		ID:         e.ID,
+		SourceText: e.SourceText,
+		TargetText: e.TargetText,
+		Similarity: 1,
+		Exact:      true,
+		UsageCount: e.UsageCount,
+		LastUsedAt: e.LastUsedAt,
+		UpdatedAt:  e.UpdatedAt,
+	}
+}
+
+// MapFuzzyMatchesToDTOs converts fuzzy matches to MatchDTOs
+func MapFuzzyMatchesToDTOs(matches []FuzzyMatch) []MatchDTO {
+	if matches == nil {
+		return []MatchDTO{}
+	}
+
+	dtos := make([]MatchDTO, len(matches))
+	for i, m := range matches {
+		dtos[i] = MapEntryToMatchDTO(m.TranslationMemoryEntry)
+		dtos[i].Similarity = m.Similarity
+		dtos[i].Exact = false
+	}
+	return dtos
+}
@@ -0,0 +1,59 @@
+package translationmemory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TranslationMemoryEntry is an accepted translation of one source segment.
+// Entries are shared by all novels and keyed by the normalized segment and
+// language pair.
+type TranslationMemoryEntry struct {
+	ID         string     `gorm:"type:uuid;primaryKey"`
+	SourceLang string     `gorm:"type:varchar(10);not null;uniqueIndex:idx_translation_memory_segment,priority:1"`
+	TargetLang string     `gorm:"type:varchar(10);not null;uniqueIndex:idx_translation_memory_segment,priority:2"`
+	SourceHash string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_translation_memory_segment,priority:3"`
+	SourceText string     `gorm:"type:text;not null"`
+	TargetText string     `gorm:"type:text;not null"`
+	NovelID    *string    `gorm:"type:uuid;index"` // Where the entry was learned from
+	ChapterID  *string    `gorm:"type:uuid"`
+	UsageCount int        `gorm:"type:int;not null;default:0"`
+	LastUsedAt *time.Time `gorm:"type:timestamp"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (e *TranslationMemoryEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (TranslationMemoryEntry) TableName() string {
+	return "translation_memory_entries"
+}
+
+// FuzzyMatch is an entry similar to a looked up segment. Similarity is the
+// pg_trgm similarity between 0 and 1.
+type FuzzyMatch struct {
+	TranslationMemoryEntry
+	Similarity float64
+}
+
+// Normalize collapses whitespace so that segments differing only in
+// formatting share an entry
+func Normalize(segment string) string {
+	return strings.Join(strings.Fields(segment), " ")
+}
+
+// Hash returns the lookup key of a normalized segment
+func Hash(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
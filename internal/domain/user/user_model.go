@@ -2,6 +2,7 @@ package user
 
 import (
 	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/quota"
 	"simple-go/internal/domain/role"
 	"time"
 
@@ -67,6 +68,7 @@ type UserProfileResponse struct {
 	Bio         *string             `json:"bio"`
 	Status      string              `json:"status"`
 	Permissions map[string][]string `json:"permissions"`
+	Quota       *quota.UsageDTO     `json:"quota,omitempty"`
 	CreatedAt   time.Time           `json:"created_at"`
 	UpdatedAt   time.Time           `json:"updated_at"`
 }
@@ -28,6 +28,8 @@ type VolumeResponseDTO struct {
 	Lang             string  `json:"lang"`
 	Title            string  `json:"title"`
 	Description      *string `json:"description"`
+	SourceLang       *string `json:"source_lang,omitempty"`
+	PivotLang        *string `json:"pivot_lang,omitempty"`
 	IsVirtual        bool    `json:"is_virtual"`
 
 	Chapters []chapter.ChapterResponseDTO `json:"chapters,omitempty"`
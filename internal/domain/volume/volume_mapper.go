@@ -19,6 +19,8 @@ func MapVolumeToDTO(v Volume, lang string) VolumeResponseDTO {
 		Lang:             selected.Lang,
 		Title:            selected.Title,
 		Description:      selected.Description,
+		SourceLang:       selected.SourceLang,
+		PivotLang:        selected.PivotLang,
 		IsVirtual:        v.IsVirtual,
 		Chapters:         mapChaptersToDTO(v.Chapters, lang),
 	}
@@ -21,3 +21,15 @@ func SelectTranslation(translations []VolumeTranslation, lang, originalLang stri
 
 	return &translations[0]
 }
+
+// PivotTranslation returns the translation in lang a pivot translation can
+// start from. Volume translations are not reviewed, so machine output,
+// which records the language it was translated from, does not qualify.
+func PivotTranslation(translations []VolumeTranslation, lang string) *VolumeTranslation {
+	for i := range translations {
+		if translations[i].Lang == lang && translations[i].SourceLang == nil {
+			return &translations[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import "time"
+
+type CreateWebhookDTO struct {
+	URL string `json:"url" binding:"required,url"`
+	// Secret signs deliveries; one is generated when omitted
+	Secret     *string  `json:"secret" binding:"omitempty,min=16"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=job.created job.progress job.completed job.failed"`
+}
+
+type UpdateWebhookDTO struct {
+	URL        *string  `json:"url" binding:"omitempty,url"`
+	Secret     *string  `json:"secret" binding:"omitempty,min=16"`
+	EventTypes []string `json:"event_types" binding:"omitempty,min=1,dive,oneof=job.created job.progress job.completed job.failed"`
+	Active     *bool    `json:"active"`
+}
+
+type WebhookResponseDTO struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+	// Secret is only returned when the subscription is created
+	Secret    *string   `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryResponseDTO struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	RedeliveryOf   *string    `json:"redelivery_of,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// EventPayloadDTO is the JSON body POSTed to subscribers
+type EventPayloadDTO struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
@@ -0,0 +1,60 @@
+package webhook
+
+// MapWebhookToDTO converts a WebhookSubscription to WebhookResponseDTO without its secret
+func MapWebhookToDTO(w WebhookSubscription) WebhookResponseDTO {
+	eventTypes := w.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	return WebhookResponseDTO{
+		ID:         w.ID,
+		URL:        w.URL,
+		EventTypes: eventTypes,
+		Active:     w.Active,
+		CreatedAt:  w.CreatedAt,
+		UpdatedAt:  w.UpdatedAt,
+	}
+}
+
+// MapWebhooksToDTOs converts a slice of WebhookSubscription to WebhookResponseDTO
+func MapWebhooksToDTOs(webhooks []WebhookSubscription) []WebhookResponseDTO {
+	if webhooks == nil {
+		return []WebhookResponseDTO{}
+	}
+
+	dtos := make([]WebhookResponseDTO, len(webhooks))
+	for i, w := range webhooks {
+		dtos[i] = MapWebhookToDTO(w)
+	}
+	return dtos
+}
+
+func MapDeliveryToDTO(d WebhookDelivery) WebhookDeliveryResponseDTO {
+	return WebhookDeliveryResponseDTO{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		RedeliveryOf:   d.RedeliveryOf,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
+
+func MapDeliveriesToDTOs(deliveries []WebhookDelivery) []WebhookDeliveryResponseDTO {
+	if deliveries == nil {
+		return []WebhookDeliveryResponseDTO{}
+	}
+
+	dtos := make([]WebhookDeliveryResponseDTO, len(deliveries))
+	for i, d := range deliveries {
+		dtos[i] = MapDeliveryToDTO(d)
+	}
+	return dtos
+}
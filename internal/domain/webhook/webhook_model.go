@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// Event types a subscription can listen to
+	EventJobCreated   = "job.created"
+	EventJobProgress  = "job.progress"
+	EventJobCompleted = "job.completed"
+	EventJobFailed    = "job.failed"
+
+	DeliveryStatusPending   = "PENDING"
+	DeliveryStatusSucceeded = "SUCCEEDED"
+	DeliveryStatusFailed    = "FAILED"
+)
+
+// EventTypes lists every event type that can be subscribed to
+var EventTypes = []string{
+	EventJobCreated,
+	EventJobProgress,
+	EventJobCompleted,
+	EventJobFailed,
+}
+
+type WebhookSubscription struct {
+	ID         string    `gorm:"type:uuid;primaryKey"`
+	URL        string    `gorm:"type:text;not null"`
+	Secret     string    `gorm:"type:varchar(255);not null"`
+	EventTypes []string  `gorm:"type:jsonb;not null;serializer:json"`
+	Active     bool      `gorm:"type:boolean;not null;default:true"`
+	CreatedBy  *string   `gorm:"type:uuid;index"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+
+	Deliveries []WebhookDelivery `gorm:"foreignKey:SubscriptionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (w *WebhookSubscription) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Subscribes reports whether the subscription listens to eventType
+func (w WebhookSubscription) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent (or to be sent) to one subscription
+type WebhookDelivery struct {
+	ID             string     `gorm:"type:uuid;primaryKey"`
+	SubscriptionID string     `gorm:"type:uuid;not null;index"`
+	EventType      string     `gorm:"type:varchar(50);not null"`
+	Payload        string     `gorm:"type:text;not null"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	Attempts       int        `gorm:"type:int;not null;default:0"`
+	NextAttemptAt  *time.Time `gorm:"type:timestamp;index"`
+	LastStatusCode *int       `gorm:"type:int"`
+	LastError      *string    `gorm:"type:text"`
+	RedeliveryOf   *string    `gorm:"type:uuid"`
+	DeliveredAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"simple-go/internal/domain/chapter"
@@ -53,8 +54,11 @@ func (h *ChapterHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 	lang := c.DefaultQuery("lang", "")
 
+	// Translations under review are only shown to reviewers
+	canReview := middleware.HasPermission(c, "chapter_translation", "review")
+
 	// Use VolumeService to get chapter with cross-volume navigation
-	result, err := h.volumeService.GetChapterWithCrossVolumeNavigation(c.Request.Context(), id, lang)
+	result, err := h.volumeService.GetChapterWithCrossVolumeNavigation(c.Request.Context(), id, lang, canReview)
 	if err != nil {
 		response.Error(c, http.StatusNotFound, "Failed to retrieve chapter", err)
 		return
@@ -63,6 +67,31 @@ func (h *ChapterHandler) GetByID(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Chapter retrieved successfully", result)
 }
 
+func (h *ChapterHandler) GetBilingual(c *gin.Context) {
+	id := c.Param("id")
+
+	var query chapter.BilingualQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", response.MapValidationErrors(err, chapter.BilingualQueryDTO{}))
+		return
+	}
+
+	// Translations under review are only shown to reviewers
+	canReview := middleware.HasPermission(c, "chapter_translation", "review")
+
+	result, err := h.chapterService.GetBilingual(c.Request.Context(), id, query, canReview)
+	if errors.Is(err, service.ErrSameBilingualLanguage) {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to retrieve bilingual chapter: %v", err))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Failed to retrieve bilingual chapter: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Bilingual chapter retrieved successfully", result)
+}
+
 func (h *ChapterHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
@@ -108,3 +137,126 @@ func (h *ChapterHandler) DeleteTranslation(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Translation deleted successfully", nil)
 }
+
+func (h *ChapterHandler) GetReviewQueue(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var query chapter.ReviewQueueQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", response.MapValidationErrors(err, chapter.ReviewQueueQueryDTO{}))
+		return
+	}
+
+	page, limit := paginationParams(c)
+	translations, total, err := h.chapterService.GetReviewQueue(c.Request.Context(), userID, query, limit, (page-1)*limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve review queue: %v", err))
+		return
+	}
+
+	response.PaginatedSuccess(c, http.StatusOK, "Review queue retrieved successfully", translations, newPagination(page, limit, total))
+}
+
+func (h *ChapterHandler) AssignReviewer(c *gin.Context) {
+	translationID := c.Param("id")
+
+	var dto chapter.AssignReviewerDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, chapter.AssignReviewerDTO{}))
+		return
+	}
+
+	result, err := h.chapterService.AssignReviewer(c.Request.Context(), translationID, dto)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to assign reviewer: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Reviewer assigned successfully", result)
+}
+
+// ReviewTranslation lets the assigned reviewer decide on a translation
+func (h *ChapterHandler) ReviewTranslation(c *gin.Context) {
+	h.reviewTranslation(c, false)
+}
+
+// OverrideReview lets users who assign reviewers decide in their place
+func (h *ChapterHandler) OverrideReview(c *gin.Context) {
+	h.reviewTranslation(c, true)
+}
+
+func (h *ChapterHandler) reviewTranslation(c *gin.Context, override bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	translationID := c.Param("id")
+
+	var dto chapter.ReviewChapterTranslationDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, chapter.ReviewChapterTranslationDTO{}))
+		return
+	}
+
+	result, err := h.chapterService.ReviewTranslation(c.Request.Context(), translationID, userID, override, dto)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to review translation: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Translation reviewed successfully", result)
+}
+
+func (h *ChapterHandler) GetRevisions(c *gin.Context) {
+	var query chapter.RevisionQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", response.MapValidationErrors(err, chapter.RevisionQueryDTO{}))
+		return
+	}
+
+	page, limit := paginationParams(c)
+	revisions, total, err := h.chapterService.GetRevisions(c.Request.Context(), query, limit, (page-1)*limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve revisions: %v", err))
+		return
+	}
+
+	response.PaginatedSuccess(c, http.StatusOK, "Revisions retrieved successfully", revisions, newPagination(page, limit, total))
+}
+
+func (h *ChapterHandler) GetRevision(c *gin.Context) {
+	result, err := h.chapterService.GetRevision(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Failed to retrieve revision: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Revision retrieved successfully", result)
+}
+
+func (h *ChapterHandler) ReviewRevision(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var dto chapter.ReviewChapterTranslationDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, chapter.ReviewChapterTranslationDTO{}))
+		return
+	}
+
+	result, err := h.chapterService.ReviewRevision(c.Request.Context(), c.Param("id"), userID, dto)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to review revision: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Revision reviewed successfully", result)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"simple-go/internal/middleware"
+	"simple-go/internal/service"
+	"simple-go/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EpubImportHandler struct {
+	importService *service.EpubImportService
+}
+
+func NewEpubImportHandler(importService *service.EpubImportService) *EpubImportHandler {
+	return &EpubImportHandler{
+		importService: importService,
+	}
+}
+
+// Create queues an uploaded EPUB for import by the worker. Poll GetByID for
+// its progress.
+func (h *EpubImportHandler) Create(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	fileBytes, fileName, ok := readEpubFile(c)
+	if !ok {
+		return
+	}
+
+	created, err := h.importService.CreateImport(c.Request.Context(), userID, fileName, fileBytes)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to create epub import: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "EPUB import queued successfully", created)
+}
+
+// Merge queues a newer copy of the novel's EPUB to be merged into it, adding
+// new volumes and chapters and, with update_content set, replacing changed
+// text. Poll GetByID for its progress and diff.
+func (h *EpubImportHandler) Merge(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	updateContent, err := strconv.ParseBool(c.DefaultPostForm("update_content", "false"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid update_content in form data")
+		return
+	}
+
+	fileBytes, fileName, ok := readEpubFile(c)
+	if !ok {
+		return
+	}
+
+	created, err := h.importService.CreateMerge(c.Request.Context(), userID, c.Param("id"), fileName, fileBytes, updateContent)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to create epub merge: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "EPUB merge queued successfully", created)
+}
+
+// GetByID returns an import's progress to the user who uploaded it
+func (h *EpubImportHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	result, err := h.importService.GetByID(c.Request.Context(), id, userID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("EPUB import not found: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "EPUB import retrieved successfully", result)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"simple-go/internal/domain/glossary"
+	"simple-go/internal/middleware"
+	"simple-go/internal/service"
+	"simple-go/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GlossaryHandler struct {
+	glossaryService *service.GlossaryService
+}
+
+func NewGlossaryHandler(glossaryService *service.GlossaryService) *GlossaryHandler {
+	return &GlossaryHandler{
+		glossaryService: glossaryService,
+	}
+}
+
+// GetByNovelID lists the glossary of a novel, optionally filtered by
+// source_lang and target_lang
+func (h *GlossaryHandler) GetByNovelID(c *gin.Context) {
+	novelID := c.Param("id")
+	page, limit := paginationParams(c)
+
+	terms, total, err := h.glossaryService.GetByNovelID(
+		c.Request.Context(),
+		novelID,
+		c.Query("source_lang"),
+		c.Query("target_lang"),
+		limit,
+		(page-1)*limit,
+	)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Failed to retrieve glossary: %v", err))
+		return
+	}
+
+	response.PaginatedSuccess(c, http.StatusOK, "Glossary retrieved successfully", terms, newPagination(page, limit, total))
+}
+
+func (h *GlossaryHandler) Create(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req glossary.CreateGlossaryTermDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, glossary.CreateGlossaryTermDTO{}))
+		return
+	}
+
+	created, err := h.glossaryService.Create(c.Request.Context(), c.Param("id"), userID, req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to create glossary term: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Glossary term created successfully", created)
+}
+
+// Import adds the terms of an uploaded CSV file (source_term,target_term[,category[,note]])
+// to the glossary of the language pair given in the form
+func (h *GlossaryHandler) Import(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Missing file in form data")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to open glossary file")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.glossaryService.ImportCSV(
+		c.Request.Context(),
+		c.Param("id"),
+		userID,
+		c.PostForm("source_lang"),
+		c.PostForm("target_lang"),
+		file,
+	)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to import glossary: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Glossary imported successfully", result)
+}
+
+func (h *GlossaryHandler) Update(c *gin.Context) {
+	var req glossary.UpdateGlossaryTermDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, glossary.UpdateGlossaryTermDTO{}))
+		return
+	}
+
+	updated, err := h.glossaryService.Update(c.Request.Context(), c.Param("id"), c.Param("term_id"), req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to update glossary term: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Glossary term updated successfully", updated)
+}
+
+func (h *GlossaryHandler) Delete(c *gin.Context) {
+	if err := h.glossaryService.Delete(c.Request.Context(), c.Param("id"), c.Param("term_id")); err != nil {
+		response.Error(c, http.StatusNotFound, "Failed to delete glossary term", err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Glossary term deleted successfully", nil)
+}
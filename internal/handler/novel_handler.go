@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"simple-go/internal/domain/novel"
 	"simple-go/internal/middleware"
 	"simple-go/internal/service"
+	"simple-go/pkg/epub/transformer"
 	"simple-go/pkg/response"
 	"strconv"
 
@@ -244,34 +247,53 @@ func (h *NovelHandler) GetNovelVolumes(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Novel volumes retrieved successfully", volumes)
 }
 
-func (h *NovelHandler) UploadEpub(c *gin.Context) {
-	userID, exists := middleware.GetUserID(c)
-	if !exists {
-		response.Error(c, http.StatusUnauthorized, "Unauthorized")
-		return
-	}
+// ExportEpub streams a novel as an EPUB 3 file. Chapters without an approved
+// translation in the requested language are exported in the original one;
+// their number is reported in the X-Epub-Fallback-Chapters header. Clients
+// holding the current ETag get 304 Not Modified.
+func (h *NovelHandler) ExportEpub(c *gin.Context) {
+	id := c.Param("id")
+	lang := c.DefaultQuery("lang", "")
 
-	fileHeader, err := c.FormFile("epub_file")
+	export, err := h.novelService.ExportEpub(c.Request.Context(), id, lang)
 	if err != nil {
-		response.Error(c, http.StatusBadRequest, "Missing epub_file in form data")
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNovelNotFound) || errors.Is(err, service.ErrExportUnavailable) {
+			status = http.StatusNotFound
+		}
+		response.Error(c, status, fmt.Sprintf("Failed to export novel: %v", err))
 		return
 	}
 
-	file, err := fileHeader.Open()
-	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to open epub file")
+	c.Header("ETag", export.ETag)
+	if c.GetHeader("If-None-Match") == export.ETag {
+		c.Status(http.StatusNotModified)
 		return
 	}
-	defer file.Close()
 
-	fileBytes, err := io.ReadAll(file)
-	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Failed to read epub file")
+	c.Header("Content-Type", "application/epub+zip")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.FileName}))
+	c.Header("Content-Language", export.Lang)
+	c.Header("X-Epub-Fallback-Chapters", strconv.Itoa(export.FallbackChapters))
+	if err := h.novelService.WriteEpub(c.Request.Context(), c.Writer, export); err != nil && !c.Writer.Written() {
+		// Nothing was sent yet; a failure once the book has started only
+		// cuts the download short
+		for _, name := range []string{"ETag", "Content-Type", "Content-Disposition", "Content-Language", "X-Epub-Fallback-Chapters"} {
+			c.Writer.Header().Del(name)
+		}
+		response.Error(c, http.StatusInternalServerError, fmt.Sprintf("Failed to export novel: %v", err))
+	}
+}
+
+func (h *NovelHandler) UploadEpub(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	if len(fileBytes) == 0 {
-		response.Error(c, http.StatusBadRequest, "Epub file is empty")
+	fileBytes, _, ok := readEpubFile(c)
+	if !ok {
 		return
 	}
 
@@ -311,3 +333,60 @@ func (h *NovelHandler) UploadEpub(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "EPUB file parsed successfully. Check console for detailed output.", responseData)
 }
+
+// PreviewEpub parses an uploaded EPUB without saving it, so the result can be
+// checked before importing it with UploadEpub
+func (h *NovelHandler) PreviewEpub(c *gin.Context) {
+	fileBytes, _, ok := readEpubFile(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.novelService.PreviewEpubUpload(c.Request.Context(), fileBytes)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if invalidEpub(err) {
+			status = http.StatusBadRequest
+		}
+		response.Error(c, status, fmt.Sprintf("Failed to parse epub file: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "EPUB file previewed successfully", result)
+}
+
+// invalidEpub reports whether err is due to the uploaded file rather than the
+// server
+func invalidEpub(err error) bool {
+	return errors.Is(err, service.ErrInvalidEpub) || errors.Is(err, transformer.ErrUnsupportedSource)
+}
+
+// readEpubFile reads the epub_file form field and returns it with its file
+// name, writing the error response itself when it is missing or empty
+func readEpubFile(c *gin.Context) ([]byte, string, bool) {
+	fileHeader, err := c.FormFile("epub_file")
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Missing epub_file in form data")
+		return nil, "", false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to open epub file")
+		return nil, "", false
+	}
+	defer file.Close()
+
+	fileBytes, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to read epub file")
+		return nil, "", false
+	}
+
+	if len(fileBytes) == 0 {
+		response.Error(c, http.StatusBadRequest, "Epub file is empty")
+		return nil, "", false
+	}
+
+	return fileBytes, fileHeader.Filename, true
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"strconv"
+
+	"simple-go/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paginationParams reads the page and limit query parameters, defaulting to
+// the first page of 10
+func paginationParams(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return page, limit
+}
+
+func newPagination(page, limit int, total int64) response.Pagination {
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return response.Pagination{
+		CurrentPage: page,
+		Limit:       limit,
+		Total:       total,
+		TotalPages:  totalPages,
+	}
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/quota"
 	"simple-go/internal/middleware"
 	"simple-go/internal/service"
 	"simple-go/pkg/response"
@@ -37,7 +41,21 @@ func (h *TranslationJobHandler) CreateTranslationJob(c *gin.Context) {
 		return
 	}
 
+	if req.Priority > job.TranslationJobPriorityDefault && !middleware.HasPermission(c, "translation_job", "prioritize") {
+		response.Error(c, http.StatusForbidden, "Raising the priority of a translation job is not allowed")
+		return
+	}
+
 	createdJob, err := h.jobService.CreateTranslationJob(c.Request.Context(), userID, req)
+	var exceeded *quota.ExceededError
+	if errors.As(err, &exceeded) {
+		quotaExceeded(c, exceeded)
+		return
+	}
+	if jobConflict(err) {
+		response.Error(c, http.StatusConflict, fmt.Sprintf("Failed to create translation job: %v", err))
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to create translation job: %v", err))
 		return
@@ -46,6 +64,124 @@ func (h *TranslationJobHandler) CreateTranslationJob(c *gin.Context) {
 	response.Success(c, http.StatusCreated, "Translation job created successfully", createdJob)
 }
 
+// CreateTranslationJobBatch creates one translation job per target language
+func (h *TranslationJobHandler) CreateTranslationJobBatch(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req job.CreateTranslationJobBatchDTO
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, job.CreateTranslationJobBatchDTO{}))
+		return
+	}
+
+	if req.Priority > job.TranslationJobPriorityDefault && !middleware.HasPermission(c, "translation_job", "prioritize") {
+		response.Error(c, http.StatusForbidden, "Raising the priority of a translation job is not allowed")
+		return
+	}
+
+	batch, err := h.jobService.CreateTranslationJobBatch(c.Request.Context(), userID, req)
+	var exceeded *quota.ExceededError
+	if errors.As(err, &exceeded) {
+		quotaExceeded(c, exceeded)
+		return
+	}
+	if jobConflict(err) {
+		response.Error(c, http.StatusConflict, fmt.Sprintf("Failed to create translation job batch: %v", err))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to create translation job batch: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Translation job batch created successfully", batch)
+}
+
+// GetBatchByID retrieves a batch of translation jobs with its aggregated progress
+func (h *TranslationJobHandler) GetBatchByID(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.jobService.GetBatchByID(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Translation job batch not found: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Translation job batch retrieved successfully", result)
+}
+
+// jobConflict reports whether err is due to another job for the same novel
+// and target language
+func jobConflict(err error) bool {
+	return errors.Is(err, service.ErrActiveJobExists) || errors.Is(err, service.ErrUnpublishedResults)
+}
+
+// quotaExceeded responds with the error code of the exceeded limit
+func quotaExceeded(c *gin.Context, err *quota.ExceededError) {
+	code := response.ErrCodeQuotaDailyExceeded
+	switch err.Limit {
+	case quota.LimitMonthlyCharacters:
+		code = response.ErrCodeQuotaMonthlyExceeded
+	case quota.LimitActiveJobs:
+		code = response.ErrCodeQuotaActiveJobsExceeded
+	}
+
+	response.ErrorWithCode(c, http.StatusTooManyRequests, code, fmt.Sprintf("Translation quota exceeded: %v", err), gin.H{
+		"limit":     err.Limit,
+		"scope":     err.Scope,
+		"max":       err.Max,
+		"used":      err.Used,
+		"requested": err.Requested,
+	})
+}
+
+// EstimateTranslationJob previews the subtasks and source size of a job
+// without creating it
+func (h *TranslationJobHandler) EstimateTranslationJob(c *gin.Context) {
+	var req job.CreateTranslationJobDTO
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, job.CreateTranslationJobDTO{}))
+		return
+	}
+
+	estimate, err := h.jobService.EstimateTranslationJob(c.Request.Context(), req)
+	if jobConflict(err) {
+		response.Error(c, http.StatusConflict, fmt.Sprintf("Failed to estimate translation job: %v", err))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to estimate translation job: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Translation job estimated successfully", estimate)
+}
+
+// SetPriority changes the scheduling priority of a job
+func (h *TranslationJobHandler) SetPriority(c *gin.Context) {
+	id := c.Param("id")
+
+	var req job.UpdateTranslationJobPriorityDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, job.UpdateTranslationJobPriorityDTO{}))
+		return
+	}
+
+	updated, err := h.jobService.SetPriority(c.Request.Context(), id, *req.Priority)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to update translation job priority: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Translation job priority updated successfully", updated)
+}
+
 // GetJobByID retrieves a translation job by ID with all subtasks
 func (h *TranslationJobHandler) GetJobByID(c *gin.Context) {
 	id := c.Param("id")
@@ -59,6 +195,25 @@ func (h *TranslationJobHandler) GetJobByID(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Translation job retrieved successfully", result)
 }
 
+// GetQAReport returns the automated QA findings of a job
+func (h *TranslationJobHandler) GetQAReport(c *gin.Context) {
+	id := c.Param("id")
+
+	var query job.QAReportQueryDTO
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", response.MapValidationErrors(err, job.QAReportQueryDTO{}))
+		return
+	}
+
+	result, err := h.jobService.GetQAReport(c.Request.Context(), id, query)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Failed to retrieve QA report: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "QA report retrieved successfully", result)
+}
+
 // GetAllJobs retrieves all translation jobs with pagination and optional status filter
 func (h *TranslationJobHandler) GetAllJobs(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -121,9 +276,15 @@ func (h *TranslationJobHandler) GetJobsByNovelID(c *gin.Context) {
 
 // CancelJob cancels a pending or in-progress translation job
 func (h *TranslationJobHandler) CancelJob(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	id := c.Param("id")
 
-	err := h.jobService.CancelJob(c.Request.Context(), id)
+	err := h.jobService.CancelJob(c.Request.Context(), id, userID)
 	if err != nil {
 		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to cancel translation job: %v", err))
 		return
@@ -131,3 +292,170 @@ func (h *TranslationJobHandler) CancelJob(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Translation job cancelled successfully", nil)
 }
+
+// RetryJob re-queues the failed subtasks of a finished translation job
+func (h *TranslationJobHandler) RetryJob(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+
+	var req job.RetryTranslationJobDTO
+	// The body is optional; an empty one retries only unsuccessful subtasks
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, job.RetryTranslationJobDTO{}))
+			return
+		}
+	}
+
+	result, err := h.jobService.RetryJob(c.Request.Context(), id, userID, req)
+	var exceeded *quota.ExceededError
+	if errors.As(err, &exceeded) {
+		quotaExceeded(c, exceeded)
+		return
+	}
+	if jobConflict(err) {
+		response.Error(c, http.StatusConflict, fmt.Sprintf("Failed to retry translation job: %v", err))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to retry translation job: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Translation job queued for retry", result)
+}
+
+// PublishJob promotes the staged results of a finished job into the
+// novel's translations
+func (h *TranslationJobHandler) PublishJob(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := c.Param("id")
+
+	result, err := h.jobService.PublishJob(c.Request.Context(), id, userID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to publish translation job: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Translation job published successfully", result)
+}
+
+// DiscardStaging drops the staged results of a finished job
+func (h *TranslationJobHandler) DiscardStaging(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.jobService.DiscardStaging(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to discard staged results: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Staged results discarded successfully", result)
+}
+
+// GetStagedResult returns the unpublished output of a subtask
+func (h *TranslationJobHandler) GetStagedResult(c *gin.Context) {
+	id := c.Param("id")
+	subtaskID := c.Param("subtask_id")
+
+	result, err := h.jobService.GetStagedResult(c.Request.Context(), id, subtaskID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Failed to retrieve staged result: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Staged result retrieved successfully", result)
+}
+
+const (
+	// eventsPollInterval is how often the job is polled when live events are unavailable
+	eventsPollInterval = 2 * time.Second
+	// eventsKeepAliveInterval keeps idle streams open through proxies and also
+	// re-checks the job in case an event was lost
+	eventsKeepAliveInterval = 15 * time.Second
+)
+
+// StreamJobEvents streams job status, progress and subtask events over
+// Server-Sent Events until the job finishes or the client disconnects
+func (h *TranslationJobHandler) StreamJobEvents(c *gin.Context) {
+	id := c.Param("id")
+	ctx := c.Request.Context()
+
+	// Subscribe before taking the snapshot so no event falls in between. If
+	// the subscription fails the stream polls instead, as without Redis.
+	events, err := h.jobService.SubscribeJobEvents(ctx, id)
+	if err != nil {
+		events = nil
+	}
+
+	snapshot, err := h.jobService.GetJobSnapshot(ctx, id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Translation job not found: %v", err))
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent(snapshot.Type, snapshot)
+	c.Writer.Flush()
+	if job.IsTerminalJobStatus(snapshot.Status) {
+		return
+	}
+
+	interval := eventsKeepAliveInterval
+	if events == nil {
+		interval = eventsPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := snapshot
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+
+		case event, ok := <-events:
+			if !ok {
+				// Subscription dropped; keep serving the client by polling
+				events = nil
+				ticker.Reset(eventsPollInterval)
+				return true
+			}
+			c.SSEvent(event.Type, event)
+			return !(event.Type == job.TranslationJobEventStatus && job.IsTerminalJobStatus(event.Status))
+
+		case <-ticker.C:
+			current, err := h.jobService.GetJobSnapshot(ctx, id)
+			if err != nil {
+				return false
+			}
+			if snapshotChanged(last, current) {
+				c.SSEvent(current.Type, current)
+				last = current
+			} else {
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+			return !job.IsTerminalJobStatus(current.Status)
+		}
+	})
+}
+
+func snapshotChanged(previous, current *job.TranslationJobEventDTO) bool {
+	return previous.Status != current.Status ||
+		*previous.CompletedSubtasks != *current.CompletedSubtasks ||
+		*previous.TotalSubtasks != *current.TotalSubtasks
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	translationmemory "simple-go/internal/domain/translation_memory"
+	"simple-go/internal/service"
+	"simple-go/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationMemoryHandler struct {
+	memoryService *service.TranslationMemoryService
+}
+
+func NewTranslationMemoryHandler(memoryService *service.TranslationMemoryService) *TranslationMemoryHandler {
+	return &TranslationMemoryHandler{
+		memoryService: memoryService,
+	}
+}
+
+// Lookup returns the exact and fuzzy translation memory matches for a segment
+func (h *TranslationMemoryHandler) Lookup(c *gin.Context) {
+	var req translationmemory.LookupDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters", response.MapValidationErrors(err, translationmemory.LookupDTO{}))
+		return
+	}
+
+	matches, err := h.memoryService.Lookup(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, fmt.Sprintf("Failed to look up translation memory: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Translation memory matches retrieved successfully", matches)
+}
+
+// Import learns the existing chapter translations of a novel
+func (h *TranslationMemoryHandler) Import(c *gin.Context) {
+	var req translationmemory.ImportDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, translationmemory.ImportDTO{}))
+		return
+	}
+
+	result, err := h.memoryService.ImportNovel(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to import translation memory: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Translation memory imported successfully", result)
+}
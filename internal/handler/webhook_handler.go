@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"simple-go/internal/domain/webhook"
+	"simple-go/internal/middleware"
+	"simple-go/internal/service"
+	"simple-go/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) Create(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req webhook.CreateWebhookDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, webhook.CreateWebhookDTO{}))
+		return
+	}
+
+	created, err := h.webhookService.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to create webhook: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Webhook created successfully", created)
+}
+
+func (h *WebhookHandler) GetAll(c *gin.Context) {
+	page, limit := paginationParams(c)
+
+	webhooks, total, err := h.webhookService.GetAll(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve webhooks: %v", err))
+		return
+	}
+
+	response.PaginatedSuccess(c, http.StatusOK, "Webhooks retrieved successfully", webhooks, newPagination(page, limit, total))
+}
+
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	result, err := h.webhookService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Webhook not found: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook retrieved successfully", result)
+}
+
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req webhook.UpdateWebhookDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request body", response.MapValidationErrors(err, webhook.UpdateWebhookDTO{}))
+		return
+	}
+
+	updated, err := h.webhookService.Update(c.Request.Context(), id, req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to update webhook: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook updated successfully", updated)
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.webhookService.Delete(c.Request.Context(), id)
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Failed to delete webhook: %v", err))
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, fmt.Sprintf("Failed to delete webhook: %v", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetDeliveries lists the delivery log of a webhook, newest first
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	id := c.Param("id")
+	page, limit := paginationParams(c)
+
+	deliveries, total, err := h.webhookService.GetDeliveries(c.Request.Context(), id, limit, (page-1)*limit)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("Failed to retrieve webhook deliveries: %v", err))
+		return
+	}
+
+	response.PaginatedSuccess(c, http.StatusOK, "Webhook deliveries retrieved successfully", deliveries, newPagination(page, limit, total))
+}
+
+// Redeliver sends a logged delivery again
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id := c.Param("id")
+	deliveryID := c.Param("delivery_id")
+
+	result, err := h.webhookService.Redeliver(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Failed to redeliver webhook: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Webhook redelivered", result)
+}
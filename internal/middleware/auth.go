@@ -45,6 +45,23 @@ func JWTAuth(jwtManager *auth.JWTManager) gin.HandlerFunc {
 	}
 }
 
+// OptionalJWTAuth identifies the caller when a valid token is sent but lets
+// anonymous requests through, for public routes that show more to signed-in
+// users. An invalid token is treated like no token at all.
+func OptionalJWTAuth(jwtManager *auth.JWTManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := jwtManager.ValidateToken(parts[1]); err == nil {
+				c.Set("userID", claims.UserID)
+				c.Set("userEmail", claims.Email)
+			}
+		}
+
+		c.Next()
+	}
+}
+
 func GetUserID(c *gin.Context) (string, bool) {
 	userID, exists := c.Get("userID")
 	if !exists {
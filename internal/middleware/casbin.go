@@ -69,19 +69,12 @@ func CasbinAuthorizer(enforcer *casbin.Enforcer, roleGetter func(*gin.Context) (
 		}
 
 		// Check if any of the user's roles have permission
-		allowed := false
-		for _, role := range roles {
-			ok, err := enforcer.Enforce(role, resource, action)
-			if err != nil {
-				logger.Error(err, "authorization check failed")
-				response.Error(c, http.StatusInternalServerError, "Authorization check failed")
-				c.Abort()
-				return
-			}
-			if ok {
-				allowed = true
-				break
-			}
+		allowed, err := enforceAny(enforcer, roles, resource, action)
+		if err != nil {
+			logger.Error(err, "authorization check failed")
+			response.Error(c, http.StatusInternalServerError, "Authorization check failed")
+			c.Abort()
+			return
 		}
 
 		if !allowed {
@@ -101,3 +94,46 @@ func RequirePermission(resource, action string, enforcer *casbin.Enforcer, roleG
 		CasbinAuthorizer(enforcer, roleGetter)(c)
 	}
 }
+
+// LoadPermission records whether the caller may perform the action on the
+// resource without rejecting the request. It is meant for routes whose
+// response depends on the caller; read the result with HasPermission.
+// Anonymous callers and failed lookups are treated as not permitted.
+func LoadPermission(resource, action string, enforcer *casbin.Enforcer, roleGetter func(*gin.Context) ([]string, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed := false
+
+		roles, err := roleGetter(c)
+		if err != nil {
+			logger.Error(err, "failed to get user roles")
+		} else if allowed, err = enforceAny(enforcer, roles, resource, action); err != nil {
+			logger.Error(err, "authorization check failed")
+		}
+
+		c.Set(permissionKey(resource, action), allowed)
+		c.Next()
+	}
+}
+
+// HasPermission reports the result recorded by LoadPermission
+func HasPermission(c *gin.Context, resource, action string) bool {
+	return c.GetBool(permissionKey(resource, action))
+}
+
+func permissionKey(resource, action string) string {
+	return "casbin_permission:" + resource + ":" + action
+}
+
+// enforceAny reports whether any of the roles may perform the action
+func enforceAny(enforcer *casbin.Enforcer, roles []string, resource, action string) (bool, error) {
+	for _, role := range roles {
+		ok, err := enforcer.Enforce(role, resource, action)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -16,5 +16,28 @@ type ChapterRepository interface {
 	Delete(ctx context.Context, id string) (int64, error)
 	CreateTranslation(ctx context.Context, ct *chapter.ChapterTranslation) (*chapter.ChapterTranslation, error)
 	GetTranslation(ctx context.Context, chapterID, lang string) (*chapter.ChapterTranslation, error)
+	GetTranslationByID(ctx context.Context, id string) (*chapter.ChapterTranslation, error)
+	// GetReviewQueue lists translations awaiting review, oldest first
+	GetReviewQueue(ctx context.Context, filter chapter.ReviewQueueFilter, limit, offset int) ([]chapter.ChapterTranslation, error)
+	CountReviewQueue(ctx context.Context, filter chapter.ReviewQueueFilter) (int64, error)
+	GetAlignment(ctx context.Context, chapterID, sourceLang, targetLang string) (*chapter.ChapterAlignment, error)
+	// SaveAlignment creates or replaces the alignment of a language pair
+	SaveAlignment(ctx context.Context, a *chapter.ChapterAlignment) error
+	// MarkTranslationsOutdated marks the chapter's translations in languages
+	// other than sourceLang outdated and returns them. Their UpdatedAt is
+	// left as it is.
+	MarkTranslationsOutdated(ctx context.Context, chapterID, sourceLang string) ([]chapter.ChapterTranslation, error)
+	// MarkAlignmentsOutdated marks the chapter's alignments with lang on
+	// either side outdated and returns them
+	MarkAlignmentsOutdated(ctx context.Context, chapterID, lang string) ([]chapter.ChapterAlignment, error)
+	// SaveRevision creates or replaces the pending revision of a translation
+	SaveRevision(ctx context.Context, r *chapter.ChapterTranslationRevision) error
+	GetRevision(ctx context.Context, translationID string) (*chapter.ChapterTranslationRevision, error)
+	// GetRevisions lists pending revisions, oldest first. Only Lang and
+	// NovelID of the filter apply.
+	GetRevisions(ctx context.Context, filter chapter.ReviewQueueFilter, limit, offset int) ([]chapter.ChapterTranslationRevision, error)
+	CountRevisions(ctx context.Context, filter chapter.ReviewQueueFilter) (int64, error)
+	DeleteRevision(ctx context.Context, translationID string) (int64, error)
+	UpdateTranslation(ctx context.Context, ct *chapter.ChapterTranslation) (*chapter.ChapterTranslation, error)
 	DeleteTranslation(ctx context.Context, translationID string) (int64, error)
 }
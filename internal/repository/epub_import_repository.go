@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	epubimport "simple-go/internal/domain/epub_import"
+	"simple-go/internal/domain/novel"
+	"time"
+)
+
+// ErrImportLeaseLost is returned when a worker updates an import that another
+// worker has claimed since, or that is no longer in progress
+var ErrImportLeaseLost = errors.New("epub import lease lost")
+
+type EpubImportRepository interface {
+	Create(ctx context.Context, i *epubimport.EpubImport) (*epubimport.EpubImport, error)
+	GetByID(ctx context.Context, id string) (*epubimport.EpubImport, error)
+	GetFile(ctx context.Context, importID string) ([]byte, error)
+	DeleteFile(ctx context.Context, importID string) error
+
+	// ClaimNext picks the oldest pending import, or one whose worker stopped
+	// renewing its lease, marks it IN_PROGRESS and leases it until leaseUntil.
+	// It returns nil when there is nothing to import. The claimed import's
+	// Attempts identifies the claim: the updates below take it as attempt and
+	// return ErrImportLeaseLost once a later claim has replaced it.
+	ClaimNext(ctx context.Context, leaseUntil time.Time) (*epubimport.EpubImport, error)
+	MarkParsed(ctx context.Context, id string, attempt int, sourceType string, totalChapters int, leaseUntil time.Time) error
+	MarkNovelCreated(ctx context.Context, id string, attempt int, novelID string, leaseUntil time.Time) error
+	UpdateProgress(ctx context.Context, id string, attempt int, importedChapters int, leaseUntil time.Time) error
+	// UpdateMergeProgress is UpdateProgress for merges, saving the diff so far
+	UpdateMergeProgress(ctx context.Context, id string, attempt int, mergedChapters int, result *novel.EpubMergeResultDTO, leaseUntil time.Time) error
+	MarkCompleted(ctx context.Context, id string, attempt int) error
+	// MarkFailed also clears the novel of an import, which is deleted when it
+	// fails. A merge keeps its novel and progress.
+	MarkFailed(ctx context.Context, id string, attempt int, errorMessage string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"simple-go/internal/domain/glossary"
+)
+
+type GlossaryRepository interface {
+	Create(ctx context.Context, t *glossary.GlossaryTerm) (*glossary.GlossaryTerm, error)
+	GetByID(ctx context.Context, id string) (*glossary.GlossaryTerm, error)
+	GetBySourceTerm(ctx context.Context, novelID, sourceLang, targetLang, sourceTerm string) (*glossary.GlossaryTerm, error)
+	// GetByNovelID lists the terms of a novel; empty languages match any
+	GetByNovelID(ctx context.Context, novelID, sourceLang, targetLang string, limit, offset int) ([]glossary.GlossaryTerm, error)
+	CountByNovelID(ctx context.Context, novelID, sourceLang, targetLang string) (int64, error)
+	// GetForLanguagePair returns every term the translator should use for a job
+	GetForLanguagePair(ctx context.Context, novelID, sourceLang, targetLang string) ([]glossary.GlossaryTerm, error)
+	Update(ctx context.Context, t *glossary.GlossaryTerm) (*glossary.GlossaryTerm, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -6,6 +6,7 @@ import (
 	"simple-go/internal/repository"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type chapterRepository struct {
@@ -135,7 +136,178 @@ func (r *chapterRepository) GetTranslation(ctx context.Context, chapterID, lang
 	return &ct, nil
 }
 
+func (r *chapterRepository) GetTranslationByID(ctx context.Context, id string) (*chapter.ChapterTranslation, error) {
+	var ct chapter.ChapterTranslation
+	if err := r.db.WithContext(ctx).First(&ct, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &ct, nil
+}
+
+func (r *chapterRepository) GetReviewQueue(ctx context.Context, filter chapter.ReviewQueueFilter, limit, offset int) ([]chapter.ChapterTranslation, error) {
+	var translations []chapter.ChapterTranslation
+
+	// Content is left out, reviewers open the chapter itself
+	query := r.reviewQueueQuery(ctx, filter).
+		Omit("content").
+		Order("chapter_translations.updated_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *chapterRepository) CountReviewQueue(ctx context.Context, filter chapter.ReviewQueueFilter) (int64, error) {
+	var count int64
+	err := r.reviewQueueQuery(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+func (r *chapterRepository) reviewQueueQuery(ctx context.Context, filter chapter.ReviewQueueFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&chapter.ChapterTranslation{})
+	if len(filter.Statuses) > 0 {
+		query = query.Where("chapter_translations.status IN ?", filter.Statuses)
+	}
+	if filter.Lang != "" {
+		query = query.Where("chapter_translations.lang = ?", filter.Lang)
+	}
+	if filter.ReviewerID != "" {
+		query = query.Where("chapter_translations.reviewer_id = ?", filter.ReviewerID)
+	}
+	if filter.Unassigned {
+		query = query.Where("chapter_translations.reviewer_id IS NULL")
+	}
+	if filter.NovelID != "" {
+		query = query.
+			Joins("JOIN chapters ON chapters.id = chapter_translations.chapter_id").
+			Joins("JOIN volumes ON volumes.id = chapters.volume_id").
+			Where("volumes.novel_id = ?", filter.NovelID)
+	}
+	return query
+}
+
+func (r *chapterRepository) GetAlignment(ctx context.Context, chapterID, sourceLang, targetLang string) (*chapter.ChapterAlignment, error) {
+	var a chapter.ChapterAlignment
+	err := r.db.WithContext(ctx).
+		Where("chapter_id = ? AND source_lang = ? AND target_lang = ?", chapterID, sourceLang, targetLang).
+		First(&a).Error
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *chapterRepository) SaveAlignment(ctx context.Context, a *chapter.ChapterAlignment) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chapter_id"}, {Name: "source_lang"}, {Name: "target_lang"}},
+			DoUpdates: clause.AssignmentColumns([]string{"source_hash", "target_hash", "pairs", "outdated", "updated_at"}),
+		}).
+		Create(a).Error
+}
+
+func (r *chapterRepository) MarkTranslationsOutdated(ctx context.Context, chapterID, sourceLang string) ([]chapter.ChapterTranslation, error) {
+	var translations []chapter.ChapterTranslation
+	err := r.db.WithContext(ctx).
+		Model(&translations).
+		Clauses(clause.Returning{}).
+		Where("chapter_id = ? AND lang <> ?", chapterID, sourceLang).
+		UpdateColumn("outdated", true).Error
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *chapterRepository) MarkAlignmentsOutdated(ctx context.Context, chapterID, lang string) ([]chapter.ChapterAlignment, error) {
+	var alignments []chapter.ChapterAlignment
+	err := r.db.WithContext(ctx).
+		Model(&alignments).
+		Clauses(clause.Returning{}).
+		Where("chapter_id = ? AND (source_lang = ? OR target_lang = ?)", chapterID, lang, lang).
+		UpdateColumn("outdated", true).Error
+	if err != nil {
+		return nil, err
+	}
+	return alignments, nil
+}
+
+func (r *chapterRepository) UpdateTranslation(ctx context.Context, ct *chapter.ChapterTranslation) (*chapter.ChapterTranslation, error) {
+	if err := r.db.WithContext(ctx).Save(ct).Error; err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
 func (r *chapterRepository) DeleteTranslation(ctx context.Context, translationID string) (int64, error) {
 	result := r.db.WithContext(ctx).Delete(&chapter.ChapterTranslation{}, "id = ?", translationID)
 	return result.RowsAffected, result.Error
 }
+
+func (r *chapterRepository) SaveRevision(ctx context.Context, rev *chapter.ChapterTranslationRevision) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "translation_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "content", "source_lang", "pivot_lang", "updated_at"}),
+		}).
+		Create(rev).Error
+}
+
+func (r *chapterRepository) GetRevision(ctx context.Context, translationID string) (*chapter.ChapterTranslationRevision, error) {
+	var rev chapter.ChapterTranslationRevision
+	if err := r.db.WithContext(ctx).First(&rev, "translation_id = ?", translationID).Error; err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (r *chapterRepository) GetRevisions(ctx context.Context, filter chapter.ReviewQueueFilter, limit, offset int) ([]chapter.ChapterTranslationRevision, error) {
+	var revisions []chapter.ChapterTranslationRevision
+
+	query := r.revisionsQuery(ctx, filter).
+		Omit("content").
+		Order("chapter_translation_revisions.updated_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *chapterRepository) CountRevisions(ctx context.Context, filter chapter.ReviewQueueFilter) (int64, error) {
+	var count int64
+	err := r.revisionsQuery(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+func (r *chapterRepository) revisionsQuery(ctx context.Context, filter chapter.ReviewQueueFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&chapter.ChapterTranslationRevision{})
+	if filter.Lang != "" {
+		query = query.Where("chapter_translation_revisions.lang = ?", filter.Lang)
+	}
+	if filter.NovelID != "" {
+		query = query.
+			Joins("JOIN chapters ON chapters.id = chapter_translation_revisions.chapter_id").
+			Joins("JOIN volumes ON volumes.id = chapters.volume_id").
+			Where("volumes.novel_id = ?", filter.NovelID)
+	}
+	return query
+}
+
+func (r *chapterRepository) DeleteRevision(ctx context.Context, translationID string) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&chapter.ChapterTranslationRevision{}, "translation_id = ?", translationID)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,162 @@
+package gormrepo
+
+import (
+	"context"
+	"encoding/json"
+	epubimport "simple-go/internal/domain/epub_import"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type epubImportRepository struct {
+	db *gorm.DB
+}
+
+func NewEpubImportRepository(db *gorm.DB) repository.EpubImportRepository {
+	return &epubImportRepository{db: db}
+}
+
+// Create saves the import together with its file
+func (r *epubImportRepository) Create(ctx context.Context, i *epubimport.EpubImport) (*epubimport.EpubImport, error) {
+	if err := r.db.WithContext(ctx).Create(i).Error; err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *epubImportRepository) GetByID(ctx context.Context, id string) (*epubimport.EpubImport, error) {
+	var i epubimport.EpubImport
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&i).Error; err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *epubImportRepository) GetFile(ctx context.Context, importID string) ([]byte, error) {
+	var f epubimport.EpubImportFile
+	if err := r.db.WithContext(ctx).Where("import_id = ?", importID).First(&f).Error; err != nil {
+		return nil, err
+	}
+	return f.Data, nil
+}
+
+func (r *epubImportRepository) DeleteFile(ctx context.Context, importID string) error {
+	return r.db.WithContext(ctx).
+		Where("import_id = ?", importID).
+		Delete(&epubimport.EpubImportFile{}).Error
+}
+
+func (r *epubImportRepository) ClaimNext(ctx context.Context, leaseUntil time.Time) (*epubimport.EpubImport, error) {
+	var imports []epubimport.EpubImport
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE epub_imports
+		SET status = ?, stage = ?, lease_expires_at = ?, attempts = attempts + 1,
+			started_at = COALESCE(started_at, ?), updated_at = ?
+		WHERE id IN (
+			SELECT id FROM epub_imports
+			WHERE status = ? OR (status = ? AND lease_expires_at < ?)
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		epubimport.EpubImportStatusInProgress,
+		epubimport.EpubImportStageParsing,
+		leaseUntil,
+		now,
+		now,
+		epubimport.EpubImportStatusPending,
+		epubimport.EpubImportStatusInProgress,
+		now,
+	).Scan(&imports).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(imports) == 0 {
+		return nil, nil
+	}
+	return &imports[0], nil
+}
+
+func (r *epubImportRepository) MarkParsed(ctx context.Context, id string, attempt int, sourceType string, totalChapters int, leaseUntil time.Time) error {
+	return r.updateClaimed(ctx, id, attempt, map[string]interface{}{
+		// A resumed import already has its novel
+		"stage": gorm.Expr("CASE WHEN novel_id IS NULL THEN ? ELSE ? END",
+			epubimport.EpubImportStageNovel, epubimport.EpubImportStageChapters),
+		"source_type":      sourceType,
+		"total_chapters":   totalChapters,
+		"lease_expires_at": leaseUntil,
+	})
+}
+
+func (r *epubImportRepository) MarkNovelCreated(ctx context.Context, id string, attempt int, novelID string, leaseUntil time.Time) error {
+	return r.updateClaimed(ctx, id, attempt, map[string]interface{}{
+		"stage":            epubimport.EpubImportStageChapters,
+		"novel_id":         novelID,
+		"lease_expires_at": leaseUntil,
+	})
+}
+
+func (r *epubImportRepository) UpdateProgress(ctx context.Context, id string, attempt int, importedChapters int, leaseUntil time.Time) error {
+	return r.updateClaimed(ctx, id, attempt, map[string]interface{}{
+		"imported_chapters": importedChapters,
+		"lease_expires_at":  leaseUntil,
+	})
+}
+
+func (r *epubImportRepository) UpdateMergeProgress(ctx context.Context, id string, attempt int, mergedChapters int, result *novel.EpubMergeResultDTO, leaseUntil time.Time) error {
+	// Map updates bypass the field's serializer
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return r.updateClaimed(ctx, id, attempt, map[string]interface{}{
+		"imported_chapters": mergedChapters,
+		"merge_result":      string(encoded),
+		"lease_expires_at":  leaseUntil,
+	})
+}
+
+func (r *epubImportRepository) MarkCompleted(ctx context.Context, id string, attempt int) error {
+	return r.updateClaimed(ctx, id, attempt, map[string]interface{}{
+		"status":           epubimport.EpubImportStatusCompleted,
+		"stage":            epubimport.EpubImportStageDone,
+		"lease_expires_at": nil,
+		"finished_at":      time.Now(),
+	})
+}
+
+func (r *epubImportRepository) MarkFailed(ctx context.Context, id string, attempt int, errorMessage string) error {
+	return r.updateClaimed(ctx, id, attempt, map[string]interface{}{
+		"status": epubimport.EpubImportStatusFailed,
+		// A merge keeps its novel and the chapters it merged
+		"novel_id": gorm.Expr("CASE WHEN kind = ? THEN novel_id END",
+			epubimport.EpubImportKindMerge),
+		"imported_chapters": gorm.Expr("CASE WHEN kind = ? THEN imported_chapters ELSE 0 END",
+			epubimport.EpubImportKindMerge),
+		"error_message":    errorMessage,
+		"lease_expires_at": nil,
+		"finished_at":      time.Now(),
+	})
+}
+
+// updateClaimed applies updates only while the import is still held by the
+// claim that counted attempt
+func (r *epubImportRepository) updateClaimed(ctx context.Context, id string, attempt int, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).
+		Model(&epubimport.EpubImport{}).
+		Where("id = ? AND status = ? AND attempts = ?", id, epubimport.EpubImportStatusInProgress, attempt).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrImportLeaseLost
+	}
+	return nil
+}
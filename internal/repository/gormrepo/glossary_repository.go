@@ -0,0 +1,108 @@
+package gormrepo
+
+import (
+	"context"
+	"simple-go/internal/domain/glossary"
+	"simple-go/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type glossaryRepository struct {
+	db *gorm.DB
+}
+
+func NewGlossaryRepository(db *gorm.DB) repository.GlossaryRepository {
+	return &glossaryRepository{db: db}
+}
+
+func (r *glossaryRepository) Create(ctx context.Context, t *glossary.GlossaryTerm) (*glossary.GlossaryTerm, error) {
+	if err := r.db.WithContext(ctx).Create(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *glossaryRepository) GetByID(ctx context.Context, id string) (*glossary.GlossaryTerm, error) {
+	var t glossary.GlossaryTerm
+	if err := r.db.WithContext(ctx).First(&t, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *glossaryRepository) GetBySourceTerm(ctx context.Context, novelID, sourceLang, targetLang, sourceTerm string) (*glossary.GlossaryTerm, error) {
+	var t glossary.GlossaryTerm
+	err := r.db.WithContext(ctx).
+		Where("novel_id = ? AND source_lang = ? AND target_lang = ? AND source_term = ?", novelID, sourceLang, targetLang, sourceTerm).
+		First(&t).Error
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *glossaryRepository) GetByNovelID(ctx context.Context, novelID, sourceLang, targetLang string, limit, offset int) ([]glossary.GlossaryTerm, error) {
+	var terms []glossary.GlossaryTerm
+
+	query := r.novelQuery(ctx, novelID, sourceLang, targetLang).
+		Order("source_lang ASC, target_lang ASC, source_term ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&terms).Error; err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (r *glossaryRepository) CountByNovelID(ctx context.Context, novelID, sourceLang, targetLang string) (int64, error) {
+	var count int64
+	err := r.novelQuery(ctx, novelID, sourceLang, targetLang).Count(&count).Error
+	return count, err
+}
+
+func (r *glossaryRepository) GetForLanguagePair(ctx context.Context, novelID, sourceLang, targetLang string) ([]glossary.GlossaryTerm, error) {
+	var terms []glossary.GlossaryTerm
+	err := r.db.WithContext(ctx).
+		Where("novel_id = ? AND source_lang = ? AND target_lang = ?", novelID, sourceLang, targetLang).
+		Order("source_term ASC").
+		Find(&terms).Error
+	if err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (r *glossaryRepository) Update(ctx context.Context, t *glossary.GlossaryTerm) (*glossary.GlossaryTerm, error) {
+	if err := r.db.WithContext(ctx).Save(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *glossaryRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&glossary.GlossaryTerm{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *glossaryRepository) novelQuery(ctx context.Context, novelID, sourceLang, targetLang string) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&glossary.GlossaryTerm{}).Where("novel_id = ?", novelID)
+	if sourceLang != "" {
+		query = query.Where("source_lang = ?", sourceLang)
+	}
+	if targetLang != "" {
+		query = query.Where("target_lang = ?", targetLang)
+	}
+	return query
+}
@@ -143,6 +143,13 @@ func (r *novelRepository) GetTranslation(ctx context.Context, novelID, lang stri
 	return &nt, nil
 }
 
+func (r *novelRepository) UpdateTranslation(ctx context.Context, nt *novel.NovelTranslation) (*novel.NovelTranslation, error) {
+	if err := r.db.WithContext(ctx).Save(nt).Error; err != nil {
+		return nil, err
+	}
+	return nt, nil
+}
+
 func (r *novelRepository) DeleteTranslation(ctx context.Context, id string) (int64, error) {
 	result := r.db.WithContext(ctx).Delete(&novel.NovelTranslation{}, "id = ?", id)
 	return result.RowsAffected, result.Error
@@ -0,0 +1,42 @@
+package gormrepo
+
+import (
+	"context"
+	"simple-go/internal/domain/quota"
+	"simple-go/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// quotaLockKey is the advisory lock taken while checking and charging quotas
+const quotaLockKey = 7340101
+
+type quotaRepository struct {
+	db *gorm.DB
+}
+
+func NewQuotaRepository(db *gorm.DB) repository.QuotaRepository {
+	return &quotaRepository{db: db}
+}
+
+func (r *quotaRepository) RecordUsage(ctx context.Context, u *quota.Usage) error {
+	return r.db.WithContext(ctx).Create(u).Error
+}
+
+func (r *quotaRepository) SumCharacters(ctx context.Context, userID string, since time.Time) (int, error) {
+	var total int
+	query := r.db.WithContext(ctx).
+		Model(&quota.Usage{}).
+		Select("COALESCE(SUM(characters), 0)").
+		Where("created_at >= ?", since)
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	err := query.Scan(&total).Error
+	return total, err
+}
+
+func (r *quotaRepository) Lock(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(?)", quotaLockKey).Error
+}
@@ -2,12 +2,22 @@ package gormrepo
 
 import (
 	"context"
+	"encoding/json"
 	"simple-go/internal/domain/job"
 	"simple-go/internal/repository"
+	"simple-go/pkg/qa"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// activeJobStatuses are the job statuses whose subtasks may still be claimed
+var activeJobStatuses = []string{
+	job.TranslationJobStatusPending,
+	job.TranslationJobStatusInProgress,
+}
+
 type translationJobRepository struct {
 	db *gorm.DB
 }
@@ -36,10 +46,31 @@ func (r *translationJobRepository) GetByID(ctx context.Context, id string) (*job
 	return &j, nil
 }
 
+// GetSummaryByID loads the job row without its subtasks
+func (r *translationJobRepository) GetSummaryByID(ctx context.Context, id string) (*job.TranslationJob, error) {
+	var j job.TranslationJob
+	if err := r.db.WithContext(ctx).First(&j, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (r *translationJobRepository) LockByID(ctx context.Context, id string) (*job.TranslationJob, error) {
+	var j job.TranslationJob
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&j, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
 func (r *translationJobRepository) GetByNovelAndLang(ctx context.Context, novelID, targetLang string) (*job.TranslationJob, error) {
 	var j job.TranslationJob
 	err := r.db.WithContext(ctx).
 		Where("novel_id = ? AND target_lang = ?", novelID, targetLang).
+		Order("created_at DESC").
 		First(&j).Error
 	if err != nil {
 		return nil, err
@@ -47,6 +78,26 @@ func (r *translationJobRepository) GetByNovelAndLang(ctx context.Context, novelI
 	return &j, nil
 }
 
+func (r *translationJobRepository) CreateBatch(ctx context.Context, b *job.TranslationJobBatch) (*job.TranslationJobBatch, error) {
+	if err := r.db.WithContext(ctx).Create(b).Error; err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r *translationJobRepository) GetBatchByID(ctx context.Context, id string) (*job.TranslationJobBatch, error) {
+	var b job.TranslationJobBatch
+	err := r.db.WithContext(ctx).
+		Preload("Jobs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("translation_jobs.target_lang ASC")
+		}).
+		First(&b, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
 func (r *translationJobRepository) GetAll(ctx context.Context, limit, offset int, status string) ([]job.TranslationJob, error) {
 	var jobs []job.TranslationJob
 
@@ -106,6 +157,13 @@ func (r *translationJobRepository) UpdateStatus(ctx context.Context, id, status
 		Update("status", status).Error
 }
 
+func (r *translationJobRepository) UpdatePriority(ctx context.Context, id string, priority int) error {
+	return r.db.WithContext(ctx).
+		Model(&job.TranslationJob{}).
+		Where("id = ?", id).
+		Update("priority", priority).Error
+}
+
 func (r *translationJobRepository) UpdateProgress(ctx context.Context, id string, progress, completedSubtasks int) error {
 	return r.db.WithContext(ctx).
 		Model(&job.TranslationJob{}).
@@ -116,12 +174,112 @@ func (r *translationJobRepository) UpdateProgress(ctx context.Context, id string
 		}).Error
 }
 
+func (r *translationJobRepository) MarkStarted(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&job.TranslationJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     job.TranslationJobStatusInProgress,
+			"started_at": gorm.Expr("COALESCE(started_at, ?)", time.Now()),
+		}).Error
+}
+
+// MarkFinished only transitions jobs that are still active so that several
+// workers racing to finish the same job do not overwrite each other
+func (r *translationJobRepository) MarkFinished(ctx context.Context, id, status string, errorMessage *string) error {
+	return r.db.WithContext(ctx).
+		Model(&job.TranslationJob{}).
+		Where("id = ? AND status IN ?", id, activeJobStatuses).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"error_message": errorMessage,
+			"finished_at":   time.Now(),
+		}).Error
+}
+
+// RecordQA stores the QA outcome of a job
+func (r *translationJobRepository) RecordQA(ctx context.Context, id, status string, errors, warnings int) error {
+	return r.db.WithContext(ctx).
+		Model(&job.TranslationJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"qa_status":   status,
+			"qa_errors":   errors,
+			"qa_warnings": warnings,
+		}).Error
+}
+
+// MarkCancelled cancels the job if it is still active and reports whether it did
+func (r *translationJobRepository) MarkCancelled(ctx context.Context, id, cancelledBy string) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&job.TranslationJob{}).
+		Where("id = ? AND status IN ?", id, activeJobStatuses).
+		Updates(map[string]interface{}{
+			"status":       job.TranslationJobStatusCancelled,
+			"cancelled_by": cancelledBy,
+			"cancelled_at": now,
+			"finished_at":  now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *translationJobRepository) MarkPublished(ctx context.Context, id string, publishedBy *string) error {
+	return r.db.WithContext(ctx).
+		Model(&job.TranslationJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"published_by": publishedBy,
+			"published_at": time.Now(),
+		}).Error
+}
+
+// ResetForRetry puts a finished job back to PENDING with recomputed totals
+func (r *translationJobRepository) ResetForRetry(ctx context.Context, id string, totalSubtasks, completedSubtasks int) error {
+	progress := 0
+	if totalSubtasks > 0 {
+		progress = completedSubtasks * 100 / totalSubtasks
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&job.TranslationJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":             job.TranslationJobStatusPending,
+			"progress":           progress,
+			"total_subtasks":     totalSubtasks,
+			"completed_subtasks": completedSubtasks,
+			"error_message":      nil,
+			"qa_status":          nil,
+			"qa_errors":          0,
+			"qa_warnings":        0,
+			"cancelled_by":       nil,
+			"cancelled_at":       nil,
+			"finished_at":        nil,
+		}).Error
+}
+
 func (r *translationJobRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&job.TranslationJob{}).Count(&count).Error
 	return count, err
 }
 
+func (r *translationJobRepository) CountActive(ctx context.Context, createdBy string) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).
+		Model(&job.TranslationJob{}).
+		Where("status IN ?", []string{job.TranslationJobStatusPending, job.TranslationJobStatusInProgress})
+	if createdBy != "" {
+		query = query.Where("created_by = ?", createdBy)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
 // Subtask operations
 
 func (r *translationJobRepository) CreateSubtask(ctx context.Context, subtask *job.TranslationSubtask) (*job.TranslationSubtask, error) {
@@ -144,6 +302,44 @@ func (r *translationJobRepository) GetSubtaskByID(ctx context.Context, id string
 	return &subtask, nil
 }
 
+func (r *translationJobRepository) GetStagedSubtasks(ctx context.Context, jobID string) ([]job.TranslationSubtask, error) {
+	var subtasks []job.TranslationSubtask
+	err := r.db.WithContext(ctx).
+		Where("job_id = ? AND status = ?", jobID, job.TranslationSubtaskStatusDone).
+		Where("result_text IS NOT NULL").
+		Order("priority ASC, seq ASC").
+		Find(&subtasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return subtasks, nil
+}
+
+func (r *translationJobRepository) CountStagedSubtasks(ctx context.Context, jobID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("job_id = ? AND status = ?", jobID, job.TranslationSubtaskStatusDone).
+		Where("result_text IS NOT NULL").
+		Count(&count).Error
+	return count, err
+}
+
+func (r *translationJobRepository) ClearStagedResults(ctx context.Context, jobID string, subtaskIDs []string) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("job_id = ?", jobID).
+		Where("result_text IS NOT NULL")
+	if len(subtaskIDs) > 0 {
+		query = query.Where("id IN ?", subtaskIDs)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"result_text": nil,
+	})
+	return result.RowsAffected, result.Error
+}
+
 func (r *translationJobRepository) GetSubtasksByJobID(ctx context.Context, jobID string) ([]job.TranslationSubtask, error) {
 	var subtasks []job.TranslationSubtask
 	err := r.db.WithContext(ctx).
@@ -170,6 +366,92 @@ func (r *translationJobRepository) UpdateSubtaskStatus(ctx context.Context, id,
 		Update("status", status).Error
 }
 
+func (r *translationJobRepository) MarkSubtaskDone(ctx context.Context, id, owner string, result job.SubtaskResult) error {
+	updates := map[string]interface{}{
+		"status":                job.TranslationSubtaskStatusDone,
+		"result_text":           result.ResultText,
+		"glossary_hits":         result.GlossaryHits,
+		"glossary_misses":       len(result.GlossaryMissedTerms),
+		"glossary_missed_terms": nil,
+		"memory_segments":       result.Memory.Segments,
+		"memory_exact_hits":     result.Memory.ExactHits,
+		"memory_fuzzy_hits":     result.Memory.FuzzyHits,
+		"qa_findings":           nil,
+	}
+	updates["qa_errors"], updates["qa_warnings"] = qa.Count(result.QAFindings)
+
+	// Map updates bypass the json serializer, so encode the columns here
+	if len(result.GlossaryMissedTerms) > 0 {
+		missed, err := json.Marshal(result.GlossaryMissedTerms)
+		if err != nil {
+			return err
+		}
+		updates["glossary_missed_terms"] = string(missed)
+	}
+	if len(result.QAFindings) > 0 {
+		findings, err := json.Marshal(result.QAFindings)
+		if err != nil {
+			return err
+		}
+		updates["qa_findings"] = string(findings)
+	}
+	return r.finishLeasedSubtask(ctx, id, owner, updates)
+}
+
+func (r *translationJobRepository) MarkSubtaskFailed(ctx context.Context, id, owner, errorMessage string) error {
+	return r.finishLeasedSubtask(ctx, id, owner, map[string]interface{}{
+		"status":        job.TranslationSubtaskStatusFailed,
+		"error_message": errorMessage,
+	})
+}
+
+func (r *translationJobRepository) MarkSubtaskDeadLettered(ctx context.Context, id, owner, errorMessage string) error {
+	return r.finishLeasedSubtask(ctx, id, owner, map[string]interface{}{
+		"status":        job.TranslationSubtaskStatusDeadLetter,
+		"error_message": errorMessage,
+	})
+}
+
+// ScheduleSubtaskRetry hands the subtask back to the pool; it cannot be claimed
+// again before nextAttemptAt
+func (r *translationJobRepository) ScheduleSubtaskRetry(ctx context.Context, id, owner, errorMessage string, nextAttemptAt time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("id = ? AND status = ? AND lease_owner = ?", id, job.TranslationSubtaskStatusInProgress, owner).
+		Updates(map[string]interface{}{
+			"status":           job.TranslationSubtaskStatusPending,
+			"error_message":    errorMessage,
+			"next_attempt_at":  nextAttemptAt,
+			"lease_owner":      nil,
+			"lease_expires_at": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrSubtaskLeaseLost
+	}
+	return nil
+}
+
+func (r *translationJobRepository) finishLeasedSubtask(ctx context.Context, id, owner string, updates map[string]interface{}) error {
+	updates["lease_owner"] = nil
+	updates["lease_expires_at"] = nil
+	updates["finished_at"] = time.Now()
+
+	result := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("id = ? AND status = ? AND lease_owner = ?", id, job.TranslationSubtaskStatusInProgress, owner).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrSubtaskLeaseLost
+	}
+	return nil
+}
+
 func (r *translationJobRepository) CountSubtasksByStatus(ctx context.Context, jobID, status string) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -178,3 +460,231 @@ func (r *translationJobRepository) CountSubtasksByStatus(ctx context.Context, jo
 		Count(&count).Error
 	return count, err
 }
+
+// SumSubtaskQA totals the QA errors and warnings of a job's subtasks
+func (r *translationJobRepository) SumSubtaskQA(ctx context.Context, jobID string) (int, int, error) {
+	var totals struct {
+		Errors   int
+		Warnings int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Select("COALESCE(SUM(qa_errors), 0) AS errors, COALESCE(SUM(qa_warnings), 0) AS warnings").
+		Where("job_id = ?", jobID).
+		Scan(&totals).Error
+	return totals.Errors, totals.Warnings, err
+}
+
+func (r *translationJobRepository) CountActiveSubtasks(ctx context.Context, jobID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("job_id = ? AND status IN ?", jobID, []string{
+			job.TranslationSubtaskStatusPending,
+			job.TranslationSubtaskStatusInProgress,
+		}).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *translationJobRepository) CountSubtasks(ctx context.Context, jobID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("job_id = ?", jobID).
+		Count(&count).Error
+	return count, err
+}
+
+// ResetSubtasks returns the job's subtasks in the given statuses to a fresh
+// PENDING state with their attempts cleared
+func (r *translationJobRepository) ResetSubtasks(ctx context.Context, jobID string, statuses []string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("job_id = ? AND status IN ?", jobID, statuses).
+		Updates(map[string]interface{}{
+			"status":                job.TranslationSubtaskStatusPending,
+			"attempts":              0,
+			"next_attempt_at":       nil,
+			"error_message":         nil,
+			"result_text":           nil,
+			"glossary_hits":         0,
+			"glossary_misses":       0,
+			"glossary_missed_terms": nil,
+			"memory_segments":       0,
+			"memory_exact_hits":     0,
+			"memory_fuzzy_hits":     0,
+			"qa_errors":             0,
+			"qa_warnings":           0,
+			"qa_findings":           nil,
+			"lease_owner":           nil,
+			"lease_expires_at":      nil,
+			"heartbeat_at":          nil,
+			"started_at":            nil,
+			"finished_at":           nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CancelActiveSubtasks cancels every pending or leased subtask of a job. Workers
+// holding a lease notice on their next heartbeat or when finishing the subtask.
+func (r *translationJobRepository) CancelActiveSubtasks(ctx context.Context, jobID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("job_id = ? AND status IN ?", jobID, []string{
+			job.TranslationSubtaskStatusPending,
+			job.TranslationSubtaskStatusInProgress,
+		}).
+		Updates(map[string]interface{}{
+			"status":           job.TranslationSubtaskStatusCancelled,
+			"lease_owner":      nil,
+			"lease_expires_at": nil,
+			"next_attempt_at":  nil,
+			"finished_at":      time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ClaimNextSubtask atomically leases the next pending subtask to owner.
+// Rows locked by other workers are skipped, so concurrent workers never
+// claim the same subtask. Returns nil when nothing is claimable.
+func (r *translationJobRepository) ClaimNextSubtask(ctx context.Context, jobID, owner string, leaseFor time.Duration) (*job.TranslationSubtask, error) {
+	var claimed *job.TranslationSubtask
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		activeJobs := tx.Model(&job.TranslationJob{}).
+			Select("id").
+			Where("status IN ?", activeJobStatuses)
+
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND job_id IN (?)", job.TranslationSubtaskStatusPending, activeJobs).
+			Where("next_attempt_at IS NULL OR next_attempt_at <= ?", time.Now())
+		if jobID != "" {
+			query = query.Where("job_id = ?", jobID)
+		}
+
+		var subtasks []job.TranslationSubtask
+		if err := query.Order("priority ASC, seq ASC").Limit(1).Find(&subtasks).Error; err != nil {
+			return err
+		}
+		if len(subtasks) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		expiresAt := now.Add(leaseFor)
+		subtask := subtasks[0]
+		err := tx.Model(&job.TranslationSubtask{}).
+			Where("id = ?", subtask.ID).
+			Updates(map[string]interface{}{
+				"status":           job.TranslationSubtaskStatusInProgress,
+				"attempts":         gorm.Expr("attempts + 1"),
+				"lease_owner":      owner,
+				"lease_expires_at": expiresAt,
+				"heartbeat_at":     now,
+				"error_message":    nil,
+				"started_at":       now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		subtask.Status = job.TranslationSubtaskStatusInProgress
+		subtask.Attempts++
+		subtask.LeaseOwner = &owner
+		subtask.LeaseExpiresAt = &expiresAt
+		subtask.HeartbeatAt = &now
+		subtask.ErrorMessage = nil
+		subtask.StartedAt = &now
+		claimed = &subtask
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// RenewLease extends the lease held by owner. It returns ErrSubtaskLeaseLost
+// when the subtask is no longer leased to owner.
+func (r *translationJobRepository) RenewLease(ctx context.Context, id, owner string, leaseFor time.Duration) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&job.TranslationSubtask{}).
+		Where("id = ? AND status = ? AND lease_owner = ?", id, job.TranslationSubtaskStatusInProgress, owner).
+		Updates(map[string]interface{}{
+			"lease_expires_at": now.Add(leaseFor),
+			"heartbeat_at":     now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrSubtaskLeaseLost
+	}
+	return nil
+}
+
+// ReleaseExpiredLeases returns IN_PROGRESS subtasks whose lease has expired to
+// PENDING and reports the distinct jobs they belong to. Subtasks that already
+// used up maxAttempts (e.g. because they keep crashing workers) are
+// dead-lettered instead.
+func (r *translationJobRepository) ReleaseExpiredLeases(ctx context.Context, maxAttempts int) ([]string, error) {
+	var jobIDs []string
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var deadLettered []string
+		err := tx.Raw(`
+			UPDATE translation_subtasks
+			SET status = ?, lease_owner = NULL, lease_expires_at = NULL,
+				error_message = ?, finished_at = ?, updated_at = ?
+			WHERE status = ? AND lease_expires_at < ? AND attempts >= ?
+			RETURNING job_id`,
+			job.TranslationSubtaskStatusDeadLetter,
+			"lease expired after the final attempt",
+			now,
+			now,
+			job.TranslationSubtaskStatusInProgress,
+			now,
+			maxAttempts,
+		).Scan(&deadLettered).Error
+		if err != nil {
+			return err
+		}
+
+		var released []string
+		err = tx.Raw(`
+			UPDATE translation_subtasks
+			SET status = ?, lease_owner = NULL, lease_expires_at = NULL, updated_at = ?
+			WHERE status = ? AND lease_expires_at < ?
+			RETURNING job_id`,
+			job.TranslationSubtaskStatusPending,
+			now,
+			job.TranslationSubtaskStatusInProgress,
+			now,
+		).Scan(&released).Error
+		if err != nil {
+			return err
+		}
+
+		jobIDs = append(deadLettered, released...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(jobIDs))
+	unique := make([]string, 0, len(jobIDs))
+	for _, id := range jobIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
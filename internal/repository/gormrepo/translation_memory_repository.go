@@ -0,0 +1,116 @@
+package gormrepo
+
+import (
+	"context"
+	translationmemory "simple-go/internal/domain/translation_memory"
+	"simple-go/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type translationMemoryRepository struct {
+	db *gorm.DB
+}
+
+func NewTranslationMemoryRepository(db *gorm.DB) repository.TranslationMemoryRepository {
+	return &translationMemoryRepository{db: db}
+}
+
+func (r *translationMemoryRepository) Upsert(ctx context.Context, entries []translationmemory.TranslationMemoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source_lang"}, {Name: "target_lang"}, {Name: "source_hash"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target_text", "novel_id", "chapter_id", "updated_at"}),
+		}).
+		CreateInBatches(entries, 100).Error
+}
+
+func (r *translationMemoryRepository) FindExact(ctx context.Context, sourceLang, targetLang string, hashes []string) ([]translationmemory.TranslationMemoryEntry, error) {
+	var entries []translationmemory.TranslationMemoryEntry
+	if len(hashes) == 0 {
+		return entries, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("source_lang = ? AND target_lang = ? AND source_hash IN ?", sourceLang, targetLang, hashes).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *translationMemoryRepository) FindFuzzy(ctx context.Context, sourceLang, targetLang, text string, minSimilarity float64, limit int) ([]translationmemory.FuzzyMatch, error) {
+	var matches []translationmemory.FuzzyMatch
+
+	// The % operator lets Postgres use the trigram index; its default
+	// threshold (0.3) is below any useful minSimilarity
+	err := r.db.WithContext(ctx).
+		Model(&translationmemory.TranslationMemoryEntry{}).
+		Select("*, similarity(source_text, ?) AS similarity", text).
+		Where("source_lang = ? AND target_lang = ?", sourceLang, targetLang).
+		Where("source_text % ?", text).
+		Where("similarity(source_text, ?) >= ?", text, minSimilarity).
+		Order("similarity DESC").
+		Limit(limit).
+		Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func (r *translationMemoryRepository) FindBestFuzzy(ctx context.Context, sourceLang, targetLang string, texts []string, minSimilarity float64) (map[int]translationmemory.FuzzyMatch, error) {
+	best := make(map[int]translationmemory.FuzzyMatch, len(texts))
+	if len(texts) == 0 {
+		return best, nil
+	}
+
+	var rows []struct {
+		Idx int
+		translationmemory.FuzzyMatch
+	}
+	// One lateral lookup per text keeps the trigram index in use for each of
+	// them; ordinality starts at 1
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT q.idx - 1 AS idx, m.*
+		FROM unnest(ARRAY[?]::text[]) WITH ORDINALITY AS q(text, idx)
+		CROSS JOIN LATERAL (
+			SELECT e.*, similarity(e.source_text, q.text) AS similarity
+			FROM translation_memory_entries e
+			WHERE e.source_lang = ? AND e.target_lang = ?
+				AND e.source_text % q.text
+				AND similarity(e.source_text, q.text) >= ?
+			ORDER BY similarity DESC
+			LIMIT 1
+		) m`, texts, sourceLang, targetLang, minSimilarity).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		best[row.Idx] = row.FuzzyMatch
+	}
+	return best, nil
+}
+
+func (r *translationMemoryRepository) MarkUsed(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&translationmemory.TranslationMemoryEntry{}).
+		Where("id IN ?", ids).
+		UpdateColumns(map[string]interface{}{
+			"usage_count":  gorm.Expr("usage_count + 1"),
+			"last_used_at": time.Now(),
+		}).Error
+}
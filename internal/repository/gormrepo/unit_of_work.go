@@ -61,3 +61,19 @@ func (rp *repoProvider) NovelTag() repository.NovelTagRepository {
 func (rp *repoProvider) TranslationJob() repository.TranslationJobRepository {
 	return NewTranslationJobRepository(rp.db)
 }
+
+func (rp *repoProvider) Webhook() repository.WebhookRepository {
+	return NewWebhookRepository(rp.db)
+}
+
+func (rp *repoProvider) Glossary() repository.GlossaryRepository {
+	return NewGlossaryRepository(rp.db)
+}
+
+func (rp *repoProvider) Quota() repository.QuotaRepository {
+	return NewQuotaRepository(rp.db)
+}
+
+func (rp *repoProvider) EpubImport() repository.EpubImportRepository {
+	return NewEpubImportRepository(rp.db)
+}
@@ -29,6 +29,24 @@ func (r *volumeRepository) CreateTranslation(ctx context.Context, vt *volume.Vol
 	return vt, nil
 }
 
+func (r *volumeRepository) GetTranslation(ctx context.Context, volumeID, lang string) (*volume.VolumeTranslation, error) {
+	var vt volume.VolumeTranslation
+	err := r.db.WithContext(ctx).
+		Where("volume_id = ? AND lang = ?", volumeID, lang).
+		First(&vt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vt, nil
+}
+
+func (r *volumeRepository) UpdateTranslation(ctx context.Context, vt *volume.VolumeTranslation) (*volume.VolumeTranslation, error) {
+	if err := r.db.WithContext(ctx).Save(vt).Error; err != nil {
+		return nil, err
+	}
+	return vt, nil
+}
+
 func (r *volumeRepository) Update(ctx context.Context, v *volume.Volume) (*volume.Volume, error) {
 	if err := r.db.WithContext(ctx).
 		Model(&volume.Volume{}).
@@ -54,6 +72,15 @@ func (r *volumeRepository) GetByID(ctx context.Context, id string) (*volume.Volu
 	return &v, nil
 }
 
+// GetAllByNovelID returns a novel's volumes without chapters or translations
+func (r *volumeRepository) GetAllByNovelID(ctx context.Context, novelID string) ([]volume.Volume, error) {
+	var volumes []volume.Volume
+	if err := r.db.WithContext(ctx).Where("novel_id = ?", novelID).Order("number ASC").Find(&volumes).Error; err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
 func (r *volumeRepository) GetAllWithChaptersByNovelID(ctx context.Context, novelID string) ([]volume.Volume, error) {
 	var volumes []volume.Volume
 	query := r.db.WithContext(ctx).
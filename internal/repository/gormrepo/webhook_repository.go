@@ -0,0 +1,161 @@
+package gormrepo
+
+import (
+	"context"
+	"simple-go/internal/domain/webhook"
+	"simple-go/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) repository.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, w *webhook.WebhookSubscription) (*webhook.WebhookSubscription, error) {
+	if err := r.db.WithContext(ctx).Create(w).Error; err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id string) (*webhook.WebhookSubscription, error) {
+	var w webhook.WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&w, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *webhookRepository) GetAll(ctx context.Context, limit, offset int) ([]webhook.WebhookSubscription, error) {
+	var webhooks []webhook.WebhookSubscription
+
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) GetActive(ctx context.Context) ([]webhook.WebhookSubscription, error) {
+	var webhooks []webhook.WebhookSubscription
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&webhook.WebhookSubscription{}).Count(&count).Error
+	return count, err
+}
+
+func (r *webhookRepository) Update(ctx context.Context, w *webhook.WebhookSubscription) (*webhook.WebhookSubscription, error) {
+	if err := r.db.WithContext(ctx).Save(w).Error; err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&webhook.WebhookSubscription{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delivery log
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []webhook.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(deliveries, 100).Error
+}
+
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, id string) (*webhook.WebhookDelivery, error) {
+	var d webhook.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&d, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *webhookRepository) GetDeliveriesBySubscriptionID(ctx context.Context, subscriptionID string, limit, offset int) ([]webhook.WebhookDelivery, error) {
+	var deliveries []webhook.WebhookDelivery
+
+	query := r.db.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) CountDeliveriesBySubscriptionID(ctx context.Context, subscriptionID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&webhook.WebhookDelivery{}).
+		Where("subscription_id = ?", subscriptionID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, d *webhook.WebhookDelivery) (*webhook.WebhookDelivery, error) {
+	if err := r.db.WithContext(ctx).Save(d).Error; err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, leaseFor time.Duration) ([]webhook.WebhookDelivery, error) {
+	var deliveries []webhook.WebhookDelivery
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries
+		SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(leaseFor),
+		now,
+		webhook.DeliveryStatusPending,
+		now,
+		limit,
+	).Scan(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -16,5 +16,6 @@ type NovelRepository interface {
 
 	CreateTranslation(ctx context.Context, nt *novel.NovelTranslation) (*novel.NovelTranslation, error)
 	GetTranslation(ctx context.Context, novelID, lang string) (*novel.NovelTranslation, error)
+	UpdateTranslation(ctx context.Context, nt *novel.NovelTranslation) (*novel.NovelTranslation, error)
 	DeleteTranslation(ctx context.Context, translationID string) (int64, error)
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"simple-go/internal/domain/quota"
+	"time"
+)
+
+type QuotaRepository interface {
+	RecordUsage(ctx context.Context, u *quota.Usage) error
+	// SumCharacters adds up the characters charged since a point in time; an
+	// empty userID sums over all users
+	SumCharacters(ctx context.Context, userID string, since time.Time) (int, error)
+	// Lock serializes quota checks until the surrounding transaction ends
+	Lock(ctx context.Context) error
+}
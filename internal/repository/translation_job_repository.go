@@ -2,26 +2,71 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"simple-go/internal/domain/job"
+	"time"
 )
 
+// ErrSubtaskLeaseLost is returned when a worker tries to finish a subtask whose
+// lease has expired or been taken over by another worker
+var ErrSubtaskLeaseLost = errors.New("subtask lease lost")
+
 type TranslationJobRepository interface {
 	Create(ctx context.Context, j *job.TranslationJob) (*job.TranslationJob, error)
 	GetByID(ctx context.Context, id string) (*job.TranslationJob, error)
+	GetSummaryByID(ctx context.Context, id string) (*job.TranslationJob, error)
+	// LockByID loads the job row and locks it until the transaction ends
+	LockByID(ctx context.Context, id string) (*job.TranslationJob, error)
+	// GetByNovelAndLang returns the latest job of a novel for the language
 	GetByNovelAndLang(ctx context.Context, novelID, targetLang string) (*job.TranslationJob, error)
+	CreateBatch(ctx context.Context, b *job.TranslationJobBatch) (*job.TranslationJobBatch, error)
+	// GetBatchByID loads a batch with its jobs, without their subtasks
+	GetBatchByID(ctx context.Context, id string) (*job.TranslationJobBatch, error)
 	GetAll(ctx context.Context, limit, offset int, status string) ([]job.TranslationJob, error)
 	GetByNovelID(ctx context.Context, novelID string, limit, offset int) ([]job.TranslationJob, error)
 	Update(ctx context.Context, j *job.TranslationJob) (*job.TranslationJob, error)
 	UpdateStatus(ctx context.Context, id, status string) error
+	UpdatePriority(ctx context.Context, id string, priority int) error
 	UpdateProgress(ctx context.Context, id string, progress, completedSubtasks int) error
+	MarkStarted(ctx context.Context, id string) error
+	MarkFinished(ctx context.Context, id, status string, errorMessage *string) error
+	MarkCancelled(ctx context.Context, id, cancelledBy string) (bool, error)
+	RecordQA(ctx context.Context, id, status string, errors, warnings int) error
+	MarkPublished(ctx context.Context, id string, publishedBy *string) error
+	ResetForRetry(ctx context.Context, id string, totalSubtasks, completedSubtasks int) error
 	Count(ctx context.Context) (int64, error)
+	// CountActive counts pending and in-progress jobs; an empty createdBy
+	// counts those of all users
+	CountActive(ctx context.Context, createdBy string) (int64, error)
 
 	// Subtask operations
 	CreateSubtask(ctx context.Context, subtask *job.TranslationSubtask) (*job.TranslationSubtask, error)
 	CreateSubtasksBatch(ctx context.Context, subtasks []job.TranslationSubtask) error
 	GetSubtaskByID(ctx context.Context, id string) (*job.TranslationSubtask, error)
+	// GetStagedSubtasks returns the done subtasks of a job whose results were
+	// not published yet
+	GetStagedSubtasks(ctx context.Context, jobID string) ([]job.TranslationSubtask, error)
+	CountStagedSubtasks(ctx context.Context, jobID string) (int64, error)
+	// ClearStagedResults drops the staged results of the given subtasks, or of
+	// every subtask of the job when subtaskIDs is empty
+	ClearStagedResults(ctx context.Context, jobID string, subtaskIDs []string) (int64, error)
 	GetSubtasksByJobID(ctx context.Context, jobID string) ([]job.TranslationSubtask, error)
 	UpdateSubtask(ctx context.Context, subtask *job.TranslationSubtask) (*job.TranslationSubtask, error)
 	UpdateSubtaskStatus(ctx context.Context, id, status string) error
+	MarkSubtaskDone(ctx context.Context, id, owner string, result job.SubtaskResult) error
+	MarkSubtaskFailed(ctx context.Context, id, owner, errorMessage string) error
+	MarkSubtaskDeadLettered(ctx context.Context, id, owner, errorMessage string) error
+	ScheduleSubtaskRetry(ctx context.Context, id, owner, errorMessage string, nextAttemptAt time.Time) error
 	CountSubtasksByStatus(ctx context.Context, jobID, status string) (int64, error)
+	CountActiveSubtasks(ctx context.Context, jobID string) (int64, error)
+	SumSubtaskQA(ctx context.Context, jobID string) (errors, warnings int, err error)
+	CountSubtasks(ctx context.Context, jobID string) (int64, error)
+	ResetSubtasks(ctx context.Context, jobID string, statuses []string) (int64, error)
+	CancelActiveSubtasks(ctx context.Context, jobID string) (int64, error)
+
+	// Leasing. An empty jobID claims from any active job. Claiming counts as
+	// an attempt and skips subtasks whose next attempt is still scheduled.
+	ClaimNextSubtask(ctx context.Context, jobID, owner string, leaseFor time.Duration) (*job.TranslationSubtask, error)
+	RenewLease(ctx context.Context, id, owner string, leaseFor time.Duration) error
+	ReleaseExpiredLeases(ctx context.Context, maxAttempts int) ([]string, error)
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	translationmemory "simple-go/internal/domain/translation_memory"
+)
+
+type TranslationMemoryRepository interface {
+	// Upsert stores entries, replacing the target text of segments already known
+	Upsert(ctx context.Context, entries []translationmemory.TranslationMemoryEntry) error
+	FindExact(ctx context.Context, sourceLang, targetLang string, hashes []string) ([]translationmemory.TranslationMemoryEntry, error)
+	// FindFuzzy returns the entries most similar to text, best first
+	FindFuzzy(ctx context.Context, sourceLang, targetLang, text string, minSimilarity float64, limit int) ([]translationmemory.FuzzyMatch, error)
+	// FindBestFuzzy returns the most similar entry for each of texts in one
+	// query, keyed by the text's index. Texts without a match are left out.
+	FindBestFuzzy(ctx context.Context, sourceLang, targetLang string, texts []string, minSimilarity float64) (map[int]translationmemory.FuzzyMatch, error)
+	MarkUsed(ctx context.Context, ids []string) error
+}
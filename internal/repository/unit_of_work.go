@@ -20,4 +20,8 @@ type RepositoryProvider interface {
 	Tag() TagRepository
 	NovelTag() NovelTagRepository
 	TranslationJob() TranslationJobRepository
+	Webhook() WebhookRepository
+	Glossary() GlossaryRepository
+	Quota() QuotaRepository
+	EpubImport() EpubImportRepository
 }
@@ -8,9 +8,12 @@ import (
 type VolumeRepository interface {
 	Create(ctx context.Context, v *volume.Volume) (*volume.Volume, error)
 	CreateTranslation(ctx context.Context, vt *volume.VolumeTranslation) (*volume.VolumeTranslation, error)
+	GetTranslation(ctx context.Context, volumeID, lang string) (*volume.VolumeTranslation, error)
+	UpdateTranslation(ctx context.Context, vt *volume.VolumeTranslation) (*volume.VolumeTranslation, error)
 	Update(ctx context.Context, v *volume.Volume) (*volume.Volume, error)
 
 	GetByID(ctx context.Context, id string) (*volume.Volume, error)
+	GetAllByNovelID(ctx context.Context, novelID string) ([]volume.Volume, error)
 	GetAllWithChaptersByNovelID(ctx context.Context, novelID string) ([]volume.Volume, error)
 	GetAllWithChaptersByNovelIDAndLang(ctx context.Context, novelID, lang string) ([]volume.Volume, error)
 	GetNextVolumeID(ctx context.Context, novelID string, currentNumber int) (*string, error)
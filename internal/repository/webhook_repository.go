@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"simple-go/internal/domain/webhook"
+	"time"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, w *webhook.WebhookSubscription) (*webhook.WebhookSubscription, error)
+	GetByID(ctx context.Context, id string) (*webhook.WebhookSubscription, error)
+	GetAll(ctx context.Context, limit, offset int) ([]webhook.WebhookSubscription, error)
+	GetActive(ctx context.Context) ([]webhook.WebhookSubscription, error)
+	Count(ctx context.Context) (int64, error)
+	Update(ctx context.Context, w *webhook.WebhookSubscription) (*webhook.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) error
+
+	// Delivery log
+	CreateDeliveries(ctx context.Context, deliveries []webhook.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id string) (*webhook.WebhookDelivery, error)
+	GetDeliveriesBySubscriptionID(ctx context.Context, subscriptionID string, limit, offset int) ([]webhook.WebhookDelivery, error)
+	CountDeliveriesBySubscriptionID(ctx context.Context, subscriptionID string) (int64, error)
+	UpdateDelivery(ctx context.Context, d *webhook.WebhookDelivery) (*webhook.WebhookDelivery, error)
+	// ClaimDueDeliveries picks pending deliveries whose next attempt is due and
+	// pushes their next_attempt_at out by leaseFor so no other process sends them
+	ClaimDueDeliveries(ctx context.Context, limit int, leaseFor time.Duration) ([]webhook.WebhookDelivery, error)
+}
@@ -81,19 +81,29 @@ func setupRoutes(router *gin.Engine, cfg *server.Config) {
 		novels.GET("", cfg.NovelHandler.GetAll)
 		novels.GET("/:id", cfg.NovelHandler.GetByID)
 		novels.GET("/:id/volumes", cfg.NovelHandler.GetNovelVolumes)
+		novels.GET("/:id/export.epub", cfg.NovelHandler.ExportEpub)
 		novels.Use(middleware.JWTAuth(cfg.JWTManager))
 		{
 			novels.POST("", middleware.RequirePermission("novel", "create", cfg.Enforcer, roleGetter), cfg.NovelHandler.Create)
 			novels.POST("/epub", middleware.RequirePermission("novel", "create", cfg.Enforcer, roleGetter), cfg.NovelHandler.UploadEpub)
+			novels.POST("/epub/preview", middleware.RequirePermission("novel", "create", cfg.Enforcer, roleGetter), cfg.NovelHandler.PreviewEpub)
+			novels.POST("/:id/epub", middleware.RequirePermission("novel", "update", cfg.Enforcer, roleGetter), cfg.EpubImportHandler.Merge)
 			novels.DELETE("/:id", middleware.RequirePermission("novel", "delete", cfg.Enforcer, roleGetter), cfg.NovelHandler.Delete)
 			novels.PATCH("/:id/cover", middleware.RequirePermission("novel", "update", cfg.Enforcer, roleGetter), cfg.NovelHandler.UpdateCoverMedia)
 
 			novels.POST("/translations", middleware.RequirePermission("novel_translation", "create", cfg.Enforcer, roleGetter), cfg.NovelHandler.CreateTranslation)
 			novels.DELETE("/translations/:translation_id", middleware.RequirePermission("novel_translation", "delete", cfg.Enforcer, roleGetter), cfg.NovelHandler.DeleteTranslation)
+
+			novels.GET("/:id/glossary", middleware.RequirePermission("glossary", "list", cfg.Enforcer, roleGetter), cfg.GlossaryHandler.GetByNovelID)
+			novels.POST("/:id/glossary", middleware.RequirePermission("glossary", "create", cfg.Enforcer, roleGetter), cfg.GlossaryHandler.Create)
+			novels.POST("/:id/glossary/import", middleware.RequirePermission("glossary", "create", cfg.Enforcer, roleGetter), cfg.GlossaryHandler.Import)
+			novels.PATCH("/:id/glossary/:term_id", middleware.RequirePermission("glossary", "update", cfg.Enforcer, roleGetter), cfg.GlossaryHandler.Update)
+			novels.DELETE("/:id/glossary/:term_id", middleware.RequirePermission("glossary", "delete", cfg.Enforcer, roleGetter), cfg.GlossaryHandler.Delete)
 		}
 
 		chapters := v1.Group("/chapters")
-		chapters.GET("/:id", cfg.ChapterHandler.GetByID)
+		chapters.GET("/:id", middleware.OptionalJWTAuth(cfg.JWTManager), middleware.LoadPermission("chapter_translation", "review", cfg.Enforcer, roleGetter), cfg.ChapterHandler.GetByID)
+		chapters.GET("/:id/bilingual", middleware.JWTAuth(cfg.JWTManager), middleware.LoadPermission("chapter_translation", "review", cfg.Enforcer, roleGetter), cfg.ChapterHandler.GetBilingual)
 		chapters.Use(middleware.JWTAuth(cfg.JWTManager))
 		{
 
@@ -102,15 +112,60 @@ func setupRoutes(router *gin.Engine, cfg *server.Config) {
 
 			chapters.POST("/translations", middleware.RequirePermission("chapter_translation", "create", cfg.Enforcer, roleGetter), cfg.ChapterHandler.CreateTranslation)
 			chapters.DELETE("/translations/:id", middleware.RequirePermission("chapter_translation", "delete", cfg.Enforcer, roleGetter), cfg.ChapterHandler.DeleteTranslation)
+
+			chapters.GET("/reviews", middleware.RequirePermission("chapter_translation", "review", cfg.Enforcer, roleGetter), cfg.ChapterHandler.GetReviewQueue)
+			chapters.PATCH("/translations/:id/reviewer", middleware.RequirePermission("chapter_translation", "assign", cfg.Enforcer, roleGetter), cfg.ChapterHandler.AssignReviewer)
+			chapters.PATCH("/translations/:id/review", middleware.RequirePermission("chapter_translation", "review", cfg.Enforcer, roleGetter), cfg.ChapterHandler.ReviewTranslation)
+			chapters.PATCH("/translations/:id/review/override", middleware.RequirePermission("chapter_translation", "assign", cfg.Enforcer, roleGetter), cfg.ChapterHandler.OverrideReview)
+
+			chapters.GET("/revisions", middleware.RequirePermission("chapter_translation", "review", cfg.Enforcer, roleGetter), cfg.ChapterHandler.GetRevisions)
+			chapters.GET("/translations/:id/revision", middleware.RequirePermission("chapter_translation", "review", cfg.Enforcer, roleGetter), cfg.ChapterHandler.GetRevision)
+			chapters.PATCH("/translations/:id/revision", middleware.RequirePermission("chapter_translation", "review", cfg.Enforcer, roleGetter), cfg.ChapterHandler.ReviewRevision)
 		}
 
 		jobs := v1.Group("/translation-jobs")
 		jobs.Use(middleware.JWTAuth(cfg.JWTManager))
 		{
-			jobs.POST("", middleware.RequirePermission("translation_job", "create", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.CreateTranslationJob)
+			jobs.POST("", middleware.RequirePermission("translation_job", "create", cfg.Enforcer, roleGetter), middleware.LoadPermission("translation_job", "prioritize", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.CreateTranslationJob)
+			jobs.POST("/estimate", middleware.RequirePermission("translation_job", "create", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.EstimateTranslationJob)
+			jobs.POST("/batches", middleware.RequirePermission("translation_job", "create", cfg.Enforcer, roleGetter), middleware.LoadPermission("translation_job", "prioritize", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.CreateTranslationJobBatch)
+			jobs.GET("/batches/:id", middleware.RequirePermission("translation_job", "read", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.GetBatchByID)
 			jobs.GET("", middleware.RequirePermission("translation_job", "list", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.GetAllJobs)
 			jobs.GET("/:id", middleware.RequirePermission("translation_job", "read", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.GetJobByID)
+			jobs.GET("/:id/events", middleware.RequirePermission("translation_job", "read", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.StreamJobEvents)
+			jobs.GET("/:id/qa", middleware.RequirePermission("translation_job", "read", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.GetQAReport)
 			jobs.PUT("/:id/cancel", middleware.RequirePermission("translation_job", "update", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.CancelJob)
+			jobs.PATCH("/:id/priority", middleware.RequirePermission("translation_job", "prioritize", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.SetPriority)
+			jobs.POST("/:id/retry", middleware.RequirePermission("translation_job", "update", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.RetryJob)
+			jobs.POST("/:id/publish", middleware.RequirePermission("translation_job", "publish", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.PublishJob)
+			jobs.POST("/:id/discard", middleware.RequirePermission("translation_job", "publish", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.DiscardStaging)
+			jobs.GET("/:id/subtasks/:subtask_id/result", middleware.RequirePermission("translation_job", "publish", cfg.Enforcer, roleGetter), cfg.TranslationJobHandler.GetStagedResult)
+		}
+
+		memory := v1.Group("/translation-memory")
+		memory.Use(middleware.JWTAuth(cfg.JWTManager))
+		{
+			memory.GET("/lookup", middleware.RequirePermission("translation_memory", "read", cfg.Enforcer, roleGetter), cfg.MemoryHandler.Lookup)
+			memory.POST("/import", middleware.RequirePermission("translation_memory", "create", cfg.Enforcer, roleGetter), cfg.MemoryHandler.Import)
+		}
+
+		webhooks := v1.Group("/webhooks")
+		webhooks.Use(middleware.JWTAuth(cfg.JWTManager))
+		{
+			webhooks.POST("", middleware.RequirePermission("webhook", "create", cfg.Enforcer, roleGetter), cfg.WebhookHandler.Create)
+			webhooks.GET("", middleware.RequirePermission("webhook", "list", cfg.Enforcer, roleGetter), cfg.WebhookHandler.GetAll)
+			webhooks.GET("/:id", middleware.RequirePermission("webhook", "read", cfg.Enforcer, roleGetter), cfg.WebhookHandler.GetByID)
+			webhooks.PUT("/:id", middleware.RequirePermission("webhook", "update", cfg.Enforcer, roleGetter), cfg.WebhookHandler.Update)
+			webhooks.DELETE("/:id", middleware.RequirePermission("webhook", "delete", cfg.Enforcer, roleGetter), cfg.WebhookHandler.Delete)
+			webhooks.GET("/:id/deliveries", middleware.RequirePermission("webhook", "read", cfg.Enforcer, roleGetter), cfg.WebhookHandler.GetDeliveries)
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", middleware.RequirePermission("webhook", "update", cfg.Enforcer, roleGetter), cfg.WebhookHandler.Redeliver)
+		}
+
+		imports := v1.Group("/imports")
+		imports.Use(middleware.JWTAuth(cfg.JWTManager))
+		{
+			imports.POST("", middleware.RequirePermission("novel_import", "create", cfg.Enforcer, roleGetter), cfg.EpubImportHandler.Create)
+			imports.GET("/:id", middleware.RequirePermission("novel_import", "read", cfg.Enforcer, roleGetter), cfg.EpubImportHandler.GetByID)
 		}
 
 		// Miscellaneous routes
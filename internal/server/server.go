@@ -27,4 +27,8 @@ type Config struct {
 	UserService           *service.UserService
 	TranslationJobHandler *handler.TranslationJobHandler
 	MiscellaneousHandler  *handler.MiscellaneousHandler
+	WebhookHandler        *handler.WebhookHandler
+	EpubImportHandler     *handler.EpubImportHandler
+	GlossaryHandler       *handler.GlossaryHandler
+	MemoryHandler         *handler.TranslationMemoryHandler
 }
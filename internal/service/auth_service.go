@@ -18,6 +18,7 @@ type AuthService struct {
 	roleRepo          repository.RoleRepository
 	jwtManager        *auth.JWTManager
 	permissionService *PermissionService
+	quotaService      *QuotaService
 }
 
 func NewAuthService(
@@ -26,6 +27,7 @@ func NewAuthService(
 	roleRepo repository.RoleRepository,
 	jwtManager *auth.JWTManager,
 	permissionService *PermissionService,
+	quotaService *QuotaService,
 ) *AuthService {
 	return &AuthService{
 		uow:               uow,
@@ -33,6 +35,7 @@ func NewAuthService(
 		roleRepo:          roleRepo,
 		jwtManager:        jwtManager,
 		permissionService: permissionService,
+		quotaService:      quotaService,
 	}
 }
 
@@ -203,5 +206,13 @@ func (s *AuthService) GetProfile(ctx context.Context, userID string) (*user.User
 		return nil, errors.New("unable to retrieve user permissions")
 	}
 
-	return u.ToProfileResponse(permissions), nil
+	profile := u.ToProfileResponse(permissions)
+
+	// The quota is informational; a failed lookup must not break the profile
+	usage, err := s.quotaService.GetUsage(ctx, u.ID)
+	if err != nil {
+		logger.Error(err, "failed to get quota usage for profile")
+	}
+	profile.Quota = usage
+	return profile, nil
 }
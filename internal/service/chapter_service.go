@@ -2,27 +2,44 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/role"
 	"simple-go/internal/repository"
+	"simple-go/pkg/epub"
 	"simple-go/pkg/logger"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrSameBilingualLanguage is returned when a bilingual view is asked to pair
+// a translation with itself
+var ErrSameBilingualLanguage = errors.New("source and target language must differ")
+
 // ChapterService handles chapter-related business logic
 type ChapterService struct {
 	uow         repository.UnitOfWork
 	chapterRepo repository.ChapterRepository
+	userRepo    repository.UserRepository
+	memory      *TranslationMemoryService
 }
 
 func NewChapterService(
 	uow repository.UnitOfWork,
 	chapterRepo repository.ChapterRepository,
+	userRepo repository.UserRepository,
+	memory *TranslationMemoryService,
 ) *ChapterService {
 	return &ChapterService{
 		uow:         uow,
 		chapterRepo: chapterRepo,
+		userRepo:    userRepo,
+		memory:      memory,
 	}
 }
 
@@ -165,6 +182,9 @@ func (s *ChapterService) CreateTranslation(ctx context.Context, dto chapter.Crea
 		return nil, errors.New("unable to create translation")
 	}
 
+	// Translations entered by hand are accepted as they are
+	learnChapterTranslation(ctx, s.memory, dto.ChapterID, dto.Lang)
+
 	return createdTranslation, nil
 }
 
@@ -181,3 +201,378 @@ func (s *ChapterService) DeleteTranslation(ctx context.Context, id string) error
 	}
 	return nil
 }
+
+// GetReviewQueue lists chapter translations for reviewers. Without a status
+// filter it returns everything still waiting for a decision.
+func (s *ChapterService) GetReviewQueue(ctx context.Context, userID string, query chapter.ReviewQueueQueryDTO, limit, offset int) ([]chapter.ChapterTranslationReviewDTO, int64, error) {
+	filter := chapter.ReviewQueueFilter{
+		Statuses: []string{chapter.TranslationStatusMachineDraft, chapter.TranslationStatusInReview},
+		Lang:     query.Lang,
+		NovelID:  query.NovelID,
+	}
+	if query.Status != "" {
+		filter.Statuses = []string{query.Status}
+	}
+	switch query.Assignee {
+	case "":
+	case "me":
+		filter.ReviewerID = userID
+	case "none":
+		filter.Unassigned = true
+	default:
+		filter.ReviewerID = query.Assignee
+	}
+
+	translations, err := s.chapterRepo.GetReviewQueue(ctx, filter, limit, offset)
+	if err != nil {
+		logger.Error(err, "failed to get review queue")
+		return nil, 0, errors.New("unable to retrieve review queue")
+	}
+
+	total, err := s.chapterRepo.CountReviewQueue(ctx, filter)
+	if err != nil {
+		logger.Error(err, "failed to count review queue")
+		return nil, 0, errors.New("unable to retrieve review queue")
+	}
+
+	return chapter.MapTranslationsToReviewDTOs(translations), total, nil
+}
+
+// AssignReviewer hands a translation to a user with the translator role and
+// puts it in review. Approved translations are final and cannot be reassigned.
+func (s *ChapterService) AssignReviewer(ctx context.Context, translationID string, dto chapter.AssignReviewerDTO) (*chapter.ChapterTranslationReviewDTO, error) {
+	ct, err := s.getTranslation(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+	if ct.IsApproved() {
+		return nil, errors.New("translation is already approved")
+	}
+
+	roles, err := s.userRepo.GetRoles(ctx, dto.ReviewerID)
+	if err != nil {
+		logger.Error(err, "failed to get reviewer roles")
+		return nil, errors.New("unable to assign reviewer")
+	}
+	isTranslator := false
+	for _, r := range roles {
+		if r.Name == role.RoleTranslator {
+			isTranslator = true
+			break
+		}
+	}
+	if !isTranslator {
+		return nil, errors.New("reviewer must have the translator role")
+	}
+
+	ct.ReviewerID = &dto.ReviewerID
+	ct.Status = chapter.TranslationStatusInReview
+
+	updated, err := s.chapterRepo.UpdateTranslation(ctx, ct)
+	if err != nil {
+		logger.Error(err, "failed to assign reviewer")
+		return nil, errors.New("unable to assign reviewer")
+	}
+
+	res := chapter.MapTranslationToReviewDTO(*updated)
+	return &res, nil
+}
+
+// ReviewTranslation approves or rejects a translation in review. Only the
+// assigned reviewer may decide unless override is set. Approved text is
+// added to the translation memory.
+func (s *ChapterService) ReviewTranslation(ctx context.Context, translationID, userID string, override bool, dto chapter.ReviewChapterTranslationDTO) (*chapter.ChapterTranslationReviewDTO, error) {
+	ct, err := s.getTranslation(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+	if ct.Status != chapter.TranslationStatusInReview {
+		return nil, errors.New("translation is not in review")
+	}
+	if !override && (ct.ReviewerID == nil || *ct.ReviewerID != userID) {
+		return nil, errors.New("translation is assigned to another reviewer")
+	}
+
+	now := time.Now()
+	ct.ReviewedBy = &userID
+	ct.ReviewedAt = &now
+	ct.ReviewNote = dto.Note
+
+	switch dto.Action {
+	case "approve":
+		ct.Status = chapter.TranslationStatusApproved
+		if dto.Title != nil {
+			ct.Title = *dto.Title
+		}
+		if dto.Content != nil {
+			ct.Content = *dto.Content
+		}
+	case "reject":
+		ct.Status = chapter.TranslationStatusRejected
+	}
+
+	updated, err := s.chapterRepo.UpdateTranslation(ctx, ct)
+	if err != nil {
+		logger.Error(err, "failed to review chapter translation")
+		return nil, errors.New("unable to review translation")
+	}
+
+	if updated.IsApproved() {
+		learnChapterTranslation(ctx, s.memory, updated.ChapterID, updated.Lang)
+	}
+
+	res := chapter.MapTranslationToReviewDTO(*updated)
+	return &res, nil
+}
+
+// GetRevisions lists machine output waiting to replace approved or human
+// translations
+func (s *ChapterService) GetRevisions(ctx context.Context, query chapter.RevisionQueryDTO, limit, offset int) ([]chapter.ChapterTranslationRevisionDTO, int64, error) {
+	filter := chapter.ReviewQueueFilter{Lang: query.Lang, NovelID: query.NovelID}
+
+	revisions, err := s.chapterRepo.GetRevisions(ctx, filter, limit, offset)
+	if err != nil {
+		logger.Error(err, "failed to get translation revisions")
+		return nil, 0, errors.New("unable to retrieve translation revisions")
+	}
+
+	total, err := s.chapterRepo.CountRevisions(ctx, filter)
+	if err != nil {
+		logger.Error(err, "failed to count translation revisions")
+		return nil, 0, errors.New("unable to retrieve translation revisions")
+	}
+
+	return chapter.MapRevisionsToDTOs(revisions), total, nil
+}
+
+// GetRevision returns the pending revision of a translation with its content
+func (s *ChapterService) GetRevision(ctx context.Context, translationID string) (*chapter.ChapterTranslationRevisionDTO, error) {
+	rev, err := s.getRevision(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := chapter.MapRevisionToDTO(*rev)
+	return &res, nil
+}
+
+// ReviewRevision decides on the pending revision of a translation. Approving
+// it replaces the translation's text, which stays approved; rejecting it
+// discards the revision. Either way the revision is removed.
+func (s *ChapterService) ReviewRevision(ctx context.Context, translationID, userID string, dto chapter.ReviewChapterTranslationDTO) (*chapter.ChapterTranslationReviewDTO, error) {
+	rev, err := s.getRevision(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := s.getTranslation(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *chapter.ChapterTranslation
+	err = s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		// The revision is claimed by deleting it, so that two concurrent
+		// decisions are not both applied
+		deleted, err := provider.Chapter().DeleteRevision(ctx, translationID)
+		if err != nil {
+			logger.Error(err, "failed to delete translation revision")
+			return errors.New("unable to review revision")
+		}
+		if deleted == 0 {
+			return errors.New("translation revision not found")
+		}
+		if dto.Action == "reject" {
+			updated = ct
+			return nil
+		}
+
+		now := time.Now()
+		ct.Title = rev.Title
+		ct.Content = rev.Content
+		if dto.Title != nil {
+			ct.Title = *dto.Title
+		}
+		if dto.Content != nil {
+			ct.Content = *dto.Content
+		}
+		ct.Status = chapter.TranslationStatusApproved
+		ct.Origin = chapter.TranslationOriginMachine
+		ct.SourceLang = rev.SourceLang
+		ct.PivotLang = rev.PivotLang
+		ct.Outdated = false
+		ct.ReviewedBy = &userID
+		ct.ReviewedAt = &now
+		ct.ReviewNote = dto.Note
+
+		if updated, err = provider.Chapter().UpdateTranslation(ctx, ct); err != nil {
+			logger.Error(err, "failed to apply translation revision")
+			return errors.New("unable to review revision")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.Action == "approve" {
+		learnChapterTranslation(ctx, s.memory, updated.ChapterID, updated.Lang)
+	}
+
+	res := chapter.MapTranslationToReviewDTO(*updated)
+	return &res, nil
+}
+
+func (s *ChapterService) getRevision(ctx context.Context, translationID string) (*chapter.ChapterTranslationRevision, error) {
+	rev, err := s.chapterRepo.GetRevision(ctx, translationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("translation revision not found")
+		}
+		logger.Error(err, "failed to get translation revision")
+		return nil, errors.New("unable to retrieve translation revision")
+	}
+	return rev, nil
+}
+
+func (s *ChapterService) getTranslation(ctx context.Context, id string) (*chapter.ChapterTranslation, error) {
+	ct, err := s.chapterRepo.GetTranslationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("chapter translation not found")
+		}
+		logger.Error(err, "failed to get chapter translation")
+		return nil, errors.New("unable to retrieve chapter translation")
+	}
+	return ct, nil
+}
+
+// GetBilingual pairs the segments of a chapter's source and target
+// translations. The alignment is stored and only recomputed when one of the
+// two texts has changed since. Unapproved target translations are only
+// available when includeUnapproved is set.
+func (s *ChapterService) GetBilingual(ctx context.Context, chapterID string, query chapter.BilingualQueryDTO, includeUnapproved bool) (*chapter.BilingualChapterDTO, error) {
+	if strings.EqualFold(query.Source, query.Target) {
+		return nil, ErrSameBilingualLanguage
+	}
+
+	c, err := s.chapterRepo.GetByID(ctx, chapterID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("chapter not found")
+		}
+		logger.Error(err, "failed to get chapter by ID")
+		return nil, errors.New("unable to retrieve chapter")
+	}
+	if !includeUnapproved {
+		c.Translations = chapter.ApprovedTranslations(c.Translations)
+	}
+
+	var source, target *chapter.ChapterTranslation
+	for i := range c.Translations {
+		switch c.Translations[i].Lang {
+		case query.Source:
+			source = &c.Translations[i]
+		case query.Target:
+			target = &c.Translations[i]
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("chapter has no %s translation", query.Source)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("chapter has no %s translation", query.Target)
+	}
+
+	sourceSegments := epub.TranslatableSegments(epub.SegmentHTML(source.Content))
+	targetSegments := epub.TranslatableSegments(epub.SegmentHTML(target.Content))
+	pairs := s.alignment(ctx, chapterID, source, target, sourceSegments, targetSegments)
+
+	res := &chapter.BilingualChapterDTO{
+		ChapterID:    chapterID,
+		SourceLang:   source.Lang,
+		TargetLang:   target.Lang,
+		TargetStatus: target.Status,
+		Title: chapter.BilingualSegmentDTO{
+			Source: []string{source.Title},
+			Target: []string{target.Title},
+		},
+		Segments: make([]chapter.BilingualSegmentDTO, len(pairs)),
+	}
+	for i, p := range pairs {
+		seg := chapter.BilingualSegmentDTO{
+			Seq:    i + 1,
+			Source: make([]string, 0, len(p.Source)),
+			Target: make([]string, 0, len(p.Target)),
+		}
+		for _, idx := range p.Source {
+			seg.Source = append(seg.Source, sourceSegments[idx])
+		}
+		for _, idx := range p.Target {
+			seg.Target = append(seg.Target, targetSegments[idx])
+		}
+		res.Segments[i] = seg
+	}
+
+	return res, nil
+}
+
+// alignment returns the stored alignment of two translations if it was made
+// from their current texts and is not outdated, and otherwise aligns them
+// again and stores the result. Storing is best effort; the fresh alignment is
+// returned either way.
+func (s *ChapterService) alignment(ctx context.Context, chapterID string, source, target *chapter.ChapterTranslation, sourceSegments, targetSegments []string) []chapter.SegmentPair {
+	sourceHash := contentHash(source.Content)
+	targetHash := contentHash(target.Content)
+
+	stored, err := s.chapterRepo.GetAlignment(ctx, chapterID, source.Lang, target.Lang)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error(err, "failed to get chapter alignment")
+	}
+	if stored != nil && !stored.Outdated && stored.SourceHash == sourceHash && stored.TargetHash == targetHash &&
+		pairsInRange(stored.Pairs, len(sourceSegments), len(targetSegments)) {
+		return stored.Pairs
+	}
+
+	aligned := epub.AlignSegments(sourceSegments, targetSegments)
+	pairs := make([]chapter.SegmentPair, len(aligned))
+	for i, a := range aligned {
+		pairs[i] = chapter.SegmentPair{Source: a.Source, Target: a.Target}
+	}
+
+	err = s.chapterRepo.SaveAlignment(ctx, &chapter.ChapterAlignment{
+		ChapterID:  chapterID,
+		SourceLang: source.Lang,
+		TargetLang: target.Lang,
+		SourceHash: sourceHash,
+		TargetHash: targetHash,
+		Pairs:      pairs,
+	})
+	if err != nil {
+		logger.Error(err, "failed to save chapter alignment")
+	}
+
+	return pairs
+}
+
+// pairsInRange guards against alignments stored before a change in how
+// content is segmented
+func pairsInRange(pairs []chapter.SegmentPair, sourceCount, targetCount int) bool {
+	for _, p := range pairs {
+		for _, idx := range p.Source {
+			if idx >= sourceCount {
+				return false
+			}
+		}
+		for _, idx := range p.Target {
+			if idx >= targetCount {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	epubimport "simple-go/internal/domain/epub_import"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+	"simple-go/internal/repository"
+	"simple-go/pkg/epub/transformer"
+	"simple-go/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// EpubImportOptions tunes the background import of EPUB files
+type EpubImportOptions struct {
+	PollInterval time.Duration
+	// Lease is how long a claimed import is hidden from other workers; it is
+	// renewed after every chunk
+	Lease time.Duration
+	// ChunkSize is how many chapters are saved per transaction
+	ChunkSize int
+	// MaxAttempts is how often an interrupted import is resumed before it fails
+	MaxAttempts int
+}
+
+// EpubImportService imports uploaded EPUBs in the background, as new novels
+// or merged into existing ones. The API only stores the file; the worker's
+// import loop parses it and saves the novel in small transactions, recording
+// its progress as it goes.
+type EpubImportService struct {
+	uow        repository.UnitOfWork
+	importRepo repository.EpubImportRepository
+	novelSrvc  *NovelService
+	opts       EpubImportOptions
+}
+
+func NewEpubImportService(
+	uow repository.UnitOfWork,
+	importRepo repository.EpubImportRepository,
+	novelSrvc *NovelService,
+	opts EpubImportOptions,
+) *EpubImportService {
+	return &EpubImportService{
+		uow:        uow,
+		importRepo: importRepo,
+		novelSrvc:  novelSrvc,
+		opts:       opts,
+	}
+}
+
+// CreateImport stores an uploaded EPUB and queues it for import
+func (s *EpubImportService) CreateImport(ctx context.Context, userID, fileName string, fileBytes []byte) (*epubimport.EpubImportResponseDTO, error) {
+	return s.queue(ctx, &epubimport.EpubImport{
+		CreatedBy: userID,
+		Kind:      epubimport.EpubImportKindImport,
+		FileName:  fileName,
+	}, fileBytes)
+}
+
+// CreateMerge stores a newer copy of a novel's EPUB and queues it to be
+// merged into the novel, see epubMerge
+func (s *EpubImportService) CreateMerge(ctx context.Context, userID, novelID, fileName string, fileBytes []byte, updateContent bool) (*epubimport.EpubImportResponseDTO, error) {
+	if _, err := s.novelSrvc.novelRepo.GetByID(ctx, novelID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("novel not found")
+		}
+		logger.Error(err, "failed to get novel by ID")
+		return nil, errors.New("unable to retrieve novel")
+	}
+
+	return s.queue(ctx, &epubimport.EpubImport{
+		CreatedBy:     userID,
+		Kind:          epubimport.EpubImportKindMerge,
+		FileName:      fileName,
+		NovelID:       &novelID,
+		UpdateContent: updateContent,
+	}, fileBytes)
+}
+
+func (s *EpubImportService) queue(ctx context.Context, i *epubimport.EpubImport, fileBytes []byte) (*epubimport.EpubImportResponseDTO, error) {
+	// An EPUB is a ZIP archive; anything else would only fail in the worker
+	if !bytes.HasPrefix(fileBytes, []byte("PK\x03\x04")) {
+		return nil, errors.New("file is not an EPUB archive")
+	}
+
+	i.FileSize = int64(len(fileBytes))
+	i.Status = epubimport.EpubImportStatusPending
+	i.Stage = epubimport.EpubImportStageQueued
+	i.File = &epubimport.EpubImportFile{Data: fileBytes}
+
+	created, err := s.importRepo.Create(ctx, i)
+	if err != nil {
+		logger.Error(err, "failed to create epub import")
+		return nil, errors.New("unable to create epub import")
+	}
+
+	response := epubimport.MapEpubImportToDTO(*created)
+	return &response, nil
+}
+
+// GetByID returns the import if userID created it. Other users' imports are
+// reported as not found, so their IDs cannot be probed.
+func (s *EpubImportService) GetByID(ctx context.Context, id, userID string) (*epubimport.EpubImportResponseDTO, error) {
+	i, err := s.importRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("epub import not found")
+		}
+		logger.Error(err, "failed to get epub import")
+		return nil, errors.New("unable to retrieve epub import")
+	}
+	if i.CreatedBy != userID {
+		return nil, errors.New("epub import not found")
+	}
+
+	response := epubimport.MapEpubImportToDTO(*i)
+	return &response, nil
+}
+
+// RunImportLoop imports queued EPUBs one at a time until ctx is cancelled.
+// An import cut short by cancellation keeps its progress and is resumed once
+// its lease expires.
+func (s *EpubImportService) RunImportLoop(ctx context.Context) {
+	logger.Info("EPUB import loop started")
+
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("EPUB import loop stopped")
+			return
+		case <-ticker.C:
+			for s.importNext(ctx) {
+			}
+		}
+	}
+}
+
+// importNext claims and runs one import, reporting whether there was one
+func (s *EpubImportService) importNext(ctx context.Context) bool {
+	i, err := s.importRepo.ClaimNext(ctx, s.leaseUntil())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error(err, "failed to claim epub import")
+		}
+		return false
+	}
+	if i == nil {
+		return false
+	}
+
+	if i.Attempts > s.opts.MaxAttempts {
+		s.fail(ctx, i, fmt.Errorf("import was interrupted %d times", i.Attempts-1))
+		return true
+	}
+
+	logger.Info(fmt.Sprintf("Importing EPUB %s (%s), attempt %d", i.ID, i.FileName, i.Attempts))
+	if err := s.runImport(ctx, i); err != nil {
+		if ctx.Err() != nil {
+			logger.Warn(fmt.Sprintf("EPUB import %s interrupted, it will resume after its lease expires", i.ID))
+			return false
+		}
+		if errors.Is(err, repository.ErrImportLeaseLost) {
+			s.warnLeaseLost(i)
+			return true
+		}
+		s.fail(ctx, i, err)
+		return true
+	}
+
+	if err := s.importRepo.MarkCompleted(ctx, i.ID, i.Attempts); err != nil {
+		if errors.Is(err, repository.ErrImportLeaseLost) {
+			s.warnLeaseLost(i)
+			return true
+		}
+		logger.Error(err, "failed to mark epub import completed")
+		return true
+	}
+	s.deleteFile(ctx, i.ID)
+	logger.Info(fmt.Sprintf("EPUB import %s completed: novel %s", i.ID, *i.NovelID))
+	return true
+}
+
+// runImport parses the file and saves whatever an earlier attempt did not:
+// the novel with its volumes in one transaction, then the chapters in chunks
+func (s *EpubImportService) runImport(ctx context.Context, i *epubimport.EpubImport) error {
+	fileBytes, err := s.importRepo.GetFile(ctx, i.ID)
+	if err != nil {
+		return fmt.Errorf("unable to load uploaded file: %w", err)
+	}
+
+	result, err := s.novelSrvc.ProcessEpubUpload(ctx, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	if err := s.importRepo.MarkParsed(ctx, i.ID, i.Attempts, string(result.SourceType), len(result.Chapters), s.leaseUntil()); err != nil {
+		return fmt.Errorf("unable to record parse result: %w", err)
+	}
+
+	if i.Kind == epubimport.EpubImportKindMerge {
+		return s.runMerge(ctx, i, result)
+	}
+
+	persist := &epubPersistence{
+		ctx:       ctx,
+		creatorID: i.CreatedBy,
+		result:    result,
+		mediaSrvc: s.novelSrvc.mediaSrvc,
+	}
+
+	if i.NovelID == nil {
+		persist.uploadCoverImage()
+		err = s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+			persist.provider = provider
+			if err := persist.createNovelStructure(); err != nil {
+				return err
+			}
+			return provider.EpubImport().MarkNovelCreated(ctx, i.ID, i.Attempts, persist.novel.ID, s.leaseUntil())
+		})
+		if err != nil {
+			return err
+		}
+		i.NovelID = &persist.novel.ID
+	} else if err := s.resumeNovel(ctx, persist, *i.NovelID); err != nil {
+		return err
+	}
+
+	return s.saveChunks(ctx, i, len(result.Chapters), func(provider repository.RepositoryProvider, start, end int) error {
+		persist.provider = provider
+		if err := persist.createChapters(result.Chapters[start:end]); err != nil {
+			return err
+		}
+		return provider.EpubImport().UpdateProgress(ctx, i.ID, i.Attempts, end, s.leaseUntil())
+	})
+}
+
+// runMerge merges the parsed EPUB into the import's novel: the volumes in one
+// transaction, then the chapters in chunks, saving the diff with every chunk.
+// A resumed merge starts from the saved diff.
+func (s *EpubImportService) runMerge(ctx context.Context, i *epubimport.EpubImport, result *transformer.EpubProcessResult) error {
+	existing, err := s.novelSrvc.novelRepo.GetByID(ctx, *i.NovelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("novel not found")
+		}
+		return fmt.Errorf("unable to load novel %s: %w", *i.NovelID, err)
+	}
+	if err := checkEpubLanguage(result, existing); err != nil {
+		return err
+	}
+
+	merge := &epubMerge{
+		epubPersistence: &epubPersistence{
+			ctx:       ctx,
+			creatorID: i.CreatedBy,
+			result:    result,
+			mediaSrvc: s.novelSrvc.mediaSrvc,
+			novel:     existing,
+		},
+		updateContent: i.UpdateContent,
+		diff:          i.MergeResult,
+	}
+	if merge.diff == nil {
+		merge.diff = newEpubMergeResult(string(result.SourceType), i.UpdateContent)
+	}
+
+	err = s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		merge.provider = provider
+		if err := merge.matchVolumes(); err != nil {
+			return err
+		}
+		return provider.EpubImport().UpdateMergeProgress(ctx, i.ID, i.Attempts, i.ImportedChapters, merge.diff, s.leaseUntil())
+	})
+	if err != nil {
+		return err
+	}
+	merge.skipMerged(result.Chapters[:i.ImportedChapters])
+
+	err = s.saveChunks(ctx, i, len(result.Chapters), func(provider repository.RepositoryProvider, start, end int) error {
+		merge.provider = provider
+		if err := merge.mergeChapters(result.Chapters[start:end]); err != nil {
+			return err
+		}
+		return provider.EpubImport().UpdateMergeProgress(ctx, i.ID, i.Attempts, end, merge.diff, s.leaseUntil())
+	})
+	if err != nil {
+		return err
+	}
+
+	diff := merge.diff
+	logger.Info(fmt.Sprintf(
+		"Merged EPUB into novel %s: %d added, %d updated, %d changed, %d conflicts, %d unchanged, %d translations outdated",
+		existing.ID, len(diff.Added), len(diff.Updated), len(diff.Changed), len(diff.Conflicts), diff.Unchanged, len(diff.OutdatedTranslations),
+	))
+	return nil
+}
+
+// saveChunks saves the chapters an earlier attempt did not, calling save with
+// the bounds of each chunk in its own transaction
+func (s *EpubImportService) saveChunks(ctx context.Context, i *epubimport.EpubImport, total int, save func(provider repository.RepositoryProvider, start, end int) error) error {
+	chunkSize := max(s.opts.ChunkSize, 1)
+	for start := i.ImportedChapters; start < total; start += chunkSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := min(start+chunkSize, total)
+		err := s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+			return save(provider, start, end)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resumeNovel points persist at the novel and volumes an interrupted attempt
+// created. Volumes are matched by number, as createVolumes numbers them.
+func (s *EpubImportService) resumeNovel(ctx context.Context, persist *epubPersistence, novelID string) error {
+	volumes, err := s.novelSrvc.volumeSrvc.volumeRepo.GetAllByNovelID(ctx, novelID)
+	if err != nil {
+		return fmt.Errorf("unable to load volumes of novel %s: %w", novelID, err)
+	}
+
+	byNumber := make(map[int]*volume.Volume, len(volumes))
+	for j := range volumes {
+		byNumber[volumes[j].Number] = &volumes[j]
+	}
+
+	volumesData := persist.result.Volumes
+	if len(volumesData) == 0 {
+		volumesData = []transformer.VolumeData{{Number: 1}}
+	}
+
+	persist.novel = &novel.Novel{ID: novelID}
+	persist.volumes = make([]*volume.Volume, len(volumesData))
+	for j, v := range volumesData {
+		number := defaultVolumeNumber(v.Number, j+1)
+		if persist.volumes[j] = byNumber[number]; persist.volumes[j] == nil {
+			return fmt.Errorf("volume %d of novel %s is missing", number, novelID)
+		}
+	}
+
+	return nil
+}
+
+// fail marks an import as failed and deletes the partly imported novel, so
+// that retrying the upload does not leave duplicates behind. The novel is
+// only deleted once the import is marked, as a worker that lost the lease
+// must not delete the novel its successor is filling. A merge keeps what it
+// merged, since merging the file again skips it.
+func (s *EpubImportService) fail(ctx context.Context, i *epubimport.EpubImport, cause error) {
+	logger.Error(cause, fmt.Sprintf("EPUB import %s failed", i.ID))
+
+	if err := s.importRepo.MarkFailed(ctx, i.ID, i.Attempts, cause.Error()); err != nil {
+		if errors.Is(err, repository.ErrImportLeaseLost) {
+			s.warnLeaseLost(i)
+			return
+		}
+		logger.Error(err, "failed to mark epub import failed")
+		return
+	}
+
+	if i.NovelID != nil && i.Kind != epubimport.EpubImportKindMerge {
+		if err := s.novelSrvc.Delete(ctx, *i.NovelID); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to delete novel %s of failed epub import", *i.NovelID))
+		}
+	}
+	s.deleteFile(ctx, i.ID)
+}
+
+// warnLeaseLost reports an attempt that stopped because another worker
+// claimed the import after its lease expired
+func (s *EpubImportService) warnLeaseLost(i *epubimport.EpubImport) {
+	logger.Warn(fmt.Sprintf("EPUB import %s attempt %d lost its lease to another worker, stopping", i.ID, i.Attempts))
+}
+
+func (s *EpubImportService) deleteFile(ctx context.Context, id string) {
+	if err := s.importRepo.DeleteFile(ctx, id); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to delete file of epub import %s", id))
+	}
+}
+
+func (s *EpubImportService) leaseUntil() time.Time {
+	return time.Now().Add(s.opts.Lease)
+}
@@ -12,13 +12,17 @@ import (
 	"strings"
 )
 
+// ErrInvalidEpub is returned for an uploaded file that cannot be read as an
+// EPUB
+var ErrInvalidEpub = errors.New("invalid EPUB file")
+
 type EpubService struct{}
 
 func NewEpubService() *EpubService { return &EpubService{} }
 
 func (s *EpubService) UploadAndExtractRawEpub(ctx context.Context, fileBytes []byte) (*epub.RawEpub, error) {
 	if len(fileBytes) == 0 {
-		return nil, errors.New("epub file is empty")
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidEpub)
 	}
 
 	epubContent, err := s.parseEpubSafe(fileBytes)
@@ -38,7 +42,11 @@ func (s *EpubService) parseEpubSafe(fileBytes []byte) (content *epub.EpubContent
 		}
 	}()
 
-	return s.parseEpub(fileBytes)
+	content, err = s.parseEpub(fileBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEpub, err)
+	}
+	return content, nil
 }
 
 // parseEpub extracts and parses all EPUB content
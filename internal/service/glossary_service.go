@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"simple-go/internal/domain/glossary"
+	"simple-go/internal/repository"
+	"simple-go/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// glossaryCSVHeader is the column layout of glossary imports. The header row
+// itself is optional; category and note may be left out.
+var glossaryCSVHeader = []string{"source_term", "target_term", "category", "note"}
+
+// GlossaryService manages the per-novel terminology the translation worker
+// enforces
+type GlossaryService struct {
+	uow          repository.UnitOfWork
+	glossaryRepo repository.GlossaryRepository
+	novelRepo    repository.NovelRepository
+}
+
+func NewGlossaryService(
+	uow repository.UnitOfWork,
+	glossaryRepo repository.GlossaryRepository,
+	novelRepo repository.NovelRepository,
+) *GlossaryService {
+	return &GlossaryService{
+		uow:          uow,
+		glossaryRepo: glossaryRepo,
+		novelRepo:    novelRepo,
+	}
+}
+
+func (s *GlossaryService) Create(ctx context.Context, novelID, userID string, dto glossary.CreateGlossaryTermDTO) (*glossary.GlossaryTermResponseDTO, error) {
+	if err := s.ensureNovelExists(ctx, novelID); err != nil {
+		return nil, err
+	}
+
+	sourceTerm := strings.TrimSpace(dto.SourceTerm)
+	targetTerm := strings.TrimSpace(dto.TargetTerm)
+	if sourceTerm == "" || targetTerm == "" {
+		return nil, errors.New("source_term and target_term must not be blank")
+	}
+	if dto.SourceLang == dto.TargetLang {
+		return nil, errors.New("source_lang and target_lang must differ")
+	}
+
+	existing, err := s.glossaryRepo.GetBySourceTerm(ctx, novelID, dto.SourceLang, dto.TargetLang, sourceTerm)
+	if err == nil && existing != nil {
+		return nil, errors.New("term already exists in this glossary")
+	}
+
+	category := glossary.CategoryTerm
+	if dto.Category != nil {
+		category = *dto.Category
+	}
+
+	created, err := s.glossaryRepo.Create(ctx, &glossary.GlossaryTerm{
+		NovelID:    novelID,
+		SourceLang: dto.SourceLang,
+		TargetLang: dto.TargetLang,
+		SourceTerm: sourceTerm,
+		TargetTerm: targetTerm,
+		Category:   category,
+		Note:       dto.Note,
+		CreatedBy:  &userID,
+	})
+	if err != nil {
+		logger.Error(err, "failed to create glossary term")
+		return nil, errors.New("unable to create glossary term")
+	}
+
+	res := glossary.MapGlossaryTermToDTO(*created)
+	return &res, nil
+}
+
+func (s *GlossaryService) GetByNovelID(ctx context.Context, novelID, sourceLang, targetLang string, limit, offset int) ([]glossary.GlossaryTermResponseDTO, int64, error) {
+	if err := s.ensureNovelExists(ctx, novelID); err != nil {
+		return nil, 0, err
+	}
+
+	terms, err := s.glossaryRepo.GetByNovelID(ctx, novelID, sourceLang, targetLang, limit, offset)
+	if err != nil {
+		logger.Error(err, "failed to get glossary terms")
+		return nil, 0, errors.New("unable to retrieve glossary")
+	}
+
+	total, err := s.glossaryRepo.CountByNovelID(ctx, novelID, sourceLang, targetLang)
+	if err != nil {
+		logger.Error(err, "failed to count glossary terms")
+		return nil, 0, errors.New("unable to count glossary terms")
+	}
+
+	return glossary.MapGlossaryTermsToDTOs(terms), total, nil
+}
+
+func (s *GlossaryService) Update(ctx context.Context, novelID, termID string, dto glossary.UpdateGlossaryTermDTO) (*glossary.GlossaryTermResponseDTO, error) {
+	t, err := s.getTerm(ctx, novelID, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.SourceTerm != nil {
+		sourceTerm := strings.TrimSpace(*dto.SourceTerm)
+		if sourceTerm == "" {
+			return nil, errors.New("source_term must not be blank")
+		}
+		if sourceTerm != t.SourceTerm {
+			existing, err := s.glossaryRepo.GetBySourceTerm(ctx, novelID, t.SourceLang, t.TargetLang, sourceTerm)
+			if err == nil && existing != nil {
+				return nil, errors.New("term already exists in this glossary")
+			}
+		}
+		t.SourceTerm = sourceTerm
+	}
+	if dto.TargetTerm != nil {
+		targetTerm := strings.TrimSpace(*dto.TargetTerm)
+		if targetTerm == "" {
+			return nil, errors.New("target_term must not be blank")
+		}
+		t.TargetTerm = targetTerm
+	}
+	if dto.Category != nil {
+		t.Category = *dto.Category
+	}
+	if dto.Note != nil {
+		t.Note = dto.Note
+	}
+
+	updated, err := s.glossaryRepo.Update(ctx, t)
+	if err != nil {
+		logger.Error(err, "failed to update glossary term")
+		return nil, errors.New("unable to update glossary term")
+	}
+
+	res := glossary.MapGlossaryTermToDTO(*updated)
+	return &res, nil
+}
+
+func (s *GlossaryService) Delete(ctx context.Context, novelID, termID string) error {
+	if _, err := s.getTerm(ctx, novelID, termID); err != nil {
+		return err
+	}
+
+	if err := s.glossaryRepo.Delete(ctx, termID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("glossary term not found")
+		}
+		logger.Error(err, "failed to delete glossary term")
+		return errors.New("unable to delete glossary term")
+	}
+	return nil
+}
+
+// ImportCSV adds the terms of a CSV file to the glossary of one language pair.
+// Terms that already exist are updated. Invalid rows are reported and skipped;
+// the valid ones are saved in a single transaction.
+func (s *GlossaryService) ImportCSV(ctx context.Context, novelID, userID, sourceLang, targetLang string, r io.Reader) (*glossary.GlossaryImportResultDTO, error) {
+	if err := s.ensureNovelExists(ctx, novelID); err != nil {
+		return nil, err
+	}
+	if sourceLang == "" || targetLang == "" {
+		return nil, errors.New("source_lang and target_lang are required")
+	}
+	if sourceLang == targetLang {
+		return nil, errors.New("source_lang and target_lang must differ")
+	}
+
+	rows, result, err := parseGlossaryCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		repo := provider.Glossary()
+
+		existing, err := repo.GetForLanguagePair(ctx, novelID, sourceLang, targetLang)
+		if err != nil {
+			return err
+		}
+		bySource := make(map[string]*glossary.GlossaryTerm, len(existing))
+		for i := range existing {
+			bySource[existing[i].SourceTerm] = &existing[i]
+		}
+
+		for _, row := range rows {
+			if t, ok := bySource[row.SourceTerm]; ok {
+				t.TargetTerm = row.TargetTerm
+				t.Category = row.Category
+				if row.Note != nil {
+					t.Note = row.Note
+				}
+				if _, err := repo.Update(ctx, t); err != nil {
+					return err
+				}
+				result.Updated++
+				continue
+			}
+
+			t := &glossary.GlossaryTerm{
+				NovelID:    novelID,
+				SourceLang: sourceLang,
+				TargetLang: targetLang,
+				SourceTerm: row.SourceTerm,
+				TargetTerm: row.TargetTerm,
+				Category:   row.Category,
+				Note:       row.Note,
+				CreatedBy:  &userID,
+			}
+			if _, err := repo.Create(ctx, t); err != nil {
+				return err
+			}
+			bySource[t.SourceTerm] = t
+			result.Created++
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(err, "failed to import glossary")
+		return nil, errors.New("unable to import glossary")
+	}
+
+	return result, nil
+}
+
+type glossaryCSVRow struct {
+	SourceTerm string
+	TargetTerm string
+	Category   string
+	Note       *string
+}
+
+func parseGlossaryCSV(r io.Reader) ([]glossaryCSVRow, *glossary.GlossaryImportResultDTO, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	result := &glossary.GlossaryImportResultDTO{Errors: []glossary.GlossaryImportErrorDTO{}}
+	rows := make([]glossaryCSVRow, 0)
+	// Later rows win when the file repeats a term
+	index := make(map[string]int)
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				result.Errors = append(result.Errors, glossary.GlossaryImportErrorDTO{Line: parseErr.Line, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("unable to read CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), glossaryCSVHeader[0]) {
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		row, err := parseGlossaryCSVRecord(record)
+		if err != nil {
+			result.Errors = append(result.Errors, glossary.GlossaryImportErrorDTO{Line: line, Message: err.Error()})
+			continue
+		}
+
+		if i, ok := index[row.SourceTerm]; ok {
+			rows[i] = row
+			continue
+		}
+		index[row.SourceTerm] = len(rows)
+		rows = append(rows, row)
+	}
+
+	return rows, result, nil
+}
+
+func parseGlossaryCSVRecord(record []string) (glossaryCSVRow, error) {
+	if len(record) < 2 || len(record) > len(glossaryCSVHeader) {
+		return glossaryCSVRow{}, fmt.Errorf("expected 2 to %d columns (%s), got %d",
+			len(glossaryCSVHeader), strings.Join(glossaryCSVHeader, ","), len(record))
+	}
+
+	row := glossaryCSVRow{
+		SourceTerm: strings.TrimSpace(record[0]),
+		TargetTerm: strings.TrimSpace(record[1]),
+		Category:   glossary.CategoryTerm,
+	}
+	if row.SourceTerm == "" || row.TargetTerm == "" {
+		return glossaryCSVRow{}, errors.New("source_term and target_term must not be blank")
+	}
+	if len(row.SourceTerm) > 255 || len(row.TargetTerm) > 255 {
+		return glossaryCSVRow{}, errors.New("terms must be at most 255 characters")
+	}
+
+	if len(record) > 2 {
+		if category := strings.ToLower(strings.TrimSpace(record[2])); category != "" {
+			if !glossary.IsValidCategory(category) {
+				return glossaryCSVRow{}, fmt.Errorf("unknown category %q", record[2])
+			}
+			row.Category = category
+		}
+	}
+	if len(record) > 3 {
+		if note := strings.TrimSpace(record[3]); note != "" {
+			row.Note = &note
+		}
+	}
+
+	return row, nil
+}
+
+func (s *GlossaryService) ensureNovelExists(ctx context.Context, novelID string) error {
+	if _, err := s.novelRepo.GetByID(ctx, novelID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("novel not found")
+		}
+		logger.Error(err, "failed to verify novel exists")
+		return errors.New("unable to retrieve novel")
+	}
+	return nil
+}
+
+// getTerm loads a term and checks that it belongs to the novel in the URL
+func (s *GlossaryService) getTerm(ctx context.Context, novelID, termID string) (*glossary.GlossaryTerm, error) {
+	t, err := s.glossaryRepo.GetByID(ctx, termID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("glossary term not found")
+		}
+		logger.Error(err, "failed to get glossary term")
+		return nil, errors.New("unable to retrieve glossary term")
+	}
+	if t.NovelID != novelID {
+		return nil, errors.New("glossary term not found")
+	}
+	return t, nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGlossaryCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		// rows are written source=target/category[/note]
+		rows []string
+		// errorLines are the lines reported as errors, in order
+		errorLines []int
+	}{
+		{
+			name: "header is skipped",
+			csv:  "source_term,target_term,category,note\nhero,Held\n",
+			rows: []string{"hero=Held/term"},
+		},
+		{
+			name: "header is matched case-insensitively",
+			csv:  " Source_Term ,target_term\nhero,Held\n",
+			rows: []string{"hero=Held/term"},
+		},
+		{
+			name: "first row without header is a term",
+			csv:  "hero,Held,Character,the main character\n",
+			rows: []string{"hero=Held/character/the main character"},
+		},
+		{
+			name: "header only counts on the first line",
+			csv:  "hero,Held\nsource_term,target_term\n",
+			rows: []string{"hero=Held/term", "source_term=target_term/term"},
+		},
+		{
+			name: "last duplicate wins in the first position",
+			csv:  "hero,Held\nvillain,Schurke\nhero,Heldin,character\n",
+			rows: []string{"hero=Heldin/character", "villain=Schurke/term"},
+		},
+		{
+			name:       "too few and too many columns",
+			csv:        "hero,Held\nvillain\nking,König,character,note,extra\n",
+			rows:       []string{"hero=Held/term"},
+			errorLines: []int{2, 3},
+		},
+		{
+			name:       "blank terms",
+			csv:        "hero, \n ,Schurke\nking,König\n",
+			rows:       []string{"king=König/term"},
+			errorLines: []int{1, 2},
+		},
+		{
+			name:       "unknown category",
+			csv:        "hero,Held,weapon\n",
+			rows:       []string{},
+			errorLines: []int{1},
+		},
+		{
+			name:       "line numbers count blank lines and the header",
+			csv:        "source_term,target_term\n\nhero,Held\n\nvillain\n",
+			rows:       []string{"hero=Held/term"},
+			errorLines: []int{5},
+		},
+		{
+			name:       "malformed quoting is reported and skipped",
+			csv:        "hero,Held\n\"villain,Schurke\n",
+			rows:       []string{"hero=Held/term"},
+			errorLines: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, result, err := parseGlossaryCSV(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatalf("parseGlossaryCSV: %v", err)
+			}
+
+			got := make([]string, 0, len(rows))
+			for _, r := range rows {
+				s := r.SourceTerm + "=" + r.TargetTerm + "/" + r.Category
+				if r.Note != nil {
+					s += "/" + *r.Note
+				}
+				got = append(got, s)
+			}
+			if !reflect.DeepEqual(got, tt.rows) {
+				t.Errorf("rows = %q, want %q", got, tt.rows)
+			}
+
+			lines := make([]int, 0, len(result.Errors))
+			for _, e := range result.Errors {
+				lines = append(lines, e.Line)
+			}
+			if len(lines) != len(tt.errorLines) || (len(lines) > 0 && !reflect.DeepEqual(lines, tt.errorLines)) {
+				t.Errorf("error lines = %v (%+v), want %v", lines, result.Errors, tt.errorLines)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	domchapter "simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+	"simple-go/pkg/epub/transformer"
+	"simple-go/pkg/logger"
+)
+
+// checkEpubLanguage rejects an EPUB in another language than the novel it is
+// merged into. An EPUB without a language is accepted.
+func checkEpubLanguage(result *transformer.EpubProcessResult, existing *novel.Novel) error {
+	declared := baseLanguage(result.NovelData.OriginalLanguage)
+	if declared != "" && declared != baseLanguage(existing.OriginalLanguage) {
+		return fmt.Errorf("EPUB language '%s' does not match the novel's original language '%s'", result.NovelData.OriginalLanguage, existing.OriginalLanguage)
+	}
+	// Store new text under the novel's own code, "ja" rather than "ja-JP"
+	result.NovelData.OriginalLanguage = existing.OriginalLanguage
+	return nil
+}
+
+// epubMerge merges a newer copy of a novel's EPUB into the novel, as when a
+// serial gets new chapters, reusing epubPersistence to create what the novel
+// lacks. Volumes are matched by number and chapters by number and title.
+// Chapters the novel lacks are added; chapters whose text changed are updated
+// only when updateContent is set. Merges run as EPUB imports of kind merge,
+// which call matchVolumes once and then mergeChapters chunk by chunk.
+type epubMerge struct {
+	*epubPersistence
+	updateContent bool
+
+	// chapters indexes the novel's chapters by volume ID and number
+	chapters map[string]map[int]*mergeTarget
+	diff     *novel.EpubMergeResultDTO
+}
+
+// mergeTarget is a chapter of the novel that EPUB chapters are matched against
+type mergeTarget struct {
+	id    string
+	title string
+	// translation is the text in the novel's original language, if any
+	translation *domchapter.ChapterTranslation
+	// merged is set once an EPUB chapter was matched to it
+	merged bool
+}
+
+func newEpubMergeResult(sourceType string, updateContent bool) *novel.EpubMergeResultDTO {
+	return &novel.EpubMergeResultDTO{
+		SourceType:           sourceType,
+		UpdateContent:        updateContent,
+		AddedVolumes:         []int{},
+		Added:                []novel.EpubMergeChapterDTO{},
+		Updated:              []novel.EpubMergeChapterDTO{},
+		Changed:              []novel.EpubMergeChapterDTO{},
+		Conflicts:            []novel.EpubMergeChapterDTO{},
+		OutdatedTranslations: []novel.EpubMergeOutdatedTranslationDTO{},
+		OutdatedAlignments:   []novel.EpubMergeOutdatedAlignmentDTO{},
+	}
+}
+
+func (m *epubMerge) mergeChapters(chapters []transformer.ChapterData) error {
+	for _, chapterData := range chapters {
+		if err := m.mergeChapter(chapterData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipMerged replays the matching of chapters an earlier attempt merged, so
+// that a resumed merge detects the same conflicts. Chapters it added are
+// among the novel's chapters by now.
+func (m *epubMerge) skipMerged(chapters []transformer.ChapterData) {
+	for _, chapterData := range chapters {
+		vol := m.resolveVolumeForChapter(chapterData.VolumeIndex)
+		target, ok := m.chapters[vol.ID][chapterData.OrderNum]
+		if ok && !target.merged && sameChapterTitle(target.title, chapterData.Title) {
+			target.merged = true
+		}
+	}
+}
+
+// matchVolumes pairs the EPUB's volumes with the novel's by number, creating
+// those the novel lacks, and indexes the chapters of each
+func (m *epubMerge) matchVolumes() error {
+	existing, err := m.provider.Volume().GetAllWithChaptersByNovelIDAndLang(m.ctx, m.novel.ID, m.result.NovelData.OriginalLanguage)
+	if err != nil {
+		logger.Error(err, "failed to get volumes of novel")
+		return errors.New("unable to retrieve volumes")
+	}
+
+	byNumber := make(map[int]*volume.Volume, len(existing))
+	m.chapters = make(map[string]map[int]*mergeTarget, len(existing))
+	for i := range existing {
+		v := &existing[i]
+		byNumber[v.Number] = v
+
+		targets := make(map[int]*mergeTarget, len(v.Chapters))
+		for _, c := range v.Chapters {
+			target := &mergeTarget{id: c.ID}
+			if len(c.Translations) > 0 {
+				target.translation = &c.Translations[0]
+				target.title = target.translation.Title
+			}
+			targets[c.Number] = target
+		}
+		m.chapters[v.ID] = targets
+	}
+
+	// Same fallback as createVolumes, so a novel imported without volumes
+	// matches its virtual volume again
+	volumesData := m.result.Volumes
+	if len(volumesData) == 0 {
+		volumesData = []transformer.VolumeData{{
+			Number:    1,
+			Title:     "Volume 1",
+			IsVirtual: true,
+		}}
+	}
+
+	m.volumes = make([]*volume.Volume, len(volumesData))
+	for i, volData := range volumesData {
+		number := defaultVolumeNumber(volData.Number, i+1)
+		if v, ok := byNumber[number]; ok {
+			m.volumes[i] = v
+			continue
+		}
+
+		createdVolume, err := m.createVolume(number, volData)
+		if err != nil {
+			return err
+		}
+		m.volumes[i] = createdVolume
+		m.chapters[createdVolume.ID] = make(map[int]*mergeTarget)
+		m.diff.AddedVolumes = append(m.diff.AddedVolumes, number)
+	}
+
+	return nil
+}
+
+func (m *epubMerge) mergeChapter(chapterData transformer.ChapterData) error {
+	vol := m.resolveVolumeForChapter(chapterData.VolumeIndex)
+	entry := novel.EpubMergeChapterDTO{
+		Volume: vol.Number,
+		Number: chapterData.OrderNum,
+		Title:  chapterData.Title,
+	}
+
+	target, ok := m.chapters[vol.ID][chapterData.OrderNum]
+	if !ok {
+		createdChapter, err := m.createChapter(vol, chapterData)
+		if err != nil {
+			return err
+		}
+		m.chapters[vol.ID][chapterData.OrderNum] = &mergeTarget{id: createdChapter.ID, title: chapterData.Title, merged: true}
+		entry.ChapterID = createdChapter.ID
+		m.diff.Added = append(m.diff.Added, entry)
+		return nil
+	}
+
+	entry.ChapterID = target.id
+	// Another chapter under this number, either in the EPUB itself or in the
+	// novel, is left alone rather than overwritten
+	if target.merged || !sameChapterTitle(target.title, chapterData.Title) {
+		entry.ExistingTitle = target.title
+		m.diff.Conflicts = append(m.diff.Conflicts, entry)
+		return nil
+	}
+	target.merged = true
+
+	if target.translation != nil && contentHash(strings.TrimSpace(target.translation.Content)) == contentHash(strings.TrimSpace(chapterData.Content)) {
+		m.diff.Unchanged++
+		return nil
+	}
+	if !m.updateContent {
+		m.diff.Changed = append(m.diff.Changed, entry)
+		return nil
+	}
+
+	if err := m.updateChapterContent(target, chapterData, entry); err != nil {
+		return err
+	}
+	m.diff.Updated = append(m.diff.Updated, entry)
+	return nil
+}
+
+// updateChapterContent replaces the chapter's original-language text, adding
+// it if the chapter had none. Replaced text outdates the translations and
+// alignments made from it.
+func (m *epubMerge) updateChapterContent(target *mergeTarget, chapterData transformer.ChapterData, entry novel.EpubMergeChapterDTO) error {
+	if target.translation == nil {
+		chapterTranslation := &domchapter.ChapterTranslation{
+			ChapterID: target.id,
+			Lang:      m.result.NovelData.OriginalLanguage,
+			Title:     chapterData.Title,
+			Content:   chapterData.Content,
+		}
+		if _, err := m.provider.Chapter().CreateTranslation(m.ctx, chapterTranslation); err != nil {
+			logger.Error(err, fmt.Sprintf("Failed to create chapter %d translation", chapterData.OrderNum))
+			return errors.New("failed to create chapter translation")
+		}
+		return nil
+	}
+
+	target.translation.Title = chapterData.Title
+	target.translation.Content = chapterData.Content
+	if _, err := m.provider.Chapter().UpdateTranslation(m.ctx, target.translation); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to update chapter %d translation", chapterData.OrderNum))
+		return errors.New("failed to update chapter translation")
+	}
+
+	return m.markOutdated(target.id, entry)
+}
+
+// markOutdated marks the chapter's other translations and the alignments
+// with its original-language text outdated and lists them in the diff
+func (m *epubMerge) markOutdated(chapterID string, entry novel.EpubMergeChapterDTO) error {
+	lang := m.result.NovelData.OriginalLanguage
+
+	translations, err := m.provider.Chapter().MarkTranslationsOutdated(m.ctx, chapterID, lang)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to mark translations of chapter %d outdated", entry.Number))
+		return errors.New("failed to mark chapter translations outdated")
+	}
+	for _, t := range translations {
+		m.diff.OutdatedTranslations = append(m.diff.OutdatedTranslations, novel.EpubMergeOutdatedTranslationDTO{
+			ChapterID:     chapterID,
+			Volume:        entry.Volume,
+			Number:        entry.Number,
+			TranslationID: t.ID,
+			Lang:          t.Lang,
+			Status:        t.Status,
+		})
+	}
+
+	alignments, err := m.provider.Chapter().MarkAlignmentsOutdated(m.ctx, chapterID, lang)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to mark alignments of chapter %d outdated", entry.Number))
+		return errors.New("failed to mark chapter alignments outdated")
+	}
+	for _, a := range alignments {
+		m.diff.OutdatedAlignments = append(m.diff.OutdatedAlignments, novel.EpubMergeOutdatedAlignmentDTO{
+			ChapterID:  chapterID,
+			Volume:     entry.Volume,
+			Number:     entry.Number,
+			SourceLang: a.SourceLang,
+			TargetLang: a.TargetLang,
+		})
+	}
+	return nil
+}
+
+// sameChapterTitle compares titles ignoring case and spacing. A missing
+// title matches anything, so untitled chapters are matched by number alone.
+func sameChapterTitle(a, b string) bool {
+	a = strings.ToLower(strings.Join(strings.Fields(a), " "))
+	b = strings.ToLower(strings.Join(strings.Fields(b), " "))
+	return a == "" || b == "" || a == b
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+	"simple-go/internal/repository"
+	"simple-go/pkg/epub/transformer"
+)
+
+type mergeProvider struct {
+	repository.RepositoryProvider
+	chapters *mergeChapterRepo
+}
+
+func (p *mergeProvider) Chapter() repository.ChapterRepository { return p.chapters }
+
+// mergeChapterRepo records updated texts and returns fixed translations and
+// alignments when they are marked outdated
+type mergeChapterRepo struct {
+	repository.ChapterRepository
+	updated    []chapter.ChapterTranslation
+	outdated   []string
+	others     []chapter.ChapterTranslation
+	alignments []chapter.ChapterAlignment
+}
+
+func (r *mergeChapterRepo) UpdateTranslation(ctx context.Context, ct *chapter.ChapterTranslation) (*chapter.ChapterTranslation, error) {
+	r.updated = append(r.updated, *ct)
+	return ct, nil
+}
+
+func (r *mergeChapterRepo) MarkTranslationsOutdated(ctx context.Context, chapterID, sourceLang string) ([]chapter.ChapterTranslation, error) {
+	r.outdated = append(r.outdated, chapterID+"/"+sourceLang)
+	return r.others, nil
+}
+
+func (r *mergeChapterRepo) MarkAlignmentsOutdated(ctx context.Context, chapterID, lang string) ([]chapter.ChapterAlignment, error) {
+	return r.alignments, nil
+}
+
+// newTestMerge sets up a merge into a novel with one volume holding chapter 1
+func newTestMerge(repo *mergeChapterRepo, chapters []transformer.ChapterData) *epubMerge {
+	vol := &volume.Volume{ID: "volume-1", Number: 1}
+	return &epubMerge{
+		epubPersistence: &epubPersistence{
+			ctx: context.Background(),
+			result: &transformer.EpubProcessResult{
+				NovelData: &transformer.NovelData{OriginalLanguage: "ja"},
+				Chapters:  chapters,
+			},
+			provider: &mergeProvider{chapters: repo},
+			novel:    &novel.Novel{ID: "novel-1", OriginalLanguage: "ja"},
+			volumes:  []*volume.Volume{vol},
+		},
+		updateContent: true,
+		chapters: map[string]map[int]*mergeTarget{vol.ID: {
+			1: {
+				id:          "chapter-1",
+				title:       "Chapter 1",
+				translation: &chapter.ChapterTranslation{ID: "ja-1", ChapterID: "chapter-1", Lang: "ja", Title: "Chapter 1", Content: "<p>Old text.</p>"},
+			},
+		}},
+		diff: newEpubMergeResult("generic", true),
+	}
+}
+
+func TestMergeChapterMarksTranslationsOutdated(t *testing.T) {
+	repo := &mergeChapterRepo{
+		others:     []chapter.ChapterTranslation{{ID: "en-1", ChapterID: "chapter-1", Lang: "en", Status: chapter.TranslationStatusApproved}},
+		alignments: []chapter.ChapterAlignment{{ChapterID: "chapter-1", SourceLang: "ja", TargetLang: "en"}},
+	}
+	chapters := []transformer.ChapterData{
+		{OrderNum: 1, Title: "Chapter 1", Content: "<p>New text.</p>"},
+		{OrderNum: 1, Title: "Chapter 1", Content: "<p>Listed twice.</p>"},
+	}
+	merge := newTestMerge(repo, chapters)
+
+	if err := merge.mergeChapters(chapters); err != nil {
+		t.Fatalf("mergeChapters: %v", err)
+	}
+
+	if len(repo.updated) != 1 || repo.updated[0].Content != "<p>New text.</p>" {
+		t.Fatalf("updated = %+v, want chapter 1 replaced once", repo.updated)
+	}
+	if len(merge.diff.Updated) != 1 || len(merge.diff.Conflicts) != 1 {
+		t.Errorf("updated = %d, conflicts = %d, want 1 and 1", len(merge.diff.Updated), len(merge.diff.Conflicts))
+	}
+	if len(repo.outdated) != 1 || repo.outdated[0] != "chapter-1/ja" {
+		t.Errorf("outdated = %v, want the translations made from chapter-1/ja", repo.outdated)
+	}
+
+	want := novel.EpubMergeOutdatedTranslationDTO{
+		ChapterID: "chapter-1", Volume: 1, Number: 1, TranslationID: "en-1", Lang: "en", Status: chapter.TranslationStatusApproved,
+	}
+	if len(merge.diff.OutdatedTranslations) != 1 || merge.diff.OutdatedTranslations[0] != want {
+		t.Errorf("outdated translations = %+v, want %+v", merge.diff.OutdatedTranslations, want)
+	}
+	if len(merge.diff.OutdatedAlignments) != 1 || merge.diff.OutdatedAlignments[0].TargetLang != "en" {
+		t.Errorf("outdated alignments = %+v, want the ja-en alignment", merge.diff.OutdatedAlignments)
+	}
+}
+
+func TestResumedMergeSkipsMergedChapters(t *testing.T) {
+	repo := &mergeChapterRepo{}
+	chapters := []transformer.ChapterData{
+		{OrderNum: 1, Title: "Chapter 1", Content: "<p>Old text.</p>"},
+		{OrderNum: 1, Title: "Chapter 1", Content: "<p>Listed twice.</p>"},
+	}
+	merge := newTestMerge(repo, chapters)
+
+	// The first chapter was merged by an earlier attempt
+	merge.skipMerged(chapters[:1])
+	if err := merge.mergeChapters(chapters[1:]); err != nil {
+		t.Fatalf("mergeChapters: %v", err)
+	}
+
+	if len(repo.updated) != 0 {
+		t.Errorf("updated = %+v, want the duplicate left alone", repo.updated)
+	}
+	if len(merge.diff.Conflicts) != 1 || merge.diff.Unchanged != 0 {
+		t.Errorf("conflicts = %d, unchanged = %d, want the duplicate as the only conflict", len(merge.diff.Conflicts), merge.diff.Unchanged)
+	}
+}
@@ -22,17 +22,25 @@ type epubPersistence struct {
 	provider  repository.RepositoryProvider
 	mediaSrvc *MediaService
 
-	novel   *novel.Novel
-	volumes []*volume.Volume
+	// coverMediaID is set by uploadCoverImage, which runs before the
+	// transaction so that a slow image host does not hold it open
+	coverMediaID *string
+	novel        *novel.Novel
+	volumes      []*volume.Volume
 }
 
 func (p *epubPersistence) run() error {
-	coverMediaID, err := p.uploadCoverImage()
-	if err != nil {
+	if err := p.createNovelStructure(); err != nil {
 		return err
 	}
 
-	if err := p.createNovel(coverMediaID); err != nil {
+	return p.createChapters(p.result.Chapters)
+}
+
+// createNovelStructure creates everything but the chapters: the novel with
+// its cover, translation and tags, and the volumes
+func (p *epubPersistence) createNovelStructure() error {
+	if err := p.createNovel(p.coverMediaID); err != nil {
 		return err
 	}
 
@@ -44,16 +52,14 @@ func (p *epubPersistence) run() error {
 		return err
 	}
 
-	if err := p.createVolumes(); err != nil {
-		return err
-	}
-
-	return p.createChapters()
+	return p.createVolumes()
 }
 
-func (p *epubPersistence) uploadCoverImage() (*string, error) {
+// uploadCoverImage uploads the cover and saves its media record outside any
+// transaction. A failed upload only leaves the novel without a cover.
+func (p *epubPersistence) uploadCoverImage() {
 	if p.mediaSrvc == nil || len(p.result.NovelData.CoverImage) == 0 {
-		return nil, nil
+		return
 	}
 
 	uploadParams := dommedia.UploadAndSaveDTO{
@@ -62,13 +68,13 @@ func (p *epubPersistence) uploadCoverImage() (*string, error) {
 		UploaderID: p.creatorID,
 	}
 
-	savedMedia, _, err := p.mediaSrvc.UploadAndSaveWithRepo(p.ctx, p.provider.Media(), uploadParams)
+	savedMedia, _, err := p.mediaSrvc.UploadAndSaveMedia(p.ctx, uploadParams)
 	if err != nil {
 		logger.Error(err, "Failed to upload cover image, continuing without cover")
-		return nil, nil
+		return
 	}
 
-	return &savedMedia.ID, nil
+	p.coverMediaID = &savedMedia.ID
 }
 
 func (p *epubPersistence) createNovel(coverMediaID *string) error {
@@ -147,77 +153,93 @@ func (p *epubPersistence) createVolumes() error {
 
 	p.volumes = make([]*volume.Volume, len(volumesData))
 	for i, volData := range volumesData {
-		newVolume := &volume.Volume{
-			Number:           defaultVolumeNumber(volData.Number, i+1),
-			OriginalLanguage: p.result.NovelData.OriginalLanguage,
-			NovelID:          p.novel.ID,
-			IsVirtual:        volData.IsVirtual,
-		}
-
-		createdVolume, err := p.provider.Volume().Create(p.ctx, newVolume)
+		createdVolume, err := p.createVolume(defaultVolumeNumber(volData.Number, i+1), volData)
 		if err != nil {
-			logger.Error(err, fmt.Sprintf("Failed to create volume %d", newVolume.Number))
-			return fmt.Errorf("failed to create volume %d", newVolume.Number)
+			return err
 		}
+		p.volumes[i] = createdVolume
+	}
 
-		// Create volume translation
-		volumeTranslation := &volume.VolumeTranslation{
-			VolumeID: createdVolume.ID,
-			Lang:     p.result.NovelData.OriginalLanguage,
-			Title:    volData.Title,
-		}
+	return nil
+}
 
-		if _, err := p.provider.Volume().CreateTranslation(p.ctx, volumeTranslation); err != nil {
-			logger.Error(err, fmt.Sprintf("Failed to create volume %d translation", createdVolume.Number))
-			return fmt.Errorf("failed to create volume %d translation", createdVolume.Number)
-		}
+func (p *epubPersistence) createVolume(number int, volData transformer.VolumeData) (*volume.Volume, error) {
+	newVolume := &volume.Volume{
+		Number:           number,
+		OriginalLanguage: p.result.NovelData.OriginalLanguage,
+		NovelID:          p.novel.ID,
+		IsVirtual:        volData.IsVirtual,
+	}
 
-		p.volumes[i] = createdVolume
+	createdVolume, err := p.provider.Volume().Create(p.ctx, newVolume)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to create volume %d", newVolume.Number))
+		return nil, fmt.Errorf("failed to create volume %d", newVolume.Number)
 	}
 
-	return nil
+	// Create volume translation
+	volumeTranslation := &volume.VolumeTranslation{
+		VolumeID: createdVolume.ID,
+		Lang:     p.result.NovelData.OriginalLanguage,
+		Title:    volData.Title,
+	}
+
+	if _, err := p.provider.Volume().CreateTranslation(p.ctx, volumeTranslation); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to create volume %d translation", createdVolume.Number))
+		return nil, fmt.Errorf("failed to create volume %d translation", createdVolume.Number)
+	}
+
+	return createdVolume, nil
 }
 
-func (p *epubPersistence) createChapters() error {
+func (p *epubPersistence) createChapters(chapters []transformer.ChapterData) error {
 	if len(p.volumes) == 0 {
 		return errors.New("volumes must be created before chapters")
 	}
 
 	createdCount := 0
-	for _, chapterData := range p.result.Chapters {
+	for _, chapterData := range chapters {
 		volume := p.resolveVolumeForChapter(chapterData.VolumeIndex)
 		if volume == nil {
 			logger.Error(nil, fmt.Sprintf("Skipping chapter due to invalid volume index %d", chapterData.VolumeIndex))
 			continue
 		}
 
-		newChapter := &domchapter.Chapter{
-			Number:   chapterData.OrderNum,
-			VolumeID: volume.ID,
+		if _, err := p.createChapter(volume, chapterData); err != nil {
+			return err
 		}
 
-		createdChapter, err := p.provider.Chapter().Create(p.ctx, newChapter)
-		if err != nil {
-			logger.Error(err, fmt.Sprintf("Failed to create chapter %d", chapterData.OrderNum))
-			return fmt.Errorf("failed to create chapter %d", chapterData.OrderNum)
-		}
+		createdCount++
+	}
 
-		chapterTranslation := &domchapter.ChapterTranslation{
-			ChapterID: createdChapter.ID,
-			Lang:      p.result.NovelData.OriginalLanguage,
-			Title:     chapterData.Title,
-			Content:   chapterData.Content,
-		}
+	return nil
+}
 
-		if _, err := p.provider.Chapter().CreateTranslation(p.ctx, chapterTranslation); err != nil {
-			logger.Error(err, fmt.Sprintf("Failed to create chapter %d translation", chapterData.OrderNum))
-			return errors.New("failed to create chapter translation")
-		}
+func (p *epubPersistence) createChapter(vol *volume.Volume, chapterData transformer.ChapterData) (*domchapter.Chapter, error) {
+	newChapter := &domchapter.Chapter{
+		Number:   chapterData.OrderNum,
+		VolumeID: vol.ID,
+	}
 
-		createdCount++
+	createdChapter, err := p.provider.Chapter().Create(p.ctx, newChapter)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to create chapter %d", chapterData.OrderNum))
+		return nil, fmt.Errorf("failed to create chapter %d", chapterData.OrderNum)
 	}
 
-	return nil
+	chapterTranslation := &domchapter.ChapterTranslation{
+		ChapterID: createdChapter.ID,
+		Lang:      p.result.NovelData.OriginalLanguage,
+		Title:     chapterData.Title,
+		Content:   chapterData.Content,
+	}
+
+	if _, err := p.provider.Chapter().CreateTranslation(p.ctx, chapterTranslation); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to create chapter %d translation", chapterData.OrderNum))
+		return nil, errors.New("failed to create chapter translation")
+	}
+
+	return createdChapter, nil
 }
 
 func (p *epubPersistence) resolveVolumeForChapter(volumeIndex int) *volume.Volume {
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"simple-go/internal/domain/novel"
+	"simple-go/pkg/epub/transformer"
+	"simple-go/pkg/miscellaneous"
+	"simple-go/pkg/script"
+	"simple-go/pkg/textstat"
+)
+
+// languageSampleSize bounds how much chapter text the language is guessed from
+const languageSampleSize = 20000
+
+// PreviewEpubUpload parses an EPUB the way ProcessAndSaveEpubUpload would and
+// reports the novel, volumes and chapters it would create, along with
+// anything that looks wrong. Nothing is saved.
+func (s *NovelService) PreviewEpubUpload(ctx context.Context, fileBytes []byte) (*novel.EpubPreviewDTO, error) {
+	result, err := s.ProcessEpubUpload(ctx, fileBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := buildEpubPreview(result)
+	return &preview, nil
+}
+
+func buildEpubPreview(result *transformer.EpubProcessResult) novel.EpubPreviewDTO {
+	data := result.NovelData
+	preview := novel.EpubPreviewDTO{
+		SourceType:       string(result.SourceType),
+		Title:            data.Title,
+		OriginalAuthor:   data.OriginalAuthor,
+		Description:      data.Description,
+		Publisher:        data.Publisher,
+		DeclaredLanguage: data.OriginalLanguage,
+		Tags:             data.Tags,
+		HasCoverImage:    len(data.CoverImage) > 0,
+		TotalChapters:    len(result.Chapters),
+		Warnings:         []string{},
+	}
+	if preview.Tags == nil {
+		preview.Tags = []string{}
+	}
+
+	// Mirror epubPersistence: a virtual volume when the source has none, and
+	// chapters with an unknown volume go into the first one
+	volumesData := result.Volumes
+	if len(volumesData) == 0 {
+		volumesData = []transformer.VolumeData{{Number: 1, Title: "Volume 1", IsVirtual: true}}
+	}
+	preview.Volumes = make([]novel.EpubPreviewVolumeDTO, len(volumesData))
+	for i, v := range volumesData {
+		preview.Volumes[i] = novel.EpubPreviewVolumeDTO{
+			Number:    defaultVolumeNumber(v.Number, i+1),
+			Title:     v.Title,
+			IsVirtual: v.IsVirtual,
+			Chapters:  []novel.EpubPreviewChapterDTO{},
+		}
+	}
+	preview.TotalVolumes = len(preview.Volumes)
+
+	var sample strings.Builder
+	numbers := make([]map[int]bool, len(preview.Volumes))
+	for _, ch := range result.Chapters {
+		index := ch.VolumeIndex
+		if index < 0 || index >= len(preview.Volumes) {
+			index = 0
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("Chapter %d (%s) refers to a missing volume and would be imported into volume %d", ch.OrderNum, ch.Title, preview.Volumes[0].Number))
+		}
+		vol := &preview.Volumes[index]
+
+		words := textstat.Words(ch.PlainText)
+		if words == 0 {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("Chapter %d (%s) in volume %d has no text", ch.OrderNum, ch.Title, vol.Number))
+		}
+
+		if numbers[index] == nil {
+			numbers[index] = make(map[int]bool)
+		}
+		if numbers[index][ch.OrderNum] {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("Volume %d has more than one chapter numbered %d", vol.Number, ch.OrderNum))
+		}
+		numbers[index][ch.OrderNum] = true
+
+		vol.Chapters = append(vol.Chapters, novel.EpubPreviewChapterDTO{
+			Number:    ch.OrderNum,
+			Title:     ch.Title,
+			WordCount: words,
+		})
+		vol.WordCount += words
+		preview.TotalWords += words
+
+		if sample.Len() < languageSampleSize {
+			sample.WriteString(ch.PlainText)
+			sample.WriteString(" ")
+		}
+	}
+
+	preview.DetectedLanguage = script.Guess(sample.String(), data.OriginalLanguage)
+	preview.Warnings = append(epubPreviewWarnings(preview), preview.Warnings...)
+
+	return preview
+}
+
+// epubPreviewWarnings checks the novel as a whole; chapter warnings are
+// collected while the chapters are listed
+func epubPreviewWarnings(preview novel.EpubPreviewDTO) []string {
+	var warnings []string
+
+	if preview.SourceType == string(transformer.EpubSourceGeneric) {
+		warnings = append(warnings, "No source-specific format was recognised; volumes and chapters were derived from the table of contents")
+	}
+	if preview.Title == "" {
+		warnings = append(warnings, "No title found in the EPUB metadata")
+	}
+
+	declared := baseLanguage(preview.DeclaredLanguage)
+	switch {
+	case declared == "":
+		warnings = append(warnings, "No language is declared in the EPUB metadata")
+	case len(miscellaneous.GetLanguageByCode(declared)) == 0:
+		warnings = append(warnings, fmt.Sprintf("Declared language '%s' is not a known ISO 639-1 code", preview.DeclaredLanguage))
+	}
+	if detected := baseLanguage(preview.DetectedLanguage); declared != "" && detected != "" && detected != declared {
+		warnings = append(warnings, fmt.Sprintf("Declared language '%s' does not match the text, which looks like '%s'", preview.DeclaredLanguage, preview.DetectedLanguage))
+	}
+
+	if !preview.HasCoverImage {
+		warnings = append(warnings, "No cover image found")
+	}
+	if preview.TotalChapters == 0 {
+		warnings = append(warnings, "No chapters found")
+	}
+
+	return warnings
+}
+
+// baseLanguage strips region and script subtags ("zh-TW" is "zh")
+func baseLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
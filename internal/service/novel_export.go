@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+	"simple-go/pkg/epub"
+	"simple-go/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// maxCoverSize bounds the cover image downloaded for an export
+const maxCoverSize = 10 << 20
+
+// ErrNovelNotFound is returned for an export of a novel that does not exist
+var ErrNovelNotFound = errors.New("novel not found")
+
+// ErrExportUnavailable is returned when a novel has nothing to export in the
+// requested language
+var ErrExportUnavailable = errors.New("novel is not available")
+
+// NovelExport is a novel ready to be written as an EPUB
+type NovelExport struct {
+	FileName string
+	Lang     string
+	// ETag identifies the exported content; it changes whenever anything
+	// that goes into the book does
+	ETag string
+	// FallbackChapters counts the chapters exported in the original language
+	// because they have no approved translation in Lang
+	FallbackChapters int
+
+	novelID  string
+	book     *epub.Book
+	coverURL *string
+}
+
+// ExportEpub prepares a novel in lang for export, defaulting to the original
+// language. Only approved chapter translations are exported; a chapter
+// without one in lang falls back to the original language, and is left out if
+// it has none there either. The novel must have a title or at least one
+// chapter in lang. Nothing is written or downloaded until WriteEpub, so a
+// client that holds the current ETag costs no more than loading the novel.
+func (s *NovelService) ExportEpub(ctx context.Context, id, lang string) (*NovelExport, error) {
+	n, err := s.novelRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNovelNotFound
+		}
+		logger.Error(err, "failed to get novel for export")
+		return nil, errors.New("unable to retrieve novel")
+	}
+
+	if lang == "" {
+		lang = n.OriginalLanguage
+	}
+
+	title := novel.SelectTranslation(n.Translations, lang, n.OriginalLanguage)
+	if title == nil {
+		return nil, fmt.Errorf("%w: it has no title", ErrExportUnavailable)
+	}
+
+	volumes, err := s.volumeSrvc.GetVolumesWithChapters(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sections, translated, fallbacks := exportSections(volumes, lang, n.OriginalLanguage)
+	if translated+fallbacks == 0 {
+		return nil, fmt.Errorf("%w: it has no approved chapters", ErrExportUnavailable)
+	}
+
+	if translated == 0 && title.Lang != lang {
+		return nil, fmt.Errorf("%w in '%s'", ErrExportUnavailable, lang)
+	}
+
+	metadata := epub.OPFMetadata{
+		Title:      []string{title.Title},
+		Language:   []string{lang},
+		Identifier: []string{"urn:uuid:" + n.ID},
+	}
+	if title.Description != nil && *title.Description != "" {
+		metadata.Description = []string{*title.Description}
+	}
+	if n.OriginalAuthor != nil && *n.OriginalAuthor != "" {
+		metadata.Creator = []string{*n.OriginalAuthor}
+	}
+	for _, g := range n.Genres {
+		metadata.Subject = append(metadata.Subject, g.Name)
+	}
+	for _, t := range n.Tags {
+		metadata.Subject = append(metadata.Subject, t.Name)
+	}
+
+	export := &NovelExport{
+		FileName:         exportFileName(title.Title, lang),
+		Lang:             lang,
+		ETag:             exportETag(n, volumes, lang),
+		FallbackChapters: fallbacks,
+		novelID:          n.ID,
+		book: &epub.Book{
+			Metadata: metadata,
+			Modified: n.UpdatedAt,
+			Sections: sections,
+		},
+	}
+	if n.Media != nil && n.Media.URL != nil {
+		export.coverURL = n.Media.URL
+	}
+	return export, nil
+}
+
+// WriteEpub downloads the export's cover and streams the book to w. A missing
+// cover does not fail the export.
+func (s *NovelService) WriteEpub(ctx context.Context, w io.Writer, export *NovelExport) error {
+	if export.coverURL != nil {
+		cover, err := s.fetchCover(ctx, *export.coverURL)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Exporting novel %s without cover: %v", export.novelID, err))
+		} else {
+			export.book.Cover = cover
+		}
+	}
+
+	if err := epub.Write(w, export.book); err != nil {
+		logger.Error(err, "failed to write EPUB export")
+		return errors.New("unable to write EPUB")
+	}
+	return nil
+}
+
+// exportETag fingerprints everything an export of the novel in lang is built
+// from, so that any edit, review decision or new cover changes it
+func exportETag(n *novel.Novel, volumes []volume.Volume, lang string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%d\n", n.ID, lang, n.UpdatedAt.UnixNano())
+	if n.OriginalAuthor != nil {
+		fmt.Fprintf(h, "author %s\n", *n.OriginalAuthor)
+	}
+	if n.Media != nil && n.Media.URL != nil {
+		fmt.Fprintf(h, "cover %s\n", *n.Media.URL)
+	}
+	for _, t := range n.Translations {
+		fmt.Fprintf(h, "novel %s %d\n", t.Lang, t.UpdatedAt.UnixNano())
+	}
+	for _, g := range n.Genres {
+		fmt.Fprintf(h, "genre %s\n", g.Name)
+	}
+	for _, t := range n.Tags {
+		fmt.Fprintf(h, "tag %s\n", t.Name)
+	}
+	for _, v := range volumes {
+		fmt.Fprintf(h, "volume %s %d %t\n", v.ID, v.Number, v.IsVirtual)
+		for _, t := range v.Translations {
+			fmt.Fprintf(h, "volume %s %d\n", t.Lang, t.UpdatedAt.UnixNano())
+		}
+		for _, c := range v.Chapters {
+			fmt.Fprintf(h, "chapter %s %d\n", c.ID, c.Number)
+			for _, t := range c.Translations {
+				fmt.Fprintf(h, "chapter %s %s %s %d\n", t.ID, t.Lang, t.Status, t.UpdatedAt.UnixNano())
+			}
+		}
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// exportSections turns volumes into EPUB sections, one per volume; chapters
+// of virtual volumes are listed without a section title. It returns how many
+// chapters were found in lang and how many fell back to originalLang.
+func exportSections(volumes []volume.Volume, lang, originalLang string) ([]epub.Section, int, int) {
+	sort.SliceStable(volumes, func(i, j int) bool { return volumes[i].Number < volumes[j].Number })
+
+	sections := make([]epub.Section, 0, len(volumes))
+	translated, fallbacks := 0, 0
+	for _, v := range volumes {
+		sort.SliceStable(v.Chapters, func(i, j int) bool { return v.Chapters[i].Number < v.Chapters[j].Number })
+
+		section := epub.Section{}
+		if !v.IsVirtual {
+			if t := volume.SelectTranslation(v.Translations, lang, v.OriginalLanguage); t != nil {
+				section.Title = t.Title
+			} else {
+				section.Title = fmt.Sprintf("Volume %d", v.Number)
+			}
+		}
+
+		for _, c := range v.Chapters {
+			t := exportTranslation(c.Translations, lang)
+			if t != nil {
+				translated++
+			} else if t = exportTranslation(c.Translations, originalLang); t != nil {
+				fallbacks++
+			} else {
+				continue
+			}
+
+			ch := epub.Chapter{Title: t.Title, Body: t.Content}
+			if ch.Title == "" {
+				ch.Title = fmt.Sprintf("Chapter %d", c.Number)
+			}
+			if t.Lang != lang {
+				ch.Lang = t.Lang
+			}
+			section.Chapters = append(section.Chapters, ch)
+		}
+
+		if len(section.Chapters) > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections, translated, fallbacks
+}
+
+func exportTranslation(translations []chapter.ChapterTranslation, lang string) *chapter.ChapterTranslation {
+	for i := range translations {
+		if translations[i].Lang == lang && translations[i].IsApproved() {
+			return &translations[i]
+		}
+	}
+	return nil
+}
+
+// exportFileName builds the download name from the title, dropping
+// characters file systems do not accept
+func exportFileName(title, lang string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return -1
+		}
+		return r
+	}, title)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "novel"
+	}
+	return fmt.Sprintf("%s (%s).epub", name, lang)
+}
+
+// fetchCover downloads the cover from the media host
+func (s *NovelService) fetchCover(ctx context.Context, url string) (*epub.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cover url: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download cover: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cover download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCoverSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cover: %w", err)
+	}
+	if len(data) > maxCoverSize {
+		return nil, fmt.Errorf("cover is larger than %d bytes", maxCoverSize)
+	}
+
+	// Readers only have to support the core image types. Aliases such as
+	// image/jpg are normalized, and a missing or generic type is sniffed.
+	declared, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	mediaType := epub.CoreImageType(declared)
+	if mediaType == "" {
+		mediaType = epub.CoreImageType(http.DetectContentType(data))
+	}
+	if mediaType == "" {
+		return nil, fmt.Errorf("cover has unsupported content type %q", declared)
+	}
+
+	return &epub.Image{MediaType: mediaType, Data: data}, nil
+}
@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
 	dommedia "simple-go/internal/domain/media"
 	"simple-go/internal/domain/novel"
@@ -21,6 +24,7 @@ type NovelService struct {
 	volumeSrvc         *VolumeService
 	epubSrvc           *EpubService
 	transformerFactory *transformer.EpubTransformerFactory
+	httpClient         *http.Client
 }
 
 func NewNovelService(uow repository.UnitOfWork, novelRepo repository.NovelRepository, mediaSrvc *MediaService, volumeSrvc *VolumeService, epubSrvc *EpubService) *NovelService {
@@ -31,6 +35,7 @@ func NewNovelService(uow repository.UnitOfWork, novelRepo repository.NovelReposi
 		volumeSrvc:         volumeSrvc,
 		epubSrvc:           epubSrvc,
 		transformerFactory: transformer.NewEpubTransformerFactory(),
+		httpClient:         &http.Client{Timeout: 15 * time.Second},
 	}
 }
 
@@ -236,19 +241,29 @@ func (s *NovelService) ProcessEpubUpload(ctx context.Context, fileBytes []byte)
 	novelData, err := tr.TransformToNovelData(ctx, rawEpub)
 	if err != nil {
 		logger.Error(err, "Failed to transform novel data")
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEpub, err)
 	}
 
-	volumes, err := tr.TransformToVolumes(ctx, rawEpub)
-	if err != nil {
-		logger.Error(err, "Failed to transform volumes")
-		return nil, err
-	}
+	var volumes []transformer.VolumeData
+	var chapters []transformer.ChapterData
+	if st, ok := tr.(transformer.EpubStructureTransformer); ok {
+		volumes, chapters, err = st.TransformToStructure(ctx, rawEpub)
+		if err != nil {
+			logger.Error(err, "Failed to transform volumes and chapters")
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEpub, err)
+		}
+	} else {
+		volumes, err = tr.TransformToVolumes(ctx, rawEpub)
+		if err != nil {
+			logger.Error(err, "Failed to transform volumes")
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEpub, err)
+		}
 
-	chapters, err := tr.TransformToChapters(ctx, rawEpub)
-	if err != nil {
-		logger.Error(err, "Failed to transform chapters")
-		return nil, err
+		chapters, err = tr.TransformToChapters(ctx, rawEpub)
+		if err != nil {
+			logger.Error(err, "Failed to transform chapters")
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEpub, err)
+		}
 	}
 
 	result := &transformer.EpubProcessResult{
@@ -270,14 +285,16 @@ func (s *NovelService) ProcessAndSaveEpubUpload(ctx context.Context, fileBytes [
 		return nil, err
 	}
 
+	persist := &epubPersistence{
+		ctx:       ctx,
+		creatorID: creatorID,
+		result:    result,
+		mediaSrvc: s.mediaSrvc,
+	}
+	persist.uploadCoverImage()
+
 	err = s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
-		persist := &epubPersistence{
-			ctx:       ctx,
-			creatorID: creatorID,
-			result:    result,
-			provider:  provider,
-			mediaSrvc: s.mediaSrvc,
-		}
+		persist.provider = provider
 		return persist.run()
 	})
 
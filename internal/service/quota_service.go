@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"simple-go/internal/domain/quota"
+	"simple-go/internal/repository"
+	"simple-go/pkg/logger"
+)
+
+// QuotaService enforces the translation volume limits of users. Character
+// usage is what a job is charged when it is created: the source characters
+// left for the translator after exact translation memory matches.
+type QuotaService struct {
+	policy    quota.Policy
+	userRepo  repository.UserRepository
+	jobRepo   repository.TranslationJobRepository
+	quotaRepo repository.QuotaRepository
+}
+
+func NewQuotaService(
+	policy quota.Policy,
+	userRepo repository.UserRepository,
+	jobRepo repository.TranslationJobRepository,
+	quotaRepo repository.QuotaRepository,
+) *QuotaService {
+	return &QuotaService{
+		policy:    policy,
+		userRepo:  userRepo,
+		jobRepo:   jobRepo,
+		quotaRepo: quotaRepo,
+	}
+}
+
+// Enabled reports whether any limit is configured
+func (s *QuotaService) Enabled() bool {
+	if limited(s.policy.Default) || limited(s.policy.Global) {
+		return true
+	}
+	for _, l := range s.policy.Roles {
+		if limited(l) {
+			return true
+		}
+	}
+	return false
+}
+
+func limited(l quota.Limits) bool {
+	return l.DailyCharacters >= 0 || l.MonthlyCharacters >= 0 || l.MaxActiveJobs >= 0
+}
+
+// GetUsage returns a user's current usage against their limits
+func (s *QuotaService) GetUsage(ctx context.Context, userID string) (*quota.UsageDTO, error) {
+	limits, err := s.limitsFor(ctx, s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	daily, err := s.quotaRepo.SumCharacters(ctx, userID, quota.DayStart(now))
+	if err != nil {
+		logger.Error(err, "failed to sum daily translation usage")
+		return nil, errors.New("unable to retrieve quota usage")
+	}
+	monthly, err := s.quotaRepo.SumCharacters(ctx, userID, quota.MonthStart(now))
+	if err != nil {
+		logger.Error(err, "failed to sum monthly translation usage")
+		return nil, errors.New("unable to retrieve quota usage")
+	}
+	active, err := s.jobRepo.CountActive(ctx, userID)
+	if err != nil {
+		logger.Error(err, "failed to count active translation jobs")
+		return nil, errors.New("unable to retrieve quota usage")
+	}
+
+	return &quota.UsageDTO{
+		Daily:   quota.NewCharacterUsageDTO(daily, limits.DailyCharacters, quota.DayStart(now).AddDate(0, 0, 1)),
+		Monthly: quota.NewCharacterUsageDTO(monthly, limits.MonthlyCharacters, quota.MonthStart(now).AddDate(0, 1, 0)),
+		ActiveJobs: quota.ActiveJobsDTO{
+			Active: int(active),
+			Limit:  limits.MaxActiveJobs,
+		},
+	}, nil
+}
+
+// Check returns a *quota.ExceededError when new jobs of the given total size
+// would go over the user's or the global limits. It locks the quotas for the
+// rest of the transaction, so the jobs should be charged in the same
+// transaction.
+func (s *QuotaService) Check(ctx context.Context, provider repository.RepositoryProvider, userID string, jobs, characters int) error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	if err := provider.Quota().Lock(ctx); err != nil {
+		logger.Error(err, "failed to lock translation quotas")
+		return errors.New("unable to check translation quota")
+	}
+
+	limits, err := s.limitsFor(ctx, provider.User(), userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkLimits(ctx, provider, quota.ScopeUser, userID, limits, jobs, characters); err != nil {
+		return err
+	}
+	return s.checkLimits(ctx, provider, quota.ScopeGlobal, "", s.policy.Global, jobs, characters)
+}
+
+func (s *QuotaService) checkLimits(ctx context.Context, provider repository.RepositoryProvider, scope, userID string, limits quota.Limits, jobs, characters int) error {
+	if limits.MaxActiveJobs >= 0 {
+		active, err := provider.TranslationJob().CountActive(ctx, userID)
+		if err != nil {
+			logger.Error(err, "failed to count active translation jobs")
+			return errors.New("unable to check translation quota")
+		}
+		if int(active)+jobs > limits.MaxActiveJobs {
+			return &quota.ExceededError{Limit: quota.LimitActiveJobs, Scope: scope, Max: limits.MaxActiveJobs, Used: int(active), Requested: jobs}
+		}
+	}
+
+	now := time.Now()
+	windows := []struct {
+		limit string
+		max   int
+		since time.Time
+	}{
+		{quota.LimitDailyCharacters, limits.DailyCharacters, quota.DayStart(now)},
+		{quota.LimitMonthlyCharacters, limits.MonthlyCharacters, quota.MonthStart(now)},
+	}
+	for _, w := range windows {
+		if w.max < 0 {
+			continue
+		}
+		used, err := provider.Quota().SumCharacters(ctx, userID, w.since)
+		if err != nil {
+			logger.Error(err, "failed to sum translation usage")
+			return errors.New("unable to check translation quota")
+		}
+		if used+characters > w.max {
+			return &quota.ExceededError{Limit: w.limit, Scope: scope, Max: w.max, Used: used, Requested: characters}
+		}
+	}
+
+	return nil
+}
+
+// Charge records the characters a new job uses
+func (s *QuotaService) Charge(ctx context.Context, provider repository.RepositoryProvider, userID, jobID string, characters int) error {
+	err := provider.Quota().RecordUsage(ctx, &quota.Usage{
+		UserID:     userID,
+		JobID:      jobID,
+		Characters: characters,
+	})
+	if err != nil {
+		logger.Error(err, "failed to record translation usage")
+		return errors.New("unable to record translation usage")
+	}
+	return nil
+}
+
+func (s *QuotaService) limitsFor(ctx context.Context, userRepo repository.UserRepository, userID string) (quota.Limits, error) {
+	if len(s.policy.Roles) == 0 {
+		return s.policy.Default, nil
+	}
+
+	roles, err := userRepo.GetRoles(ctx, userID)
+	if err != nil {
+		logger.Error(err, "failed to get user roles")
+		return quota.Limits{}, errors.New("unable to determine translation quota")
+	}
+
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = r.Name
+	}
+	return s.policy.ForRoles(names), nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"simple-go/internal/domain/quota"
+	"simple-go/internal/domain/role"
+	"simple-go/internal/repository"
+)
+
+type quotaProvider struct {
+	repository.RepositoryProvider
+	users  *quotaUserRepo
+	jobs   *quotaJobRepo
+	quotas *quotaUsageRepo
+}
+
+func (p *quotaProvider) User() repository.UserRepository                     { return p.users }
+func (p *quotaProvider) TranslationJob() repository.TranslationJobRepository { return p.jobs }
+func (p *quotaProvider) Quota() repository.QuotaRepository                   { return p.quotas }
+
+type quotaUserRepo struct {
+	repository.UserRepository
+	roles []role.Role
+}
+
+func (r *quotaUserRepo) GetRoles(ctx context.Context, userID string) ([]role.Role, error) {
+	return r.roles, nil
+}
+
+// quotaJobRepo counts active jobs per creator; "" is everyone's
+type quotaJobRepo struct {
+	repository.TranslationJobRepository
+	active map[string]int64
+}
+
+func (r *quotaJobRepo) CountActive(ctx context.Context, createdBy string) (int64, error) {
+	return r.active[createdBy], nil
+}
+
+// quotaUsageRepo returns the same usage for every window, so each case only
+// limits the window it is about
+type quotaUsageRepo struct {
+	repository.QuotaRepository
+	used   map[string]int
+	locked bool
+}
+
+func (r *quotaUsageRepo) SumCharacters(ctx context.Context, userID string, since time.Time) (int, error) {
+	return r.used[userID], nil
+}
+
+func (r *quotaUsageRepo) Lock(ctx context.Context) error {
+	r.locked = true
+	return nil
+}
+
+func TestQuotaServiceCheck(t *testing.T) {
+	unlimited := quota.Limits{DailyCharacters: quota.Unlimited, MonthlyCharacters: quota.Unlimited, MaxActiveJobs: quota.Unlimited}
+	daily := func(n int) quota.Limits {
+		return quota.Limits{DailyCharacters: n, MonthlyCharacters: quota.Unlimited, MaxActiveJobs: quota.Unlimited}
+	}
+
+	tests := []struct {
+		name       string
+		policy     quota.Policy
+		roles      []string
+		active     map[string]int64
+		used       map[string]int
+		jobs       int
+		characters int
+		// want is the exceeded limit and scope, or nil
+		want *quota.ExceededError
+	}{
+		{
+			name:   "everything unlimited",
+			policy: quota.Policy{Default: unlimited, Global: unlimited},
+			active: map[string]int64{"user-1": 100}, used: map[string]int{"user-1": 1 << 30},
+			jobs: 1, characters: 1000,
+		},
+		{
+			name:   "within the daily limit",
+			policy: quota.Policy{Default: daily(1000), Global: unlimited},
+			used:   map[string]int{"user-1": 990},
+			jobs:   1, characters: 10,
+		},
+		{
+			name:   "over the daily limit",
+			policy: quota.Policy{Default: daily(1000), Global: unlimited},
+			used:   map[string]int{"user-1": 990},
+			jobs:   1, characters: 11,
+			want: &quota.ExceededError{Limit: quota.LimitDailyCharacters, Scope: quota.ScopeUser, Max: 1000, Used: 990, Requested: 11},
+		},
+		{
+			name: "over the monthly limit",
+			policy: quota.Policy{
+				Default: quota.Limits{DailyCharacters: quota.Unlimited, MonthlyCharacters: 5000, MaxActiveJobs: quota.Unlimited},
+				Global:  unlimited,
+			},
+			used: map[string]int{"user-1": 4900},
+			jobs: 1, characters: 200,
+			want: &quota.ExceededError{Limit: quota.LimitMonthlyCharacters, Scope: quota.ScopeUser, Max: 5000, Used: 4900, Requested: 200},
+		},
+		{
+			name: "active jobs limit reached",
+			policy: quota.Policy{
+				Default: quota.Limits{DailyCharacters: quota.Unlimited, MonthlyCharacters: quota.Unlimited, MaxActiveJobs: 2},
+				Global:  unlimited,
+			},
+			active: map[string]int64{"user-1": 2},
+			jobs:   1,
+			want:   &quota.ExceededError{Limit: quota.LimitActiveJobs, Scope: quota.ScopeUser, Max: 2, Used: 2, Requested: 1},
+		},
+		{
+			name: "batch over the active jobs limit",
+			policy: quota.Policy{
+				Default: quota.Limits{DailyCharacters: quota.Unlimited, MonthlyCharacters: quota.Unlimited, MaxActiveJobs: 3},
+				Global:  unlimited,
+			},
+			active: map[string]int64{"user-1": 1},
+			jobs:   3,
+			want:   &quota.ExceededError{Limit: quota.LimitActiveJobs, Scope: quota.ScopeUser, Max: 3, Used: 1, Requested: 3},
+		},
+		{
+			name: "active jobs are checked before characters",
+			policy: quota.Policy{
+				Default: quota.Limits{DailyCharacters: 10, MonthlyCharacters: 10, MaxActiveJobs: 1},
+				Global:  unlimited,
+			},
+			active: map[string]int64{"user-1": 1}, used: map[string]int{"user-1": 10},
+			jobs: 1, characters: 100,
+			want: &quota.ExceededError{Limit: quota.LimitActiveJobs, Scope: quota.ScopeUser, Max: 1, Used: 1, Requested: 1},
+		},
+		{
+			name: "role limits replace the default",
+			policy: quota.Policy{
+				Default: daily(100),
+				Roles:   map[string]quota.Limits{"translator": daily(1000)},
+				Global:  unlimited,
+			},
+			roles: []string{"translator"},
+			used:  map[string]int{"user-1": 500},
+			jobs:  1, characters: 100,
+		},
+		{
+			name: "unlimited role",
+			policy: quota.Policy{
+				Default: daily(100),
+				Roles:   map[string]quota.Limits{"admin": unlimited},
+				Global:  unlimited,
+			},
+			roles: []string{"admin"},
+			used:  map[string]int{"user-1": 500},
+			jobs:  1, characters: 100,
+		},
+		{
+			name:   "over the global limit",
+			policy: quota.Policy{Default: daily(1000), Global: daily(5000)},
+			used:   map[string]int{"user-1": 100, "": 4950},
+			jobs:   1, characters: 100,
+			want: &quota.ExceededError{Limit: quota.LimitDailyCharacters, Scope: quota.ScopeGlobal, Max: 5000, Used: 4950, Requested: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := make([]role.Role, len(tt.roles))
+			for i, name := range tt.roles {
+				roles[i] = role.Role{Name: name}
+			}
+			provider := &quotaProvider{
+				users:  &quotaUserRepo{roles: roles},
+				jobs:   &quotaJobRepo{active: tt.active},
+				quotas: &quotaUsageRepo{used: tt.used},
+			}
+			s := NewQuotaService(tt.policy, provider.users, provider.jobs, provider.quotas)
+
+			err := s.Check(context.Background(), provider, "user-1", tt.jobs, tt.characters)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Check: %v, want no error", err)
+				}
+				return
+			}
+
+			var exceeded *quota.ExceededError
+			if !errors.As(err, &exceeded) {
+				t.Fatalf("Check = %v, want a *quota.ExceededError", err)
+			}
+			if *exceeded != *tt.want {
+				t.Errorf("exceeded = %+v, want %+v", *exceeded, *tt.want)
+			}
+			if !provider.quotas.locked {
+				t.Error("quotas were checked without the lock")
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/webhook"
+	"simple-go/pkg/logger"
+	"simple-go/pkg/queue"
+)
+
+// TranslationEventService fans translation job events out over Redis pub/sub
+// and to webhook subscriptions. Without Redis, live subscribers fall back to
+// polling.
+type TranslationEventService struct {
+	redisQueue *queue.RedisQueue
+	webhooks   *WebhookService
+
+	// lastProgress remembers the last progress sent to webhooks per job so that
+	// job.progress fires at most once per percentage point in this process
+	mu           sync.Mutex
+	lastProgress map[string]int
+}
+
+func NewTranslationEventService(redisQueue *queue.RedisQueue, webhooks *WebhookService) *TranslationEventService {
+	return &TranslationEventService{
+		redisQueue:   redisQueue,
+		webhooks:     webhooks,
+		lastProgress: make(map[string]int),
+	}
+}
+
+// Publish broadcasts an event. Events are best effort, so failures are only logged.
+func (s *TranslationEventService) Publish(ctx context.Context, event job.TranslationJobEventDTO) {
+	if s == nil {
+		return
+	}
+
+	if eventType, ok := s.webhookEventType(event); ok {
+		s.webhooks.Dispatch(ctx, eventType, event)
+	}
+
+	if s.redisQueue == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.Error(err, "failed to encode translation job event")
+		return
+	}
+
+	if err := s.redisQueue.PublishJobEvent(ctx, event.JobID, payload); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to publish %s event for job %s", event.Type, event.JobID))
+	}
+}
+
+// Subscribe streams the events of one job until ctx is done. It returns a nil
+// channel when no event transport is configured.
+func (s *TranslationEventService) Subscribe(ctx context.Context, jobID string) (<-chan job.TranslationJobEventDTO, error) {
+	if s == nil || s.redisQueue == nil {
+		return nil, nil
+	}
+
+	payloads, err := s.redisQueue.SubscribeJobEvents(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan job.TranslationJobEventDTO)
+	go func() {
+		defer close(events)
+		for payload := range payloads {
+			var event job.TranslationJobEventDTO
+			if err := json.Unmarshal([]byte(payload), &event); err != nil {
+				logger.Error(err, "failed to decode translation job event")
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// webhookEventType maps a job event to the webhook event it triggers, if any
+func (s *TranslationEventService) webhookEventType(event job.TranslationJobEventDTO) (string, bool) {
+	if s.webhooks == nil {
+		return "", false
+	}
+
+	switch event.Type {
+	case job.TranslationJobEventCreated:
+		return webhook.EventJobCreated, true
+	case job.TranslationJobEventProgress:
+		if event.Progress == nil || !s.progressAdvanced(event.JobID, *event.Progress) {
+			return "", false
+		}
+		return webhook.EventJobProgress, true
+	case job.TranslationJobEventStatus:
+		switch event.Status {
+		case job.TranslationJobStatusCompleted:
+			s.forgetProgress(event.JobID)
+			return webhook.EventJobCompleted, true
+		case job.TranslationJobStatusFailed:
+			s.forgetProgress(event.JobID)
+			return webhook.EventJobFailed, true
+		case job.TranslationJobStatusCancelled:
+			s.forgetProgress(event.JobID)
+		}
+	}
+	return "", false
+}
+
+func (s *TranslationEventService) progressAdvanced(jobID string, progress int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.lastProgress[jobID]; ok && last == progress {
+		return false
+	}
+	s.lastProgress[jobID] = progress
+	return true
+}
+
+func (s *TranslationEventService) forgetProgress(jobID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lastProgress, jobID)
+}
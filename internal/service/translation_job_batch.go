@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"simple-go/internal/domain/job"
+	"simple-go/internal/repository"
+	"simple-go/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// CreateTranslationJobBatch creates one job per target language in a single
+// transaction, so either all of them are created or none. The novel is loaded
+// once for all languages. Quotas are checked against the batch as a whole.
+func (s *TranslationJobService) CreateTranslationJobBatch(
+	ctx context.Context,
+	userID string,
+	dto job.CreateTranslationJobBatchDTO,
+) (*job.TranslationJobBatchResponseDTO, error) {
+	langs := make([]string, 0, len(dto.TargetLangs))
+	seen := make(map[string]bool, len(dto.TargetLangs))
+	for _, lang := range dto.TargetLangs {
+		if !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+
+	var batch *job.TranslationJobBatch
+
+	err := s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		n, volumes, err := loadNovelSource(ctx, provider, dto.NovelID)
+		if err != nil {
+			return err
+		}
+
+		// Every language is planned from the same source text, so it is
+		// segmented once for all of them
+		segments := make(segmentCache)
+		planned := make([]*plannedJob, len(langs))
+		characters := 0
+		for i, lang := range langs {
+			if planned[i], err = s.planJob(ctx, provider, n, volumes, dto.JobDTO(lang), segments); err != nil {
+				return fmt.Errorf("%s: %w", lang, err)
+			}
+			characters += planned[i].characters
+		}
+
+		if err := s.quota.Check(ctx, provider, userID, len(planned), characters); err != nil {
+			return err
+		}
+
+		batch, err = provider.TranslationJob().CreateBatch(ctx, &job.TranslationJobBatch{
+			NovelID:     dto.NovelID,
+			TargetLangs: langs,
+			CreatedBy:   &userID,
+		})
+		if err != nil {
+			logger.Error(err, "failed to create translation job batch")
+			return errors.New("unable to create translation job batch")
+		}
+
+		for _, p := range planned {
+			createdJob, err := s.createPlannedJob(ctx, provider, userID, n, p, &batch.ID)
+			if err != nil {
+				return err
+			}
+			batch.Jobs = append(batch.Jobs, *createdJob)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range batch.Jobs {
+		s.publishJob(ctx, &batch.Jobs[i])
+		s.events.Publish(ctx, job.NewJobStateEvent(job.TranslationJobEventCreated, batch.Jobs[i]))
+	}
+
+	response := job.MapTranslationJobBatchToDTO(*batch)
+	return &response, nil
+}
+
+// GetBatchByID retrieves a batch with the progress of its jobs added up
+func (s *TranslationJobService) GetBatchByID(ctx context.Context, id string) (*job.TranslationJobBatchResponseDTO, error) {
+	batch, err := s.jobRepo.GetBatchByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("translation job batch not found")
+		}
+		logger.Error(err, "failed to get translation job batch")
+		return nil, errors.New("unable to retrieve translation job batch")
+	}
+
+	response := job.MapTranslationJobBatchToDTO(*batch)
+	return &response, nil
+}
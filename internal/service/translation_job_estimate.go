@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+	"simple-go/internal/repository"
+	"simple-go/pkg/epub"
+	"simple-go/pkg/logger"
+	"simple-go/pkg/textstat"
+)
+
+// EstimateTranslationJob plans a job the way CreateTranslationJob would and
+// measures the source text of every planned subtask, without creating
+// anything. It fails where creating the job would, for instance when the
+// novel already has an active job or nothing to translate. Segments with an
+// exact translation memory match are counted but not billed, since the worker
+// fills them in without the translator.
+func (s *TranslationJobService) EstimateTranslationJob(ctx context.Context, dto job.CreateTranslationJobDTO) (*job.TranslationJobEstimateDTO, error) {
+	var estimate *job.TranslationJobEstimateDTO
+
+	err := s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		n, volumes, err := loadNovelSource(ctx, provider, dto.NovelID)
+		if err != nil {
+			return err
+		}
+
+		planned, err := s.planJob(ctx, provider, n, volumes, dto, nil)
+		if err != nil {
+			return err
+		}
+		estimate = planned.estimate
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return estimate, nil
+}
+
+// estimate measures planned subtasks. CreateTranslationJob uses it to charge
+// the job against the user's quota. The segments of each entity's text are
+// taken from segments when it already holds them.
+func (s *TranslationJobService) estimate(
+	ctx context.Context,
+	n *novel.Novel,
+	volumes []volume.Volume,
+	subtasks []job.TranslationSubtask,
+	dto job.CreateTranslationJobDTO,
+	segments segmentCache,
+) (*job.TranslationJobEstimateDTO, error) {
+	volumesByID := make(map[string]*volume.Volume, len(volumes))
+	chaptersByID := make(map[string]*chapter.Chapter)
+	for i := range volumes {
+		volumesByID[volumes[i].ID] = &volumes[i]
+		for k := range volumes[i].Chapters {
+			chaptersByID[volumes[i].Chapters[k].ID] = &volumes[i].Chapters[k]
+		}
+	}
+
+	estimate := &job.TranslationJobEstimateDTO{
+		NovelID:    dto.NovelID,
+		FromLang:   n.OriginalLanguage,
+		TargetLang: dto.TargetLang,
+		PivotLang:  dto.PivotLang,
+		Mode:       dto.Mode,
+		ByEntity:   make(map[string]job.TranslationEstimateStatsDTO),
+		Subtasks:   make([]job.SubtaskEstimateDTO, 0, len(subtasks)),
+	}
+
+	for _, st := range subtasks {
+		var texts, pivotTexts []string
+		var hasTranslation bool
+		switch st.EntityType {
+		case job.EntityTypeChapter:
+			texts, hasTranslation = chapterSourceTexts(chaptersByID[st.EntityID], n.OriginalLanguage, dto.TargetLang)
+			pivotTexts = chapterPivotTexts(chaptersByID[st.EntityID], dto.PivotLang)
+		case job.EntityTypeVolume:
+			texts, hasTranslation = volumeSourceTexts(volumesByID[st.EntityID], n.OriginalLanguage, dto.TargetLang)
+			pivotTexts = volumePivotTexts(volumesByID[st.EntityID], dto.PivotLang)
+		case job.EntityTypeNovel:
+			texts, hasTranslation = novelSourceTexts(n, dto.TargetLang)
+			pivotTexts = novelPivotTexts(n, dto.PivotLang)
+		}
+
+		sourceKey := st.EntityType + ":" + st.EntityID
+		var stats job.TranslationEstimateStatsDTO
+		var err error
+		switch {
+		case dto.PivotLang == "":
+			stats, err = s.estimateSegments(ctx, n.OriginalLanguage, dto.TargetLang, segments.get(sourceKey, texts))
+		case pivotTexts != nil:
+			// Only the pivot to target leg runs
+			stats, err = s.estimateSegments(ctx, dto.PivotLang, dto.TargetLang, segments.get(sourceKey+":"+dto.PivotLang, pivotTexts))
+		default:
+			// Both legs run; the pivot text is taken to be as long as the
+			// source and to have no memory matches
+			sourceSegments := segments.get(sourceKey, texts)
+			stats, err = s.estimateSegments(ctx, n.OriginalLanguage, dto.PivotLang, sourceSegments)
+			stats.Add(measureSegments(sourceSegments, nil))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		estimate.Subtasks = append(estimate.Subtasks, job.SubtaskEstimateDTO{
+			EntityType:                  st.EntityType,
+			EntityID:                    st.EntityID,
+			ParentVolumeID:              st.ParentVolumeID,
+			Seq:                         st.Seq,
+			Priority:                    st.Priority,
+			HasTranslation:              hasTranslation,
+			TranslationEstimateStatsDTO: stats,
+		})
+		estimate.Totals.Add(stats)
+		byEntity := estimate.ByEntity[st.EntityType]
+		byEntity.Add(stats)
+		estimate.ByEntity[st.EntityType] = byEntity
+		if hasTranslation {
+			estimate.AlreadyTranslated++
+		}
+	}
+
+	return estimate, nil
+}
+
+// estimateSegments measures translatable segments and looks them up in the
+// translation memory
+func (s *TranslationJobService) estimateSegments(ctx context.Context, fromLang, targetLang string, segments []string) (job.TranslationEstimateStatsDTO, error) {
+	exact := make(map[int]bool)
+	if s.memory != nil {
+		matches, err := s.memory.ExactMatches(ctx, fromLang, targetLang, segments)
+		if err != nil {
+			logger.Error(err, "failed to look up translation memory")
+			return job.TranslationEstimateStatsDTO{}, errors.New("unable to look up translation memory")
+		}
+		for i := range matches {
+			exact[i] = true
+		}
+	}
+
+	return measureSegments(segments, exact), nil
+}
+
+// segmentCache holds the translatable segments of entity texts by entity and
+// language, so a batch splits each text once rather than once per target
+// language. A nil cache splits the texts on every call.
+type segmentCache map[string][]string
+
+func (c segmentCache) get(key string, texts []string) []string {
+	if segments, ok := c[key]; ok {
+		return segments
+	}
+	segments := translatableSegments(texts)
+	if c != nil {
+		c[key] = segments
+	}
+	return segments
+}
+
+func translatableSegments(texts []string) []string {
+	segments := make([]string, 0)
+	for _, text := range texts {
+		segments = append(segments, epub.TranslatableSegments(epub.SegmentHTML(text))...)
+	}
+	return segments
+}
+
+// measureSegments sizes segments; those marked exact are served by the
+// translation memory and not billable
+func measureSegments(segments []string, exact map[int]bool) job.TranslationEstimateStatsDTO {
+	var stats job.TranslationEstimateStatsDTO
+	for i, seg := range segments {
+		m := textstat.Measure(seg)
+		stats.Segments++
+		stats.Characters += m.Characters
+		stats.Words += m.Words
+		stats.Tokens += m.Tokens
+		if exact[i] {
+			stats.MemorySegments++
+			stats.MemoryCharacters += m.Characters
+			continue
+		}
+		stats.BillableCharacters += m.Characters
+		stats.BillableTokens += m.Tokens
+	}
+
+	return stats
+}
+
+// The source text helpers mirror what the worker sends to the translator for
+// each entity type, and report whether a target translation already exists.
+
+func chapterSourceTexts(ch *chapter.Chapter, fromLang, targetLang string) ([]string, bool) {
+	if ch == nil {
+		return nil, false
+	}
+	source := chapter.SelectTranslation(ch.Translations, fromLang)
+	if source == nil {
+		return nil, false
+	}
+
+	exists := false
+	for _, tr := range ch.Translations {
+		if tr.Lang == targetLang {
+			exists = true
+			break
+		}
+	}
+	return []string{source.Title, source.Content}, exists
+}
+
+func volumeSourceTexts(vol *volume.Volume, fromLang, targetLang string) ([]string, bool) {
+	if vol == nil {
+		return nil, false
+	}
+	source := volume.SelectTranslation(vol.Translations, fromLang, vol.OriginalLanguage)
+	if source == nil {
+		return nil, false
+	}
+
+	exists := false
+	for _, tr := range vol.Translations {
+		if tr.Lang == targetLang {
+			exists = true
+			break
+		}
+	}
+	return titleAndDescription(source.Title, source.Description), exists
+}
+
+func novelSourceTexts(n *novel.Novel, targetLang string) ([]string, bool) {
+	source := novel.SelectTranslation(n.Translations, n.OriginalLanguage, n.OriginalLanguage)
+	if source == nil {
+		return nil, false
+	}
+
+	exists := false
+	for _, tr := range n.Translations {
+		if tr.Lang == targetLang {
+			exists = true
+			break
+		}
+	}
+	return titleAndDescription(source.Title, source.Description), exists
+}
+
+// The pivot text helpers return the texts a pivot translation starts from,
+// or nil when the pivot language translation has to be made first.
+
+func chapterPivotTexts(ch *chapter.Chapter, pivotLang string) []string {
+	if ch == nil || pivotLang == "" {
+		return nil
+	}
+	if pivot := chapter.PivotTranslation(ch.Translations, pivotLang); pivot != nil {
+		return []string{pivot.Title, pivot.Content}
+	}
+	return nil
+}
+
+func volumePivotTexts(vol *volume.Volume, pivotLang string) []string {
+	if vol == nil || pivotLang == "" {
+		return nil
+	}
+	if pivot := volume.PivotTranslation(vol.Translations, pivotLang); pivot != nil {
+		return titleAndDescription(pivot.Title, pivot.Description)
+	}
+	return nil
+}
+
+func novelPivotTexts(n *novel.Novel, pivotLang string) []string {
+	if pivotLang == "" {
+		return nil
+	}
+	if pivot := novel.PivotTranslation(n.Translations, pivotLang); pivot != nil {
+		return titleAndDescription(pivot.Title, pivot.Description)
+	}
+	return nil
+}
+
+func titleAndDescription(title string, description *string) []string {
+	if description == nil || *description == "" {
+		return []string{title}
+	}
+	return []string{title, *description}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+)
+
+// planTranslationSubtasks decides which chapters, volumes and the novel itself
+// a job has to translate. Volumes are expected in number order with their
+// chapters and translations preloaded. The returned subtasks have no JobID yet.
+func planTranslationSubtasks(
+	n *novel.Novel,
+	volumes []volume.Volume,
+	targetLang string,
+	scope job.TranslationJobScope,
+) ([]job.TranslationSubtask, error) {
+	if scope.ChapterFrom != nil && scope.ChapterTo != nil && *scope.ChapterFrom > *scope.ChapterTo {
+		return nil, errors.New("chapter_from must not be greater than chapter_to")
+	}
+
+	incremental := scope.Mode == job.TranslationJobModeIncremental
+	fromLang := n.OriginalLanguage
+
+	scopedVolumes, err := filterVolumes(volumes, scope.VolumeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	subtasks := make([]job.TranslationSubtask, 0)
+	chaptersInScope := 0
+	volumesWithChapters := make([]volume.Volume, 0, len(scopedVolumes))
+
+	// Chapter subtasks (priority 200)
+	for _, vol := range scopedVolumes {
+		chapters := make([]chapter.Chapter, len(vol.Chapters))
+		copy(chapters, vol.Chapters)
+		sort.SliceStable(chapters, func(i, j int) bool {
+			return chapters[i].Number < chapters[j].Number
+		})
+
+		matched := false
+		for _, ch := range chapters {
+			if !chapterInRange(ch.Number, scope) {
+				continue
+			}
+			matched = true
+			chaptersInScope++
+
+			if incremental && !chapterNeedsTranslation(ch, fromLang, targetLang) {
+				continue
+			}
+
+			volumeID := ch.VolumeID
+			subtasks = append(subtasks, job.TranslationSubtask{
+				EntityType:     job.EntityTypeChapter,
+				EntityID:       ch.ID,
+				ParentVolumeID: &volumeID,
+				Seq:            chaptersInScope,
+				Priority:       job.PriorityChapter,
+				Status:         job.TranslationSubtaskStatusPending,
+			})
+		}
+
+		// A chapter range only pulls in the volumes it touches
+		if matched || (scope.ChapterFrom == nil && scope.ChapterTo == nil) {
+			volumesWithChapters = append(volumesWithChapters, vol)
+		}
+	}
+
+	if chaptersInScope == 0 {
+		if len(scope.VolumeIDs) > 0 || scope.ChapterFrom != nil || scope.ChapterTo != nil {
+			return nil, errors.New("no chapters match the requested scope")
+		}
+		return nil, errors.New("novel has no chapters to translate")
+	}
+
+	// Volume subtasks (priority 150)
+	for i, vol := range volumesWithChapters {
+		if incremental && !volumeNeedsTranslation(vol, fromLang, targetLang) {
+			continue
+		}
+
+		subtasks = append(subtasks, job.TranslationSubtask{
+			EntityType: job.EntityTypeVolume,
+			EntityID:   vol.ID,
+			Seq:        i + 1,
+			Priority:   job.PriorityVolume,
+			Status:     job.TranslationSubtaskStatusPending,
+		})
+	}
+
+	// Novel subtask (priority 100 - highest, processed last)
+	if !incremental || novelNeedsTranslation(n, fromLang, targetLang) {
+		subtasks = append(subtasks, job.TranslationSubtask{
+			EntityType: job.EntityTypeNovel,
+			EntityID:   n.ID,
+			Seq:        1,
+			Priority:   job.PriorityNovel,
+			Status:     job.TranslationSubtaskStatusPending,
+		})
+	}
+
+	return subtasks, nil
+}
+
+func filterVolumes(volumes []volume.Volume, volumeIDs []string) ([]volume.Volume, error) {
+	if len(volumeIDs) == 0 {
+		return volumes, nil
+	}
+
+	byID := make(map[string]volume.Volume, len(volumes))
+	for _, vol := range volumes {
+		byID[vol.ID] = vol
+	}
+
+	wanted := make(map[string]bool, len(volumeIDs))
+	for _, id := range volumeIDs {
+		if _, ok := byID[id]; !ok {
+			return nil, fmt.Errorf("volume %s does not belong to this novel", id)
+		}
+		wanted[id] = true
+	}
+
+	// Keep the novel's volume order rather than the request order
+	filtered := make([]volume.Volume, 0, len(volumeIDs))
+	for _, vol := range volumes {
+		if wanted[vol.ID] {
+			filtered = append(filtered, vol)
+		}
+	}
+	return filtered, nil
+}
+
+func chapterInRange(number int, scope job.TranslationJobScope) bool {
+	if scope.ChapterFrom != nil && number < *scope.ChapterFrom {
+		return false
+	}
+	if scope.ChapterTo != nil && number > *scope.ChapterTo {
+		return false
+	}
+	return true
+}
+
+// chapterNeedsTranslation reports whether the target translation is missing,
+// marked outdated or older than the source it would be translated from.
+// Chapters without a usable source are skipped since the worker could not
+// translate them. The volume and novel variants below follow the same rules.
+func chapterNeedsTranslation(ch chapter.Chapter, fromLang, targetLang string) bool {
+	source := chapter.SelectTranslation(ch.Translations, fromLang)
+	if source == nil || source.Lang == targetLang {
+		return false
+	}
+
+	for _, tr := range ch.Translations {
+		if tr.Lang == targetLang {
+			return tr.Outdated || source.UpdatedAt.After(tr.UpdatedAt)
+		}
+	}
+	return true
+}
+
+func volumeNeedsTranslation(vol volume.Volume, fromLang, targetLang string) bool {
+	source := volume.SelectTranslation(vol.Translations, fromLang, vol.OriginalLanguage)
+	if source == nil || source.Lang == targetLang {
+		return false
+	}
+
+	for _, tr := range vol.Translations {
+		if tr.Lang == targetLang {
+			return source.UpdatedAt.After(tr.UpdatedAt)
+		}
+	}
+	return true
+}
+
+func novelNeedsTranslation(n *novel.Novel, fromLang, targetLang string) bool {
+	source := novel.SelectTranslation(n.Translations, fromLang, n.OriginalLanguage)
+	if source == nil || source.Lang == targetLang {
+		return false
+	}
+
+	for _, tr := range n.Translations {
+		if tr.Lang == targetLang {
+			return source.UpdatedAt.After(tr.UpdatedAt)
+		}
+	}
+	return true
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+	"simple-go/internal/repository"
+	"simple-go/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// PublishJob promotes the staged results of a finished job in one transaction.
+// Jobs in manual publish mode are published this way by a reviewer; for the
+// others it publishes what is left, e.g. the done subtasks of a failed job.
+func (s *TranslationJobService) PublishJob(ctx context.Context, id, userID string) (*job.PublishResultDTO, error) {
+	if err := s.checkStagingAccess(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var published int
+	err := s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		staged, err := provider.TranslationJob().GetStagedSubtasks(ctx, id)
+		if err != nil {
+			logger.Error(err, "failed to get staged subtasks")
+			return errors.New("unable to retrieve staged results")
+		}
+		if len(staged) == 0 {
+			return errors.New("job has no staged results")
+		}
+
+		published, err = publishStagedResults(ctx, provider, id, &userID)
+		if err != nil {
+			logger.Error(err, "failed to publish staged results")
+			return errors.New("unable to publish translation job")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if publishedJob, err := s.jobRepo.GetSummaryByID(ctx, id); err == nil {
+		s.events.Publish(ctx, job.NewJobStateEvent(job.TranslationJobEventStatus, *publishedJob))
+	}
+
+	return &job.PublishResultDTO{JobID: id, Published: published}, nil
+}
+
+// DiscardStaging drops the staged results of a finished job without
+// publishing them. Retrying the job translates the entities again.
+func (s *TranslationJobService) DiscardStaging(ctx context.Context, id string) (*job.PublishResultDTO, error) {
+	if err := s.checkStagingAccess(ctx, id); err != nil {
+		return nil, err
+	}
+
+	discarded, err := s.jobRepo.ClearStagedResults(ctx, id, nil)
+	if err != nil {
+		logger.Error(err, "failed to discard staged results")
+		return nil, errors.New("unable to discard staged results")
+	}
+	if discarded == 0 {
+		return nil, errors.New("job has no staged results")
+	}
+
+	return &job.PublishResultDTO{JobID: id, Discarded: int(discarded)}, nil
+}
+
+// GetStagedResult returns the staged output of one subtask of a job
+func (s *TranslationJobService) GetStagedResult(ctx context.Context, jobID, subtaskID string) (*job.StagedResultDTO, error) {
+	j, err := s.jobRepo.GetSummaryByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("translation job not found")
+		}
+		logger.Error(err, "failed to get translation job")
+		return nil, errors.New("unable to retrieve translation job")
+	}
+
+	subtask, err := s.jobRepo.GetSubtaskByID(ctx, subtaskID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error(err, "failed to get translation subtask")
+		return nil, errors.New("unable to retrieve translation subtask")
+	}
+	if subtask == nil || subtask.JobID != jobID {
+		return nil, errors.New("translation subtask not found")
+	}
+	if !subtask.IsStaged() {
+		return nil, errors.New("subtask has no staged result")
+	}
+
+	result, err := loadStagedResult(subtask)
+	if err != nil {
+		logger.Error(err, "failed to read staged result")
+		return nil, errors.New("unable to read staged result")
+	}
+
+	return &job.StagedResultDTO{
+		SubtaskID:    subtask.ID,
+		EntityType:   subtask.EntityType,
+		EntityID:     subtask.EntityID,
+		TargetLang:   j.TargetLang,
+		StagedResult: *result,
+	}, nil
+}
+
+// checkStagingAccess allows publishing and discarding only once a job has
+// finished, while no worker writes to its staging area
+func (s *TranslationJobService) checkStagingAccess(ctx context.Context, id string) error {
+	j, err := s.jobRepo.GetSummaryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("translation job not found")
+		}
+		logger.Error(err, "failed to get translation job")
+		return errors.New("unable to retrieve translation job")
+	}
+
+	if !job.IsTerminalJobStatus(j.Status) {
+		return errors.New("job is still active")
+	}
+	return nil
+}
+
+// publishStagedResults promotes every staged result of a job into the
+// translation tables and clears it from staging. It must run in a single
+// transaction, so that readers see either all of the job's results or none;
+// the job row is locked first so concurrent publishes do not overlap. Results
+// of entities deleted in the meantime are dropped.
+func publishStagedResults(ctx context.Context, provider repository.RepositoryProvider, jobID string, publishedBy *string) (int, error) {
+	j, err := provider.TranslationJob().LockByID(ctx, jobID)
+	if err != nil {
+		return 0, fmt.Errorf("unable to lock translation job: %w", err)
+	}
+
+	subtasks, err := provider.TranslationJob().GetStagedSubtasks(ctx, jobID)
+	if err != nil {
+		return 0, fmt.Errorf("unable to load staged results: %w", err)
+	}
+	if len(subtasks) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, len(subtasks))
+	published := 0
+	for i := range subtasks {
+		ids[i] = subtasks[i].ID
+
+		result, err := loadStagedResult(&subtasks[i])
+		if err != nil {
+			return 0, fmt.Errorf("unable to read staged result of subtask %s: %w", subtasks[i].ID, err)
+		}
+
+		var found bool
+		switch subtasks[i].EntityType {
+		case job.EntityTypeChapter:
+			found, err = publishChapter(ctx, provider, j, subtasks[i].EntityID, result)
+		case job.EntityTypeVolume:
+			found, err = publishVolume(ctx, provider, j, subtasks[i].EntityID, result)
+		case job.EntityTypeNovel:
+			found, err = publishNovel(ctx, provider, j, subtasks[i].EntityID, result)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			published++
+		}
+	}
+
+	if _, err := provider.TranslationJob().ClearStagedResults(ctx, jobID, ids); err != nil {
+		return 0, fmt.Errorf("unable to clear staged results: %w", err)
+	}
+	if err := provider.TranslationJob().MarkPublished(ctx, jobID, publishedBy); err != nil {
+		return 0, fmt.Errorf("unable to mark translation job published: %w", err)
+	}
+
+	return published, nil
+}
+
+// loadStagedResult decodes a subtask's staged result from ResultText
+func loadStagedResult(subtask *job.TranslationSubtask) (*job.StagedResult, error) {
+	if subtask.ResultText == nil {
+		return nil, errors.New("subtask has no staged result")
+	}
+
+	var result job.StagedResult
+	if err := json.Unmarshal([]byte(*subtask.ResultText), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// publishChapter writes a machine translation, which has to go through review
+// before readers see it, recording the languages it was translated through.
+// An approved or human translation stays live and gets the output as a
+// pending revision instead. It reports false when the chapter is gone.
+func publishChapter(ctx context.Context, provider repository.RepositoryProvider, j *job.TranslationJob, chapterID string, result *job.StagedResult) (bool, error) {
+	found, err := saveMachineChapterTranslation(ctx, provider, chapterID, j.TargetLang, result, &j.FromLang, j.PivotLang, true)
+	if err != nil || !found || result.Pivot == nil || j.PivotLang == nil {
+		return found, err
+	}
+
+	// The pivot translation is only written if the chapter still has no
+	// approved one or one under review, so a translation made or checked in
+	// the meantime is kept. An unreviewed machine draft is replaced, as the
+	// result was made from the new pivot text rather than from it.
+	return saveMachineChapterTranslation(ctx, provider, chapterID, *j.PivotLang, result.Pivot, &j.FromLang, nil, false)
+}
+
+// saveMachineChapterTranslation creates a chapter's translation in lang from
+// machine output, or replaces unreviewed or rejected machine output. With
+// overwrite set, any other translation keeps its text and the output is stored
+// as its pending revision; without it, anything else is left alone.
+func saveMachineChapterTranslation(
+	ctx context.Context,
+	provider repository.RepositoryProvider,
+	chapterID, lang string,
+	result *job.StagedResult,
+	sourceLang, pivotLang *string,
+	overwrite bool,
+) (bool, error) {
+	existing, err := provider.Chapter().GetTranslation(ctx, chapterID, lang)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, fmt.Errorf("unable to load chapter translation: %w", err)
+	}
+
+	if existing != nil {
+		if !existing.IsMachineDraft() {
+			if !overwrite {
+				return true, nil
+			}
+			return true, saveChapterRevision(ctx, provider, existing, result, sourceLang, pivotLang)
+		}
+
+		existing.Title = result.Title
+		existing.Content = result.Content
+		existing.Status = chapter.TranslationStatusMachineDraft
+		existing.Origin = chapter.TranslationOriginMachine
+		existing.SourceLang = sourceLang
+		existing.PivotLang = pivotLang
+		existing.Outdated = false
+		existing.ReviewerID = nil
+		existing.ReviewedBy = nil
+		existing.ReviewedAt = nil
+		existing.ReviewNote = nil
+		_, err = provider.Chapter().UpdateTranslation(ctx, existing)
+	} else {
+		if _, err := provider.Chapter().GetByID(ctx, chapterID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, fmt.Errorf("unable to load chapter: %w", err)
+		}
+
+		_, err = provider.Chapter().CreateTranslation(ctx, &chapter.ChapterTranslation{
+			ChapterID:  chapterID,
+			Lang:       lang,
+			Title:      result.Title,
+			Content:    result.Content,
+			Status:     chapter.TranslationStatusMachineDraft,
+			Origin:     chapter.TranslationOriginMachine,
+			SourceLang: sourceLang,
+			PivotLang:  pivotLang,
+		})
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to save chapter translation: %w", err)
+	}
+
+	return true, nil
+}
+
+func saveChapterRevision(
+	ctx context.Context,
+	provider repository.RepositoryProvider,
+	ct *chapter.ChapterTranslation,
+	result *job.StagedResult,
+	sourceLang, pivotLang *string,
+) error {
+	err := provider.Chapter().SaveRevision(ctx, &chapter.ChapterTranslationRevision{
+		TranslationID: ct.ID,
+		ChapterID:     ct.ChapterID,
+		Lang:          ct.Lang,
+		Title:         result.Title,
+		Content:       result.Content,
+		SourceLang:    sourceLang,
+		PivotLang:     pivotLang,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to save chapter translation revision: %w", err)
+	}
+	return nil
+}
+
+// publishVolume writes the volume's translation, recording the languages it was
+// translated through. Earlier machine output is replaced, but a translation
+// written by hand, which records no source language, is kept as it is. It
+// reports false when the volume is gone.
+func publishVolume(ctx context.Context, provider repository.RepositoryProvider, j *job.TranslationJob, volumeID string, result *job.StagedResult) (bool, error) {
+	existing, err := provider.Volume().GetTranslation(ctx, volumeID, j.TargetLang)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, fmt.Errorf("unable to load volume translation: %w", err)
+	}
+
+	if existing != nil {
+		if existing.SourceLang == nil {
+			return true, nil
+		}
+
+		existing.Title = result.Title
+		existing.Description = result.Description
+		existing.SourceLang = &j.FromLang
+		existing.PivotLang = j.PivotLang
+		_, err = provider.Volume().UpdateTranslation(ctx, existing)
+	} else {
+		if _, err := provider.Volume().GetByID(ctx, volumeID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, fmt.Errorf("unable to load volume: %w", err)
+		}
+
+		_, err = provider.Volume().CreateTranslation(ctx, &volume.VolumeTranslation{
+			VolumeID:    volumeID,
+			Lang:        j.TargetLang,
+			Title:       result.Title,
+			Description: result.Description,
+			SourceLang:  &j.FromLang,
+			PivotLang:   j.PivotLang,
+		})
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to save volume translation: %w", err)
+	}
+
+	return true, nil
+}
+
+// publishNovel writes the novel's translation, recording the languages it was
+// translated through. Earlier machine output is replaced, but a translation
+// written by hand, which records no source language, is kept as it is. It
+// reports false when the novel is gone.
+func publishNovel(ctx context.Context, provider repository.RepositoryProvider, j *job.TranslationJob, novelID string, result *job.StagedResult) (bool, error) {
+	existing, err := provider.Novel().GetTranslation(ctx, novelID, j.TargetLang)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, fmt.Errorf("unable to load novel translation: %w", err)
+	}
+
+	if existing != nil {
+		if existing.SourceLang == nil {
+			return true, nil
+		}
+
+		existing.Title = result.Title
+		existing.Description = result.Description
+		existing.SourceLang = &j.FromLang
+		existing.PivotLang = j.PivotLang
+		_, err = provider.Novel().UpdateTranslation(ctx, existing)
+	} else {
+		if _, err := provider.Novel().GetByID(ctx, novelID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, fmt.Errorf("unable to load novel: %w", err)
+		}
+
+		_, err = provider.Novel().CreateTranslation(ctx, &novel.NovelTranslation{
+			NovelID:     novelID,
+			Lang:        j.TargetLang,
+			Title:       result.Title,
+			Description: result.Description,
+			SourceLang:  &j.FromLang,
+			PivotLang:   j.PivotLang,
+		})
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to save novel translation: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+	"simple-go/internal/repository"
+)
+
+// revisionChapterRepo holds a single chapter translation and records what
+// publishing does to it
+type revisionChapterRepo struct {
+	fakeChapterRepo
+	translation *chapter.ChapterTranslation
+	updated     bool
+	revision    *chapter.ChapterTranslationRevision
+}
+
+func (r *revisionChapterRepo) GetTranslation(ctx context.Context, chapterID, lang string) (*chapter.ChapterTranslation, error) {
+	copied := *r.translation
+	return &copied, nil
+}
+
+func (r *revisionChapterRepo) UpdateTranslation(ctx context.Context, ct *chapter.ChapterTranslation) (*chapter.ChapterTranslation, error) {
+	r.updated = true
+	r.translation = ct
+	return ct, nil
+}
+
+func (r *revisionChapterRepo) SaveRevision(ctx context.Context, rev *chapter.ChapterTranslationRevision) error {
+	r.revision = rev
+	return nil
+}
+
+type revisionProvider struct {
+	fakeProvider
+	chapters *revisionChapterRepo
+}
+
+func (p *revisionProvider) Chapter() repository.ChapterRepository { return p.chapters }
+
+func TestPublishChapterKeepsReviewedTranslations(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		origin       string
+		wantRevision bool
+	}{
+		{"machine draft", chapter.TranslationStatusMachineDraft, chapter.TranslationOriginMachine, false},
+		{"rejected machine output", chapter.TranslationStatusRejected, chapter.TranslationOriginMachine, false},
+		{"approved machine output", chapter.TranslationStatusApproved, chapter.TranslationOriginMachine, true},
+		{"machine output in review", chapter.TranslationStatusInReview, chapter.TranslationOriginMachine, true},
+		{"human translation", chapter.TranslationStatusApproved, chapter.TranslationOriginHuman, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviewer := "reviewer-1"
+			repo := &revisionChapterRepo{translation: &chapter.ChapterTranslation{
+				ID:         "translation-1",
+				ChapterID:  "chapter-1",
+				Lang:       "en",
+				Title:      "Old",
+				Content:    "<p>Old</p>",
+				Status:     tt.status,
+				Origin:     tt.origin,
+				ReviewedBy: &reviewer,
+			}}
+			provider := &revisionProvider{chapters: repo}
+
+			j := &job.TranslationJob{FromLang: "ja", TargetLang: "en"}
+			result := &job.StagedResult{Title: "New", Content: "<p>New</p>"}
+			if _, err := publishChapter(context.Background(), provider, j, "chapter-1", result); err != nil {
+				t.Fatalf("publishChapter: %v", err)
+			}
+
+			if tt.wantRevision {
+				if repo.updated {
+					t.Error("translation was overwritten")
+				}
+				if repo.revision == nil || repo.revision.TranslationID != "translation-1" || repo.revision.Content != result.Content {
+					t.Errorf("revision = %+v, want the new output for translation-1", repo.revision)
+				}
+				return
+			}
+
+			if repo.revision != nil {
+				t.Error("unexpected revision")
+			}
+			if repo.translation.Content != result.Content || repo.translation.Status != chapter.TranslationStatusMachineDraft || repo.translation.ReviewedBy != nil {
+				t.Errorf("translation = %+v, want a fresh machine draft", repo.translation)
+			}
+		})
+	}
+}
+
+// publishVolumeRepo holds a single volume translation and records whether
+// publishing overwrote it
+type publishVolumeRepo struct {
+	repository.VolumeRepository
+	translation *volume.VolumeTranslation
+	updated     bool
+}
+
+func (r *publishVolumeRepo) GetTranslation(ctx context.Context, volumeID, lang string) (*volume.VolumeTranslation, error) {
+	copied := *r.translation
+	return &copied, nil
+}
+
+func (r *publishVolumeRepo) UpdateTranslation(ctx context.Context, vt *volume.VolumeTranslation) (*volume.VolumeTranslation, error) {
+	r.updated = true
+	r.translation = vt
+	return vt, nil
+}
+
+// publishNovelRepo holds a single novel translation and records whether
+// publishing overwrote it
+type publishNovelRepo struct {
+	repository.NovelRepository
+	translation *novel.NovelTranslation
+	updated     bool
+}
+
+func (r *publishNovelRepo) GetTranslation(ctx context.Context, novelID, lang string) (*novel.NovelTranslation, error) {
+	copied := *r.translation
+	return &copied, nil
+}
+
+func (r *publishNovelRepo) UpdateTranslation(ctx context.Context, nt *novel.NovelTranslation) (*novel.NovelTranslation, error) {
+	r.updated = true
+	r.translation = nt
+	return nt, nil
+}
+
+type publishProvider struct {
+	fakeProvider
+	volumes *publishVolumeRepo
+	novels  *publishNovelRepo
+}
+
+func (p *publishProvider) Volume() repository.VolumeRepository { return p.volumes }
+func (p *publishProvider) Novel() repository.NovelRepository   { return p.novels }
+
+func TestPublishKeepsHumanVolumeAndNovelTranslations(t *testing.T) {
+	machineSource := "ja"
+	tests := []struct {
+		name          string
+		sourceLang    *string
+		wantOverwrite bool
+	}{
+		{"machine translation", &machineSource, true},
+		{"human translation", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldDescription := "Old description"
+			provider := &publishProvider{
+				volumes: &publishVolumeRepo{translation: &volume.VolumeTranslation{
+					ID: "volume-translation-1", VolumeID: "volume-1", Lang: "en", Title: "Old", Description: &oldDescription, SourceLang: tt.sourceLang,
+				}},
+				novels: &publishNovelRepo{translation: &novel.NovelTranslation{
+					ID: "novel-translation-1", NovelID: "novel-1", Lang: "en", Title: "Old", Description: &oldDescription, SourceLang: tt.sourceLang,
+				}},
+			}
+
+			pivot := "zh"
+			j := &job.TranslationJob{FromLang: "ko", TargetLang: "en", PivotLang: &pivot}
+			newDescription := "New description"
+			result := &job.StagedResult{Title: "New", Description: &newDescription}
+
+			if found, err := publishVolume(context.Background(), provider, j, "volume-1", result); err != nil || !found {
+				t.Fatalf("publishVolume = %v, %v", found, err)
+			}
+			if found, err := publishNovel(context.Background(), provider, j, "novel-1", result); err != nil || !found {
+				t.Fatalf("publishNovel = %v, %v", found, err)
+			}
+
+			vt, nt := provider.volumes.translation, provider.novels.translation
+			if provider.volumes.updated != tt.wantOverwrite || provider.novels.updated != tt.wantOverwrite {
+				t.Fatalf("volume updated = %v, novel updated = %v, want %v", provider.volumes.updated, provider.novels.updated, tt.wantOverwrite)
+			}
+			if !tt.wantOverwrite {
+				if vt.Title != "Old" || *vt.Description != oldDescription || nt.Title != "Old" || *nt.Description != oldDescription {
+					t.Errorf("volume = %+v, novel = %+v, want the human text kept", vt, nt)
+				}
+				return
+			}
+
+			if vt.Title != "New" || *vt.Description != newDescription || *vt.SourceLang != "ko" || *vt.PivotLang != "zh" {
+				t.Errorf("volume = %+v, want the new output translated from ko through zh", vt)
+			}
+			if nt.Title != "New" || *nt.Description != newDescription || *nt.SourceLang != "ko" || *nt.PivotLang != "zh" {
+				t.Errorf("novel = %+v, want the new output translated from ko through zh", nt)
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"simple-go/internal/domain/chapter"
 	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/novel"
 	"simple-go/internal/domain/volume"
 	"simple-go/internal/repository"
 	"simple-go/pkg/logger"
@@ -15,6 +17,16 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrActiveJobExists is returned when a novel already has an active job
+	// for the target language
+	ErrActiveJobExists = errors.New("active translation job already exists for this novel and language")
+	// ErrUnpublishedResults is returned when the latest job for a novel and
+	// target language has staged results that were neither published nor
+	// discarded, which a new job would otherwise bury
+	ErrUnpublishedResults = errors.New("latest translation job has unpublished staged results; publish or discard them first")
+)
+
 type TranslationJobService struct {
 	uow         repository.UnitOfWork
 	jobRepo     repository.TranslationJobRepository
@@ -22,6 +34,9 @@ type TranslationJobService struct {
 	volumeRepo  repository.VolumeRepository
 	chapterRepo repository.ChapterRepository
 	redisQueue  *queue.RedisQueue
+	events      *TranslationEventService
+	memory      *TranslationMemoryService
+	quota       *QuotaService
 }
 
 func NewTranslationJobService(
@@ -31,6 +46,9 @@ func NewTranslationJobService(
 	volumeRepo repository.VolumeRepository,
 	chapterRepo repository.ChapterRepository,
 	redisQueue *queue.RedisQueue,
+	events *TranslationEventService,
+	memory *TranslationMemoryService,
+	quota *QuotaService,
 ) *TranslationJobService {
 	return &TranslationJobService{
 		uow:         uow,
@@ -39,10 +57,18 @@ func NewTranslationJobService(
 		volumeRepo:  volumeRepo,
 		chapterRepo: chapterRepo,
 		redisQueue:  redisQueue,
+		events:      events,
+		memory:      memory,
+		quota:       quota,
 	}
 }
 
 // CreateTranslationJob creates a new translation job for a novel with all subtasks
+// in its scope. In incremental mode only entities whose target translation is
+// missing or stale are included. Earlier jobs for the same novel and language
+// are kept as they are, for their history. The job's billable characters
+// are charged to the user; a *quota.ExceededError is returned when the job
+// would go over a quota.
 func (s *TranslationJobService) CreateTranslationJob(
 	ctx context.Context,
 	userID string,
@@ -51,133 +77,216 @@ func (s *TranslationJobService) CreateTranslationJob(
 	var createdJob *job.TranslationJob
 
 	err := s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
-		novel, err := provider.Novel().GetByID(ctx, dto.NovelID)
+		// 1. Get the novel and its volumes with chapters
+		n, volumes, err := loadNovelSource(ctx, provider, dto.NovelID)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("novel not found")
-			}
-			logger.Error(err, "failed to get novel")
-			return errors.New("unable to verify novel")
-		}
-
-		existingJob, err := provider.TranslationJob().GetByNovelAndLang(ctx, dto.NovelID, dto.TargetLang)
-		if err == nil && existingJob != nil {
-			// Check if it's an active job
-			if existingJob.Status == job.TranslationJobStatusPending || existingJob.Status == job.TranslationJobStatusInProgress {
-				return fmt.Errorf("active translation job already exists for this novel and language (job_id: %s)", existingJob.ID)
-			}
+			return err
 		}
 
-		// 3. Get all volumes with chapters for the novel
-		volumes, err := provider.Volume().GetAllWithChaptersByNovelID(ctx, dto.NovelID)
+		// 2. Plan and measure the subtasks
+		planned, err := s.planJob(ctx, provider, n, volumes, dto, nil)
 		if err != nil {
-			logger.Error(err, "failed to get volumes")
-			return errors.New("unable to get volumes for novel")
+			return err
 		}
 
-		// 4. Collect all chapters from volumes
-		chapters := make([]chapter.Chapter, 0)
-		for _, vol := range volumes {
-			chapters = append(chapters, vol.Chapters...)
+		// 3. Check the billable characters against the user's quotas
+		if err := s.quota.Check(ctx, provider, userID, 1, planned.characters); err != nil {
+			return err
 		}
 
-		if len(chapters) == 0 {
-			return errors.New("novel has no chapters to translate")
-		}
+		// 4. Create the job with its subtasks
+		createdJob, err = s.createPlannedJob(ctx, provider, userID, n, planned, nil)
+		return err
+	})
 
-		// 5. Create the parent job
-		newJob := &job.TranslationJob{
-			NovelID:    dto.NovelID,
-			FromLang:   novel.OriginalLanguage,
-			TargetLang: dto.TargetLang,
-			Status:     job.TranslationJobStatusPending,
-			CreatedBy:  &userID,
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		createdJob, err = provider.TranslationJob().Create(ctx, newJob)
-		if err != nil {
-			logger.Error(err, "failed to create translation job")
-			return errors.New("unable to create translation job")
-		}
+	// 5. Push job to Redis queue (after successful DB transaction)
+	s.publishJob(ctx, createdJob)
+	s.events.Publish(ctx, job.NewJobStateEvent(job.TranslationJobEventCreated, *createdJob))
 
-		// 6. Create subtasks for chapters, volumes, and novel
-		subtasks := make([]job.TranslationSubtask, 0)
-
-		// Chapter subtasks (priority 200)
-		for i, ch := range chapters {
-			subtasks = append(subtasks, job.TranslationSubtask{
-				JobID:          createdJob.ID,
-				EntityType:     job.EntityTypeChapter,
-				EntityID:       ch.ID,
-				ParentVolumeID: &ch.VolumeID,
-				Seq:            i + 1,
-				Priority:       job.PriorityChapter,
-				Status:         job.TranslationSubtaskStatusPending,
-			})
-		}
+	response := job.MapTranslationJobToDTO(*createdJob)
+	return &response, nil
+}
 
-		// Volume subtasks (priority 150)
-		for i, vol := range volumes {
-			subtasks = append(subtasks, job.TranslationSubtask{
-				JobID:      createdJob.ID,
-				EntityType: job.EntityTypeVolume,
-				EntityID:   vol.ID,
-				Seq:        i + 1,
-				Priority:   job.PriorityVolume,
-				Status:     job.TranslationSubtaskStatusPending,
-			})
+// loadNovelSource loads a novel with its volumes and chapters, which are the
+// source of every job planned for it
+func loadNovelSource(ctx context.Context, provider repository.RepositoryProvider, novelID string) (*novel.Novel, []volume.Volume, error) {
+	n, err := provider.Novel().GetByID(ctx, novelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("novel not found")
 		}
+		logger.Error(err, "failed to get novel")
+		return nil, nil, errors.New("unable to verify novel")
+	}
 
-		// Novel subtask (priority 100 - highest, processed last)
-		subtasks = append(subtasks, job.TranslationSubtask{
-			JobID:      createdJob.ID,
-			EntityType: job.EntityTypeNovel,
-			EntityID:   dto.NovelID,
-			Seq:        1,
-			Priority:   job.PriorityNovel,
-			Status:     job.TranslationSubtaskStatusPending,
-		})
-
-		// 7. Batch insert subtasks
-		if err := provider.TranslationJob().CreateSubtasksBatch(ctx, subtasks); err != nil {
-			logger.Error(err, "failed to create subtasks")
-			return errors.New("unable to create translation subtasks")
-		}
+	volumes, err := provider.Volume().GetAllWithChaptersByNovelID(ctx, novelID)
+	if err != nil {
+		logger.Error(err, "failed to get volumes")
+		return nil, nil, errors.New("unable to get volumes for novel")
+	}
+
+	return n, volumes, nil
+}
+
+// plannedJob is a job planned for one target language, ready to be created
+type plannedJob struct {
+	dto        job.CreateTranslationJobDTO
+	subtasks   []job.TranslationSubtask
+	characters int
+	estimate   *job.TranslationJobEstimateDTO
+}
+
+// planJob checks that the novel has no active job for the target language,
+// then plans the subtasks within the requested scope and mode and measures
+// their billable characters. Callers planning several languages of one novel
+// share segments between them.
+func (s *TranslationJobService) planJob(
+	ctx context.Context,
+	provider repository.RepositoryProvider,
+	n *novel.Novel,
+	volumes []volume.Volume,
+	dto job.CreateTranslationJobDTO,
+	segments segmentCache,
+) (*plannedJob, error) {
+	if dto.Mode == "" {
+		dto.Mode = job.TranslationJobModeFull
+	}
+	if dto.PublishMode == "" {
+		dto.PublishMode = job.TranslationJobPublishOnComplete
+	}
+
+	if err := validatePivotLang(n, dto); err != nil {
+		return nil, err
+	}
+
+	if err := checkNoConflictingJob(ctx, provider, dto.NovelID, dto.TargetLang, ""); err != nil {
+		return nil, err
+	}
 
-		// 8. Update job with total subtasks count
-		createdJob.TotalSubtasks = len(subtasks)
-		if _, err := provider.TranslationJob().Update(ctx, createdJob); err != nil {
-			logger.Error(err, "failed to update job with total subtasks")
-			return errors.New("unable to update job")
+	subtasks, err := planTranslationSubtasks(n, volumes, dto.TargetLang, dto.TranslationJobScope)
+	if err != nil {
+		return nil, err
+	}
+	if len(subtasks) == 0 {
+		return nil, errors.New("nothing to translate: all entities in scope are up to date")
+	}
+
+	estimate, err := s.estimate(ctx, n, volumes, subtasks, dto, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plannedJob{
+		dto:        dto,
+		subtasks:   subtasks,
+		characters: estimate.Totals.BillableCharacters,
+		estimate:   estimate,
+	}, nil
+}
+
+// checkNoConflictingJob rejects a job for a novel and language whose latest
+// job, unless it is the job with ID exceptID, is still active or holds
+// staged results nobody published or discarded yet. Only the latest job can
+// be active, since no job is created or retried while one is.
+func checkNoConflictingJob(ctx context.Context, provider repository.RepositoryProvider, novelID, targetLang, exceptID string) error {
+	latest, err := provider.TranslationJob().GetByNovelAndLang(ctx, novelID, targetLang)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
 		}
+		logger.Error(err, "failed to get existing translation job")
+		return errors.New("unable to check existing translation jobs")
+	}
 
+	if latest.ID == exceptID {
 		return nil
-	})
+	}
+	if !job.IsTerminalJobStatus(latest.Status) {
+		return fmt.Errorf("%w (job_id: %s)", ErrActiveJobExists, latest.ID)
+	}
+
+	staged, err := provider.TranslationJob().CountStagedSubtasks(ctx, latest.ID)
+	if err != nil {
+		logger.Error(err, "failed to count staged subtasks")
+		return errors.New("unable to check existing translation jobs")
+	}
+	if staged > 0 {
+		return fmt.Errorf("%w (job_id: %s)", ErrUnpublishedResults, latest.ID)
+	}
+	return nil
+}
+
+// validatePivotLang rejects a pivot language that would not be an
+// intermediate step
+func validatePivotLang(n *novel.Novel, dto job.CreateTranslationJobDTO) error {
+	if dto.PivotLang == "" {
+		return nil
+	}
+	if dto.PivotLang == n.OriginalLanguage || dto.PivotLang == dto.TargetLang {
+		return errors.New("pivot language must differ from the source and target languages")
+	}
+	return nil
+}
 
+// createPlannedJob creates the parent job, inserts its subtasks and charges it
+// to the user
+func (s *TranslationJobService) createPlannedJob(
+	ctx context.Context,
+	provider repository.RepositoryProvider,
+	userID string,
+	n *novel.Novel,
+	planned *plannedJob,
+	batchID *string,
+) (*job.TranslationJob, error) {
+	dto := planned.dto
+	newJob := &job.TranslationJob{
+		NovelID:     dto.NovelID,
+		BatchID:     batchID,
+		FromLang:    n.OriginalLanguage,
+		TargetLang:  dto.TargetLang,
+		PivotLang:   optionalStringPtr(dto.PivotLang),
+		Status:      job.TranslationJobStatusPending,
+		Mode:        dto.Mode,
+		Priority:    dto.Priority,
+		PublishMode: dto.PublishMode,
+		ChapterFrom: dto.ChapterFrom,
+		ChapterTo:   dto.ChapterTo,
+		VolumeIDs:   dto.VolumeIDs,
+		CreatedBy:   &userID,
+	}
+
+	createdJob, err := provider.TranslationJob().Create(ctx, newJob)
 	if err != nil {
+		logger.Error(err, "failed to create translation job")
+		return nil, errors.New("unable to create translation job")
+	}
+
+	if err := s.quota.Charge(ctx, provider, userID, createdJob.ID, planned.characters); err != nil {
 		return nil, err
 	}
 
-	// 9. Push job to Redis queue (after successful DB transaction)
-	if s.redisQueue != nil {
-		queueMsg := queue.TranslationJobMessage{
-			JobID:        createdJob.ID,
-			TargetLang:   createdJob.TargetLang,
-			SourceLang:   createdJob.FromLang,
-			TargetFields: []string{"title", "description", "content"},
-		}
+	for i := range planned.subtasks {
+		planned.subtasks[i].JobID = createdJob.ID
+	}
 
-		if err := s.redisQueue.Publish(ctx, queueMsg); err != nil {
-			logger.Error(err, "failed to push job to Redis queue")
-			// Don't fail the request if queue push fails - job is already created
-			// Worker can poll from DB if needed
-		} else {
-			logger.Info(fmt.Sprintf("Successfully pushed job %s to Redis queue", createdJob.ID))
-		}
+	// Batch insert subtasks
+	if err := provider.TranslationJob().CreateSubtasksBatch(ctx, planned.subtasks); err != nil {
+		logger.Error(err, "failed to create subtasks")
+		return nil, errors.New("unable to create translation subtasks")
 	}
 
-	response := job.MapTranslationJobToDTO(*createdJob)
-	return &response, nil
+	// Update job with total subtasks count
+	createdJob.TotalSubtasks = len(planned.subtasks)
+	if _, err := provider.TranslationJob().Update(ctx, createdJob); err != nil {
+		logger.Error(err, "failed to update job with total subtasks")
+		return nil, errors.New("unable to update job")
+	}
+
+	return createdJob, nil
 }
 
 // GetJobByID retrieves a translation job by ID with all subtasks
@@ -195,6 +304,48 @@ func (s *TranslationJobService) GetJobByID(ctx context.Context, id string) (*job
 	return &response, nil
 }
 
+// GetQAReport returns the QA findings of a job's subtasks
+func (s *TranslationJobService) GetQAReport(ctx context.Context, id string, query job.QAReportQueryDTO) (*job.TranslationJobQAReportDTO, error) {
+	j, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("translation job not found")
+		}
+		logger.Error(err, "failed to get translation job")
+		return nil, errors.New("unable to retrieve translation job")
+	}
+
+	report := job.MapQAReportToDTO(*j, query.Severity, query.Rule)
+	return &report, nil
+}
+
+// GetJobSnapshot returns the current job state as a snapshot event, without
+// loading the subtasks
+func (s *TranslationJobService) GetJobSnapshot(ctx context.Context, id string) (*job.TranslationJobEventDTO, error) {
+	j, err := s.jobRepo.GetSummaryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("translation job not found")
+		}
+		logger.Error(err, "failed to get translation job")
+		return nil, errors.New("unable to retrieve translation job")
+	}
+
+	event := job.NewJobStateEvent(job.TranslationJobEventSnapshot, *j)
+	return &event, nil
+}
+
+// SubscribeJobEvents streams live events of a job. The channel is nil when
+// live events are unavailable and callers have to poll GetJobSnapshot.
+func (s *TranslationJobService) SubscribeJobEvents(ctx context.Context, id string) (<-chan job.TranslationJobEventDTO, error) {
+	events, err := s.events.Subscribe(ctx, id)
+	if err != nil {
+		logger.Error(err, "failed to subscribe to translation job events")
+		return nil, errors.New("unable to subscribe to translation job events")
+	}
+	return events, nil
+}
+
 func (s *TranslationJobService) GetAllJobs(ctx context.Context, limit, offset int, status string) ([]job.TranslationJobResponseDTO, int64, error) {
 	jobs, err := s.jobRepo.GetAll(ctx, limit, offset, status)
 	if err != nil {
@@ -237,8 +388,9 @@ func (s *TranslationJobService) GetJobsByNovelID(ctx context.Context, novelID st
 	return response, nil
 }
 
-// CancelJob cancels a pending or in-progress translation job
-func (s *TranslationJobService) CancelJob(ctx context.Context, id string) error {
+// CancelJob cancels a pending or in-progress translation job together with its
+// unfinished subtasks, drops it from the queue and signals running workers
+func (s *TranslationJobService) CancelJob(ctx context.Context, id, userID string) error {
 	j, err := s.jobRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -248,22 +400,237 @@ func (s *TranslationJobService) CancelJob(ctx context.Context, id string) error
 		return errors.New("unable to retrieve translation job")
 	}
 
-	if j.Status == job.TranslationJobStatusCompleted {
+	switch j.Status {
+	case job.TranslationJobStatusCompleted:
 		return errors.New("cannot cancel a completed job")
+	case job.TranslationJobStatusFailed:
+		return errors.New("job already failed")
+	case job.TranslationJobStatusCancelled:
+		return errors.New("job already cancelled")
 	}
 
-	if j.Status == job.TranslationJobStatusFailed {
-		return errors.New("job already failed")
+	err = s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		cancelled, err := provider.TranslationJob().MarkCancelled(ctx, id, userID)
+		if err != nil {
+			logger.Error(err, "failed to cancel translation job")
+			return errors.New("unable to cancel translation job")
+		}
+		if !cancelled {
+			return errors.New("job is no longer active")
+		}
+
+		if _, err := provider.TranslationJob().CancelActiveSubtasks(ctx, id); err != nil {
+			logger.Error(err, "failed to cancel translation subtasks")
+			return errors.New("unable to cancel translation subtasks")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if err := s.jobRepo.UpdateStatus(ctx, id, job.TranslationJobStatusFailed); err != nil {
-		logger.Error(err, "failed to cancel translation job")
-		return errors.New("unable to cancel translation job")
+	if cancelledJob, err := s.jobRepo.GetSummaryByID(ctx, id); err == nil {
+		s.events.Publish(ctx, job.NewJobStateEvent(job.TranslationJobEventStatus, *cancelledJob))
+	}
+
+	// The database is the source of truth; the queue cleanup and the signal
+	// only save workers from picking up or finishing cancelled work
+	if s.redisQueue != nil {
+		if _, err := s.redisQueue.RemoveJob(ctx, id); err != nil {
+			logger.Error(err, "failed to remove cancelled job from Redis queue")
+		}
+		if err := s.redisQueue.PublishCancellation(ctx, id); err != nil {
+			logger.Error(err, "failed to publish job cancellation")
+		}
 	}
 
 	return nil
 }
 
+// SetPriority changes the priority of a job. A job waiting in the queue is
+// moved to its new place; one being worked on gets it at its next turn.
+func (s *TranslationJobService) SetPriority(ctx context.Context, id string, priority int) (*job.TranslationJobResponseDTO, error) {
+	j, err := s.jobRepo.GetSummaryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("translation job not found")
+		}
+		logger.Error(err, "failed to get translation job")
+		return nil, errors.New("unable to retrieve translation job")
+	}
+
+	if err := s.jobRepo.UpdatePriority(ctx, id, priority); err != nil {
+		logger.Error(err, "failed to update translation job priority")
+		return nil, errors.New("unable to update translation job priority")
+	}
+	j.Priority = priority
+
+	if s.redisQueue != nil && !job.IsTerminalJobStatus(j.Status) {
+		removed, err := s.redisQueue.RemoveJob(ctx, id)
+		if err != nil {
+			logger.Error(err, "failed to remove job from Redis queue")
+		}
+		if removed > 0 {
+			s.publishJob(ctx, j)
+		}
+	}
+
+	response := job.MapTranslationJobToDTO(*j)
+	return &response, nil
+}
+
+// RetryJob resets the failed and dead-lettered subtasks of a finished job (and
+// optionally the done ones) to PENDING and queues it again. Cancelled jobs stay
+// cancelled. The retried subtasks are charged to the user like a new job; a
+// *quota.ExceededError is returned when they would go over a quota.
+func (s *TranslationJobService) RetryJob(ctx context.Context, id, userID string, dto job.RetryTranslationJobDTO) (*job.TranslationJobResponseDTO, error) {
+	statuses := []string{
+		job.TranslationSubtaskStatusFailed,
+		job.TranslationSubtaskStatusDeadLetter,
+	}
+	if dto.IncludeDone {
+		statuses = append(statuses, job.TranslationSubtaskStatusDone)
+	}
+
+	err := s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		// The lock keeps a concurrent retry or cancellation from changing the
+		// job between the status check and the reset
+		j, err := provider.TranslationJob().LockByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("translation job not found")
+			}
+			logger.Error(err, "failed to lock translation job")
+			return errors.New("unable to retrieve translation job")
+		}
+
+		switch j.Status {
+		case job.TranslationJobStatusPending, job.TranslationJobStatusInProgress:
+			return errors.New("job is still active")
+		case job.TranslationJobStatusCancelled:
+			return errors.New("cannot retry a cancelled job")
+		}
+		if err := checkNoConflictingJob(ctx, provider, j.NovelID, j.TargetLang, j.ID); err != nil {
+			return err
+		}
+
+		characters, err := s.measureRetry(ctx, provider, j, statuses)
+		if err != nil {
+			return err
+		}
+		if err := s.quota.Check(ctx, provider, userID, 1, characters); err != nil {
+			return err
+		}
+
+		reset, err := provider.TranslationJob().ResetSubtasks(ctx, id, statuses)
+		if err != nil {
+			logger.Error(err, "failed to reset translation subtasks")
+			return errors.New("unable to reset translation subtasks")
+		}
+		if reset == 0 {
+			return errors.New("job has no subtasks to retry")
+		}
+
+		total, err := provider.TranslationJob().CountSubtasks(ctx, id)
+		if err != nil {
+			logger.Error(err, "failed to count translation subtasks")
+			return errors.New("unable to count translation subtasks")
+		}
+		completed, err := provider.TranslationJob().CountSubtasksByStatus(ctx, id, job.TranslationSubtaskStatusDone)
+		if err != nil {
+			logger.Error(err, "failed to count completed translation subtasks")
+			return errors.New("unable to count translation subtasks")
+		}
+
+		if err := provider.TranslationJob().ResetForRetry(ctx, id, int(total), int(completed)); err != nil {
+			logger.Error(err, "failed to reset translation job")
+			return errors.New("unable to reset translation job")
+		}
+
+		return s.quota.Charge(ctx, provider, userID, id, characters)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.Error(err, "failed to reload translation job")
+		return nil, errors.New("unable to retrieve translation job")
+	}
+
+	s.publishJob(ctx, updated)
+	s.events.Publish(ctx, job.NewJobStateEvent(job.TranslationJobEventStatus, *updated))
+
+	response := job.MapTranslationJobToDTO(*updated)
+	return &response, nil
+}
+
+// measureRetry returns the billable characters of the job's subtasks in the
+// given statuses, measured like those of a new job
+func (s *TranslationJobService) measureRetry(ctx context.Context, provider repository.RepositoryProvider, j *job.TranslationJob, statuses []string) (int, error) {
+	subtasks, err := provider.TranslationJob().GetSubtasksByJobID(ctx, j.ID)
+	if err != nil {
+		logger.Error(err, "failed to get translation subtasks")
+		return 0, errors.New("unable to retrieve translation subtasks")
+	}
+
+	retried := make([]job.TranslationSubtask, 0, len(subtasks))
+	for _, st := range subtasks {
+		if slices.Contains(statuses, st.Status) {
+			retried = append(retried, st)
+		}
+	}
+	if len(retried) == 0 {
+		return 0, nil
+	}
+
+	n, volumes, err := loadNovelSource(ctx, provider, j.NovelID)
+	if err != nil {
+		return 0, err
+	}
+
+	dto := job.CreateTranslationJobDTO{NovelID: j.NovelID, TargetLang: j.TargetLang}
+	dto.Mode = j.Mode
+	if j.PivotLang != nil {
+		dto.PivotLang = *j.PivotLang
+	}
+	estimate, err := s.estimate(ctx, n, volumes, retried, dto, nil)
+	if err != nil {
+		return 0, err
+	}
+	return estimate.Totals.BillableCharacters, nil
+}
+
+// publishJob pushes the job to the Redis queue. A failed push is only logged
+// since the job is already persisted; idle workers still claim its subtasks.
+func (s *TranslationJobService) publishJob(ctx context.Context, j *job.TranslationJob) {
+	if s.redisQueue == nil {
+		return
+	}
+
+	if err := s.redisQueue.Publish(ctx, newJobMessage(j)); err != nil {
+		logger.Error(err, "failed to push job to Redis queue")
+		return
+	}
+
+	logger.Info(fmt.Sprintf("Successfully pushed job %s to Redis queue", j.ID))
+}
+
+// newJobMessage builds the queue message of a job. The worker uses it as well
+// to queue the job for its next turn.
+func newJobMessage(j *job.TranslationJob) queue.TranslationJobMessage {
+	return queue.TranslationJobMessage{
+		JobID:        j.ID,
+		TargetLang:   j.TargetLang,
+		SourceLang:   j.FromLang,
+		TargetFields: []string{"title", "description", "content"},
+		Priority:     j.Priority,
+		Owner:        j.FairShareOwner(),
+	}
+}
+
 // Helper method to get chapters by novel ID (if not already available in repository)
 func (s *TranslationJobService) getChaptersByNovelID(ctx context.Context, novelID string) ([]chapter.Chapter, error) {
 	volumes, err := s.volumeRepo.GetAllWithChaptersByNovelID(ctx, novelID)
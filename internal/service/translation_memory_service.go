@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"simple-go/internal/domain/chapter"
+	translationmemory "simple-go/internal/domain/translation_memory"
+	"simple-go/internal/repository"
+	"simple-go/pkg/epub"
+	"simple-go/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// TranslationMemoryService stores accepted translations segment by segment and
+// serves them back to the translation pipeline
+type TranslationMemoryService struct {
+	memoryRepo  repository.TranslationMemoryRepository
+	chapterRepo repository.ChapterRepository
+	volumeRepo  repository.VolumeRepository
+	// fuzzyMinSimilarity is the lowest similarity (0-1) reported as a fuzzy match;
+	// zero disables fuzzy lookups
+	fuzzyMinSimilarity float64
+}
+
+func NewTranslationMemoryService(
+	memoryRepo repository.TranslationMemoryRepository,
+	chapterRepo repository.ChapterRepository,
+	volumeRepo repository.VolumeRepository,
+	fuzzyMinSimilarity float64,
+) *TranslationMemoryService {
+	return &TranslationMemoryService{
+		memoryRepo:         memoryRepo,
+		chapterRepo:        chapterRepo,
+		volumeRepo:         volumeRepo,
+		fuzzyMinSimilarity: fuzzyMinSimilarity,
+	}
+}
+
+// MemoryMatches holds the best match per looked up segment, by segment index
+type MemoryMatches struct {
+	Exact map[int]translationmemory.TranslationMemoryEntry
+	Fuzzy map[int]translationmemory.FuzzyMatch
+}
+
+// Lookup returns the exact match for text, if any, followed by fuzzy matches
+func (s *TranslationMemoryService) Lookup(ctx context.Context, dto translationmemory.LookupDTO) ([]translationmemory.MatchDTO, error) {
+	limit := dto.Limit
+	if limit == 0 {
+		limit = 5
+	}
+
+	normalized := translationmemory.Normalize(dto.Text)
+	results := make([]translationmemory.MatchDTO, 0, limit)
+
+	exact, err := s.memoryRepo.FindExact(ctx, dto.SourceLang, dto.TargetLang, []string{translationmemory.Hash(normalized)})
+	if err != nil {
+		logger.Error(err, "failed to look up translation memory")
+		return nil, errors.New("unable to look up translation memory")
+	}
+	for _, e := range exact {
+		results = append(results, translationmemory.MapEntryToMatchDTO(e))
+	}
+
+	if s.fuzzyMinSimilarity > 0 && len(results) < limit {
+		fuzzy, err := s.memoryRepo.FindFuzzy(ctx, dto.SourceLang, dto.TargetLang, normalized, s.fuzzyMinSimilarity, limit)
+		if err != nil {
+			logger.Error(err, "failed to look up fuzzy translation memory matches")
+			return nil, errors.New("unable to look up translation memory")
+		}
+		for _, m := range translationmemory.MapFuzzyMatchesToDTOs(fuzzy) {
+			if len(results) == limit {
+				break
+			}
+			if len(exact) > 0 && m.ID == exact[0].ID {
+				continue
+			}
+			results = append(results, m)
+		}
+	}
+
+	return results, nil
+}
+
+// Match looks up segments for the translation pipeline. Segments without an
+// exact match are looked up fuzzily when enabled, all of them in one query.
+func (s *TranslationMemoryService) Match(ctx context.Context, sourceLang, targetLang string, segments []string) (*MemoryMatches, error) {
+	matches := &MemoryMatches{
+		Exact: make(map[int]translationmemory.TranslationMemoryEntry),
+		Fuzzy: make(map[int]translationmemory.FuzzyMatch),
+	}
+	if len(segments) == 0 {
+		return matches, nil
+	}
+
+	exact, err := s.ExactMatches(ctx, sourceLang, targetLang, segments)
+	if err != nil {
+		return nil, err
+	}
+	matches.Exact = exact
+
+	if s.fuzzyMinSimilarity <= 0 {
+		return matches, nil
+	}
+
+	// texts holds the segments without an exact match; indexes maps them back
+	texts := make([]string, 0, len(segments)-len(exact))
+	indexes := make([]int, 0, len(segments)-len(exact))
+	for i, seg := range segments {
+		if _, ok := exact[i]; ok {
+			continue
+		}
+		texts = append(texts, translationmemory.Normalize(seg))
+		indexes = append(indexes, i)
+	}
+	if len(texts) == 0 {
+		return matches, nil
+	}
+
+	fuzzy, err := s.memoryRepo.FindBestFuzzy(ctx, sourceLang, targetLang, texts, s.fuzzyMinSimilarity)
+	if err != nil {
+		return nil, err
+	}
+	for j, m := range fuzzy {
+		matches.Fuzzy[indexes[j]] = m
+	}
+
+	return matches, nil
+}
+
+// ExactMatches returns the stored translations of segments, keyed by the
+// segment's index. It does a single query, unlike fuzzy lookups.
+func (s *TranslationMemoryService) ExactMatches(ctx context.Context, sourceLang, targetLang string, segments []string) (map[int]translationmemory.TranslationMemoryEntry, error) {
+	exact := make(map[int]translationmemory.TranslationMemoryEntry)
+	if len(segments) == 0 {
+		return exact, nil
+	}
+
+	hashes := make([]string, len(segments))
+	for i, seg := range segments {
+		hashes[i] = translationmemory.Hash(translationmemory.Normalize(seg))
+	}
+
+	entries, err := s.memoryRepo.FindExact(ctx, sourceLang, targetLang, hashes)
+	if err != nil {
+		return nil, err
+	}
+	byHash := make(map[string]translationmemory.TranslationMemoryEntry, len(entries))
+	for _, e := range entries {
+		byHash[e.SourceHash] = e
+	}
+
+	for i := range segments {
+		if e, ok := byHash[hashes[i]]; ok {
+			exact[i] = e
+		}
+	}
+	return exact, nil
+}
+
+// MarkUsed bumps the usage counters of reused entries. Failures are only logged.
+func (s *TranslationMemoryService) MarkUsed(ctx context.Context, entries map[int]translationmemory.TranslationMemoryEntry) {
+	seen := make(map[string]bool, len(entries))
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !seen[e.ID] {
+			seen[e.ID] = true
+			ids = append(ids, e.ID)
+		}
+	}
+
+	if err := s.memoryRepo.MarkUsed(ctx, ids); err != nil {
+		logger.Error(err, "failed to record translation memory usage")
+	}
+}
+
+// LearnChapter stores the segments of a chapter's targetLang translation,
+// aligned with its translation in the volume's original language. It reports
+// false when the two cannot be aligned segment by segment.
+func (s *TranslationMemoryService) LearnChapter(ctx context.Context, chapterID, targetLang string) (bool, error) {
+	c, err := s.chapterRepo.GetByID(ctx, chapterID)
+	if err != nil {
+		return false, err
+	}
+	v, err := s.volumeRepo.GetByID(ctx, c.VolumeID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := s.learn(ctx, v.NovelID, *c, v.OriginalLanguage, targetLang)
+	return n > 0, err
+}
+
+// ImportNovel learns every chapter of a novel that has both a source and an
+// approved target translation
+func (s *TranslationMemoryService) ImportNovel(ctx context.Context, dto translationmemory.ImportDTO) (*translationmemory.ImportResultDTO, error) {
+	volumes, err := s.volumeRepo.GetAllWithChaptersByNovelID(ctx, dto.NovelID)
+	if err != nil {
+		logger.Error(err, "failed to load novel chapters")
+		return nil, errors.New("unable to import translation memory")
+	}
+	if len(volumes) == 0 {
+		return nil, errors.New("novel not found or has no volumes")
+	}
+
+	result := &translationmemory.ImportResultDTO{}
+	for _, v := range volumes {
+		sourceLang := v.OriginalLanguage
+		if dto.SourceLang != nil {
+			sourceLang = *dto.SourceLang
+		}
+		if sourceLang == dto.TargetLang {
+			return nil, errors.New("source_lang and target_lang must differ")
+		}
+
+		for _, c := range v.Chapters {
+			if !hasChapterTranslation(c, sourceLang) || !hasChapterTranslation(c, dto.TargetLang) {
+				continue
+			}
+
+			n, err := s.learn(ctx, v.NovelID, c, sourceLang, dto.TargetLang)
+			if err != nil {
+				logger.Error(err, "failed to import translation memory")
+				return nil, errors.New("unable to import translation memory")
+			}
+			if n == 0 {
+				result.Skipped++
+				continue
+			}
+			result.Chapters++
+			result.Segments += n
+		}
+	}
+
+	return result, nil
+}
+
+// learn aligns and stores one chapter, returning the number of segments stored
+func (s *TranslationMemoryService) learn(ctx context.Context, novelID string, c chapter.Chapter, sourceLang, targetLang string) (int, error) {
+	var source, target *chapter.ChapterTranslation
+	for i := range c.Translations {
+		switch c.Translations[i].Lang {
+		case sourceLang:
+			source = &c.Translations[i]
+		case targetLang:
+			// Unreviewed machine output must not become a reference
+			if c.Translations[i].IsApproved() {
+				target = &c.Translations[i]
+			}
+		}
+	}
+	if source == nil || target == nil || sourceLang == targetLang {
+		return 0, nil
+	}
+
+	sourceSegments := append([]string{source.Title}, epub.TranslatableSegments(epub.SegmentHTML(source.Content))...)
+	targetSegments := append([]string{target.Title}, epub.TranslatableSegments(epub.SegmentHTML(target.Content))...)
+	if len(sourceSegments) != len(targetSegments) {
+		return 0, nil
+	}
+
+	chapterID := c.ID
+	entries := make([]translationmemory.TranslationMemoryEntry, 0, len(sourceSegments))
+	seen := make(map[string]bool, len(sourceSegments))
+	for i := range sourceSegments {
+		normalized := translationmemory.Normalize(sourceSegments[i])
+		hash := translationmemory.Hash(normalized)
+		// A batch must not update the same row twice
+		if normalized == "" || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		entries = append(entries, translationmemory.TranslationMemoryEntry{
+			SourceLang: sourceLang,
+			TargetLang: targetLang,
+			SourceHash: hash,
+			SourceText: normalized,
+			TargetText: targetSegments[i],
+			NovelID:    &novelID,
+			ChapterID:  &chapterID,
+		})
+	}
+
+	if err := s.memoryRepo.Upsert(ctx, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+func hasChapterTranslation(c chapter.Chapter, lang string) bool {
+	for _, tr := range c.Translations {
+		if tr.Lang == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// learnChapterTranslation is called after a translation has been accepted.
+// Translation memory is an optimisation, so failures are only logged.
+func learnChapterTranslation(ctx context.Context, memory *TranslationMemoryService, chapterID, lang string) {
+	if memory == nil {
+		return
+	}
+	if _, err := memory.LearnChapter(ctx, chapterID, lang); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error(err, "failed to add chapter translation to translation memory")
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	translationmemory "simple-go/internal/domain/translation_memory"
+)
+
+// batchMemoryRepo answers exact lookups from a fixed set of segments and
+// records every fuzzy batch it is asked for
+type batchMemoryRepo struct {
+	fakeMemoryRepo
+	exact   map[string]string
+	batches [][]string
+}
+
+func (r *batchMemoryRepo) FindExact(ctx context.Context, sourceLang, targetLang string, hashes []string) ([]translationmemory.TranslationMemoryEntry, error) {
+	var entries []translationmemory.TranslationMemoryEntry
+	for source, target := range r.exact {
+		entries = append(entries, translationmemory.TranslationMemoryEntry{SourceHash: translationmemory.Hash(source), TargetText: target})
+	}
+	return entries, nil
+}
+
+func (r *batchMemoryRepo) FindBestFuzzy(ctx context.Context, sourceLang, targetLang string, texts []string, minSimilarity float64) (map[int]translationmemory.FuzzyMatch, error) {
+	r.batches = append(r.batches, texts)
+	return map[int]translationmemory.FuzzyMatch{
+		1: {TranslationMemoryEntry: translationmemory.TranslationMemoryEntry{TargetText: "fuzzy"}, Similarity: 0.9},
+	}, nil
+}
+
+func TestMatchLooksUpFuzzySegmentsInOneBatch(t *testing.T) {
+	repo := &batchMemoryRepo{exact: map[string]string{"known": "bekannt"}}
+	memory := NewTranslationMemoryService(repo, nil, nil, 0.8)
+
+	matches, err := memory.Match(context.Background(), "en", "de", []string{"first", "known", "second  segment", "third"})
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+
+	if want := [][]string{{"first", "second segment", "third"}}; !reflect.DeepEqual(repo.batches, want) {
+		t.Fatalf("fuzzy batches = %q, want %q", repo.batches, want)
+	}
+	if _, ok := matches.Exact[1]; !ok || len(matches.Exact) != 1 {
+		t.Errorf("exact matches = %v, want segment 1 only", matches.Exact)
+	}
+	if m, ok := matches.Fuzzy[2]; !ok || m.TargetText != "fuzzy" || len(matches.Fuzzy) != 1 {
+		t.Errorf("fuzzy matches = %v, want segment 2 only", matches.Fuzzy)
+	}
+}
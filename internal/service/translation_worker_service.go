@@ -0,0 +1,913 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/glossary"
+	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/novel"
+	"simple-go/internal/domain/volume"
+	"simple-go/internal/repository"
+	"simple-go/pkg/epub"
+	"simple-go/pkg/logger"
+	"simple-go/pkg/qa"
+	"simple-go/pkg/queue"
+	"simple-go/pkg/translator"
+
+	"gorm.io/gorm"
+)
+
+// TranslationWorkerOptions tunes how a worker polls the queue and leases subtasks
+type TranslationWorkerOptions struct {
+	// WorkerID identifies this worker as the owner of its leases
+	WorkerID          string
+	PollTimeout       time.Duration
+	LeaseDuration     time.Duration
+	HeartbeatInterval time.Duration
+	ReaperInterval    time.Duration
+	RetryPolicy       job.RetryPolicy
+	QAPolicy          job.QAPolicy
+	// TurnSubtasks is how many subtasks of a job a worker processes per turn
+	// before the job goes back in the queue behind other users' jobs
+	TurnSubtasks int
+}
+
+// TranslationWorkerService consumes translation jobs from the queue and
+// executes their subtasks. Subtasks are leased one at a time, so several
+// workers can share a job and work abandoned by a crashed worker is
+// returned to the pool once its lease expires.
+type TranslationWorkerService struct {
+	uow         repository.UnitOfWork
+	jobRepo     repository.TranslationJobRepository
+	novelRepo   repository.NovelRepository
+	volumeRepo  repository.VolumeRepository
+	chapterRepo repository.ChapterRepository
+	redisQueue  *queue.RedisQueue
+	translator  translator.Translator
+	memory      *TranslationMemoryService
+	events      *TranslationEventService
+	opts        TranslationWorkerOptions
+
+	// running maps a job ID to the cancel func of the subtask being processed for it
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+func NewTranslationWorkerService(
+	uow repository.UnitOfWork,
+	jobRepo repository.TranslationJobRepository,
+	novelRepo repository.NovelRepository,
+	volumeRepo repository.VolumeRepository,
+	chapterRepo repository.ChapterRepository,
+	redisQueue *queue.RedisQueue,
+	engine translator.Translator,
+	memory *TranslationMemoryService,
+	events *TranslationEventService,
+	opts TranslationWorkerOptions,
+) *TranslationWorkerService {
+	return &TranslationWorkerService{
+		uow:         uow,
+		jobRepo:     jobRepo,
+		novelRepo:   novelRepo,
+		volumeRepo:  volumeRepo,
+		chapterRepo: chapterRepo,
+		redisQueue:  redisQueue,
+		translator:  engine,
+		memory:      memory,
+		events:      events,
+		opts:        opts,
+		running:     make(map[string]context.CancelFunc),
+	}
+}
+
+// Run takes turns on the jobs in the queue until the context is cancelled.
+// When the queue is idle the worker helps out on any job that still has
+// claimable subtasks.
+func (s *TranslationWorkerService) Run(ctx context.Context) error {
+	if s.redisQueue == nil {
+		return errors.New("translation worker requires a Redis queue")
+	}
+
+	logger.Info(fmt.Sprintf("Translation worker %s started", s.opts.WorkerID))
+
+	go s.runReaper(ctx)
+	go s.watchCancellations(ctx)
+
+	// After helping out, look at the queue again without waiting
+	helped := false
+	for {
+		if ctx.Err() != nil {
+			logger.Info(fmt.Sprintf("Translation worker %s stopped", s.opts.WorkerID))
+			return nil
+		}
+
+		timeout := s.opts.PollTimeout
+		if helped {
+			timeout = 0
+		}
+
+		msg, err := s.redisQueue.Consume(ctx, timeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			logger.Error(err, "failed to consume translation job")
+			time.Sleep(s.opts.PollTimeout)
+			continue
+		}
+		if msg == nil {
+			helped, err = s.processAnyJob(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Error(err, "failed to process claimable subtasks")
+			}
+			continue
+		}
+		helped = false
+
+		logger.Info(fmt.Sprintf("Processing translation job %s", msg.JobID))
+		if err := s.ProcessJob(ctx, msg.JobID); err != nil {
+			logger.Error(err, fmt.Sprintf("translation job %s failed", msg.JobID))
+		}
+	}
+}
+
+// ProcessJob takes one turn on a job: it claims up to TurnSubtasks pending
+// subtasks in priority/seq order and stages the translated entities. A job
+// with work left is queued again for its next turn.
+func (s *TranslationWorkerService) ProcessJob(ctx context.Context, jobID string) error {
+	j, err := s.jobRepo.GetSummaryByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("translation job not found")
+		}
+		return fmt.Errorf("unable to load translation job: %w", err)
+	}
+
+	if job.IsTerminalJobStatus(j.Status) {
+		logger.Info(fmt.Sprintf("Skipping translation job %s with status %s", j.ID, j.Status))
+		return nil
+	}
+
+	if err := s.jobRepo.MarkStarted(ctx, j.ID); err != nil {
+		return fmt.Errorf("unable to start translation job: %w", err)
+	}
+	if j.Status != job.TranslationJobStatusInProgress {
+		s.publishJobState(ctx, j.ID)
+	}
+
+	for n := 0; n < s.turnSubtasks(); n++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		subtask, err := s.jobRepo.ClaimNextSubtask(ctx, j.ID, s.opts.WorkerID, s.opts.LeaseDuration)
+		if err != nil {
+			return fmt.Errorf("unable to claim subtask: %w", err)
+		}
+		if subtask == nil {
+			return s.finishIfDone(ctx, j.ID)
+		}
+
+		if err := s.executeSubtask(ctx, j, subtask); err != nil {
+			return err
+		}
+	}
+
+	return s.requeue(ctx, j.ID)
+}
+
+// requeue puts a job back in the queue for its next turn, with its current
+// priority
+func (s *TranslationWorkerService) requeue(ctx context.Context, jobID string) error {
+	return s.requeueAt(ctx, jobID, time.Time{})
+}
+
+// requeueAt puts a job back in the queue once at has passed; a zero time
+// queues it at once
+func (s *TranslationWorkerService) requeueAt(ctx context.Context, jobID string, at time.Time) error {
+	j, err := s.jobRepo.GetSummaryByID(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("unable to load translation job: %w", err)
+	}
+	if job.IsTerminalJobStatus(j.Status) {
+		return nil
+	}
+
+	if at.IsZero() {
+		err = s.redisQueue.Publish(ctx, newJobMessage(j))
+	} else {
+		err = s.redisQueue.PublishAt(ctx, newJobMessage(j), at)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to re-queue translation job: %w", err)
+	}
+	return nil
+}
+
+func (s *TranslationWorkerService) turnSubtasks() int {
+	if s.opts.TurnSubtasks < 1 {
+		return 1
+	}
+	return s.opts.TurnSubtasks
+}
+
+// processAnyJob claims up to one turn's worth of subtasks across all active
+// jobs, which picks up work released by the reaper or waiting for a retry and
+// lets idle workers share large jobs. It reports whether it found any work, and
+// returns after one turn so that the worker checks the queue again.
+func (s *TranslationWorkerService) processAnyJob(ctx context.Context) (bool, error) {
+	for n := 0; n < s.turnSubtasks(); n++ {
+		if ctx.Err() != nil {
+			return n > 0, nil
+		}
+
+		subtask, err := s.jobRepo.ClaimNextSubtask(ctx, "", s.opts.WorkerID, s.opts.LeaseDuration)
+		if err != nil {
+			return n > 0, fmt.Errorf("unable to claim subtask: %w", err)
+		}
+		if subtask == nil {
+			return n > 0, nil
+		}
+
+		j, err := s.jobRepo.GetSummaryByID(ctx, subtask.JobID)
+		if err != nil {
+			return true, fmt.Errorf("unable to load translation job: %w", err)
+		}
+
+		if err := s.executeSubtask(ctx, j, subtask); err != nil {
+			logger.Error(err, fmt.Sprintf("translation job %s failed", j.ID))
+			continue
+		}
+		if err := s.finishIfDone(ctx, j.ID); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// executeSubtask runs a leased subtask while a heartbeat keeps the lease alive.
+// Failures are retried with backoff according to the retry policy; losing the
+// lease, e.g. because the job was cancelled, only abandons the subtask.
+func (s *TranslationWorkerService) executeSubtask(ctx context.Context, j *job.TranslationJob, subtask *job.TranslationSubtask) error {
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.heartbeat(workCtx, cancel, subtask.ID)
+
+	s.trackRunning(j.ID, cancel)
+	defer s.untrackRunning(j.ID)
+
+	err := s.processSubtask(workCtx, j, subtask)
+	if err == nil {
+		s.events.Publish(ctx, job.NewSubtaskEvent(*subtask, job.TranslationSubtaskStatusDone, nil))
+		if err := s.updateProgress(ctx, j); err != nil {
+			logger.Error(err, "failed to update job progress")
+		}
+		return nil
+	}
+
+	if errors.Is(err, repository.ErrSubtaskLeaseLost) || (workCtx.Err() != nil && ctx.Err() == nil) {
+		logger.Warn(fmt.Sprintf("Subtask %s was cancelled or its lease was lost, abandoning it", subtask.ID))
+		return nil
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the lease will expire and the reaper hands the subtask to another worker
+		return nil
+	}
+
+	return s.handleSubtaskFailure(ctx, subtask, err)
+}
+
+// handleSubtaskFailure records a failed attempt. Permanent errors fail the
+// subtask at once, transient ones are rescheduled until the retry policy gives
+// up and the subtask is dead-lettered.
+func (s *TranslationWorkerService) handleSubtaskFailure(ctx context.Context, subtask *job.TranslationSubtask, cause error) error {
+	var (
+		markErr       error
+		status        string
+		nextAttemptAt time.Time
+		message       = cause.Error()
+	)
+	switch {
+	case isPermanent(cause):
+		logger.Error(cause, fmt.Sprintf("subtask %s failed permanently", subtask.ID))
+		status = job.TranslationSubtaskStatusFailed
+		markErr = s.jobRepo.MarkSubtaskFailed(ctx, subtask.ID, s.opts.WorkerID, message)
+	case s.opts.RetryPolicy.ShouldRetry(subtask.Attempts):
+		nextAttemptAt = s.opts.RetryPolicy.NextAttemptAt(subtask.Attempts, time.Now())
+		logger.Warn(fmt.Sprintf("Subtask %s attempt %d failed, retrying at %s: %v",
+			subtask.ID, subtask.Attempts, nextAttemptAt.Format(time.RFC3339), cause))
+		status = job.TranslationSubtaskStatusPending
+		markErr = s.jobRepo.ScheduleSubtaskRetry(ctx, subtask.ID, s.opts.WorkerID, message, nextAttemptAt)
+	default:
+		logger.Error(cause, fmt.Sprintf("subtask %s dead-lettered after %d attempts", subtask.ID, subtask.Attempts))
+		status = job.TranslationSubtaskStatusDeadLetter
+		markErr = s.jobRepo.MarkSubtaskDeadLettered(ctx, subtask.ID, s.opts.WorkerID, message)
+	}
+
+	if markErr != nil {
+		if errors.Is(markErr, repository.ErrSubtaskLeaseLost) {
+			return nil
+		}
+		return fmt.Errorf("unable to record subtask failure: %w", markErr)
+	}
+
+	s.events.Publish(ctx, job.NewSubtaskEvent(*subtask, status, &message))
+
+	// The job gets a turn when the retry is due; without one it would only be
+	// picked up again by a worker with nothing else to do
+	if status == job.TranslationSubtaskStatusPending {
+		if err := s.requeueAt(ctx, subtask.JobID, nextAttemptAt); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to schedule translation job %s for its retry", subtask.JobID))
+		}
+	}
+	return nil
+}
+
+// heartbeat renews the subtask lease until ctx is done and cancels the work
+// as soon as the lease cannot be renewed
+func (s *TranslationWorkerService) heartbeat(ctx context.Context, cancel context.CancelFunc, subtaskID string) {
+	ticker := time.NewTicker(s.opts.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.jobRepo.RenewLease(ctx, subtaskID, s.opts.WorkerID, s.opts.LeaseDuration)
+			if err == nil {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, repository.ErrSubtaskLeaseLost) {
+				cancel()
+				return
+			}
+			logger.Error(err, fmt.Sprintf("failed to renew lease on subtask %s", subtaskID))
+		}
+	}
+}
+
+// watchCancellations interrupts the subtask in flight as soon as its job is
+// cancelled. Heartbeats catch the cancellation as well if a signal is missed.
+func (s *TranslationWorkerService) watchCancellations(ctx context.Context) {
+	jobIDs, err := s.redisQueue.SubscribeCancellations(ctx)
+	if err != nil {
+		logger.Error(err, "failed to subscribe to job cancellations, relying on lease heartbeats")
+		return
+	}
+
+	for jobID := range jobIDs {
+		s.mu.Lock()
+		cancel, ok := s.running[jobID]
+		s.mu.Unlock()
+
+		if ok {
+			logger.Info(fmt.Sprintf("Translation job %s cancelled, interrupting its subtask", jobID))
+			cancel()
+		}
+	}
+}
+
+func (s *TranslationWorkerService) trackRunning(jobID string, cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running[jobID] = cancel
+}
+
+func (s *TranslationWorkerService) untrackRunning(jobID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, jobID)
+}
+
+// runReaper periodically returns subtasks with expired leases to PENDING and
+// re-queues their jobs so that a live worker resumes them
+func (s *TranslationWorkerService) runReaper(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.ReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			jobIDs, err := s.jobRepo.ReleaseExpiredLeases(ctx, s.opts.RetryPolicy.MaxAttempts)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error(err, "failed to release expired subtask leases")
+				}
+				continue
+			}
+
+			for _, jobID := range jobIDs {
+				logger.Warn(fmt.Sprintf("Released expired subtask leases of translation job %s", jobID))
+				if err := s.requeue(ctx, jobID); err != nil {
+					logger.Error(err, fmt.Sprintf("failed to re-queue translation job %s", jobID))
+				}
+			}
+		}
+	}
+}
+
+// finishIfDone finishes the job once no subtask is pending or leased: FAILED
+// when any subtask failed or was dead-lettered, COMPLETED otherwise. Other
+// workers may still be holding leases on the job, in which case the last one
+// to finish completes it.
+func (s *TranslationWorkerService) finishIfDone(ctx context.Context, jobID string) error {
+	active, err := s.jobRepo.CountActiveSubtasks(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("unable to count active subtasks: %w", err)
+	}
+	if active > 0 {
+		return nil
+	}
+
+	failed, err := s.jobRepo.CountSubtasksByStatus(ctx, jobID, job.TranslationSubtaskStatusFailed)
+	if err != nil {
+		return fmt.Errorf("unable to count failed subtasks: %w", err)
+	}
+	deadLettered, err := s.jobRepo.CountSubtasksByStatus(ctx, jobID, job.TranslationSubtaskStatusDeadLetter)
+	if err != nil {
+		return fmt.Errorf("unable to count dead-lettered subtasks: %w", err)
+	}
+
+	qaErrors, qaWarnings, err := s.jobRepo.SumSubtaskQA(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("unable to sum QA findings: %w", err)
+	}
+	qaStatus := s.opts.QAPolicy.Evaluate(qaErrors, qaWarnings)
+	if err := s.jobRepo.RecordQA(ctx, jobID, qaStatus, qaErrors, qaWarnings); err != nil {
+		return fmt.Errorf("unable to record QA result: %w", err)
+	}
+
+	if failed+deadLettered > 0 {
+		message := fmt.Sprintf("%d subtasks failed, %d dead-lettered", failed, deadLettered)
+		if err := s.jobRepo.MarkFinished(ctx, jobID, job.TranslationJobStatusFailed, &message); err != nil {
+			return fmt.Errorf("unable to fail translation job: %w", err)
+		}
+		logger.Warn(fmt.Sprintf("Translation job %s failed: %s", jobID, message))
+		s.publishJobState(ctx, jobID)
+		return nil
+	}
+
+	if qaStatus == job.QAStatusFailed {
+		message := fmt.Sprintf("QA found %d errors, more than the %d allowed", qaErrors, s.opts.QAPolicy.MaxErrors)
+		if err := s.jobRepo.MarkFinished(ctx, jobID, job.TranslationJobStatusFailed, &message); err != nil {
+			return fmt.Errorf("unable to fail translation job: %w", err)
+		}
+		logger.Warn(fmt.Sprintf("Translation job %s failed QA: %s", jobID, message))
+		s.publishJobState(ctx, jobID)
+		return nil
+	}
+
+	if err := s.jobRepo.MarkFinished(ctx, jobID, job.TranslationJobStatusCompleted, nil); err != nil {
+		return fmt.Errorf("unable to complete translation job: %w", err)
+	}
+
+	logger.Info(fmt.Sprintf("Translation job %s completed", jobID))
+	s.publishOnComplete(ctx, jobID)
+	s.publishJobState(ctx, jobID)
+	return nil
+}
+
+// publishOnComplete promotes the staged results of a completed job unless a
+// reviewer publishes it. A failed publish leaves the results staged, so they
+// can still be published by hand.
+func (s *TranslationWorkerService) publishOnComplete(ctx context.Context, jobID string) {
+	j, err := s.jobRepo.GetSummaryByID(ctx, jobID)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("failed to load translation job %s for publishing", jobID))
+		return
+	}
+	if j.PublishMode != job.TranslationJobPublishOnComplete {
+		return
+	}
+
+	var published int
+	err = s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		published, err = publishStagedResults(ctx, provider, jobID, nil)
+		return err
+	})
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("failed to publish translation job %s", jobID))
+		return
+	}
+	logger.Info(fmt.Sprintf("Translation job %s published %d results", jobID, published))
+}
+
+// publishJobState broadcasts the job's current status to event subscribers
+func (s *TranslationWorkerService) publishJobState(ctx context.Context, jobID string) {
+	j, err := s.jobRepo.GetSummaryByID(ctx, jobID)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("failed to load translation job %s for its status event", jobID))
+		return
+	}
+	s.events.Publish(ctx, job.NewJobStateEvent(job.TranslationJobEventStatus, *j))
+}
+
+// processSubtask translates one leased subtask and stages its result. The
+// source data is read and the result written in short transactions of their
+// own, so that no transaction stays open while the translator is working.
+func (s *TranslationWorkerService) processSubtask(ctx context.Context, j *job.TranslationJob, subtask *job.TranslationSubtask) error {
+	src, err := s.loadSubtaskSource(ctx, j, subtask)
+	if err != nil {
+		return err
+	}
+
+	// With a pivot language the subtask is translated in two legs, source to
+	// pivot and pivot to target; st follows the leg that produces the result
+	var pivotSt *subtaskState
+	fromLang := j.FromLang
+	if j.PivotLang != nil {
+		pivotSt = newSubtaskState(j.FromLang, *j.PivotLang, src.pivotGlossary)
+		fromLang = *j.PivotLang
+	}
+	st := newSubtaskState(fromLang, j.TargetLang, src.glossary)
+
+	var result *job.StagedResult
+	switch {
+	case src.chapter != nil:
+		result, err = s.translateChapter(ctx, j, st, pivotSt, src.chapter)
+	case src.volume != nil:
+		result, err = s.translateVolume(ctx, j, st, pivotSt, src.volume)
+	default:
+		result, err = s.translateNovel(ctx, j, st, pivotSt, src.novel)
+	}
+	if err != nil {
+		return err
+	}
+	if pivotSt != nil {
+		st.addPivotLeg(pivotSt)
+	}
+
+	// The output is staged on the subtask until the job is published
+	staged, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("unable to encode staged result: %w", err)
+	}
+	resultText := string(staged)
+
+	if len(st.glossaryCheck.Misses) > 0 {
+		logger.Warn(fmt.Sprintf("Subtask %s missed %d glossary terms: %s",
+			subtask.ID, len(st.glossaryCheck.Misses), strings.Join(st.glossaryCheck.Misses, ", ")))
+	}
+
+	findings := append(st.qaFindings, qa.GlossaryMisses(st.glossaryCheck.Misses)...)
+	if errs, warnings := qa.Count(findings); errs+warnings > 0 {
+		logger.Warn(fmt.Sprintf("Subtask %s has %d QA errors and %d warnings", subtask.ID, errs, warnings))
+	}
+
+	return s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		// Fails with ErrSubtaskLeaseLost, rolling back the write, if another
+		// worker took the subtask over in the meantime
+		return provider.TranslationJob().MarkSubtaskDone(ctx, subtask.ID, s.opts.WorkerID, job.SubtaskResult{
+			ResultText:          &resultText,
+			GlossaryHits:        len(st.glossaryCheck.Hits),
+			GlossaryMissedTerms: st.glossaryCheck.Misses,
+			Memory:              st.memory,
+			QAFindings:          findings,
+		})
+	})
+}
+
+// subtaskSource is what a subtask translates: exactly one of chapter, volume
+// and novel, along with the glossaries of the legs it is translated in
+type subtaskSource struct {
+	chapter       *chapter.Chapter
+	volume        *volume.Volume
+	novel         *novel.Novel
+	glossary      []glossary.GlossaryTerm
+	pivotGlossary []glossary.GlossaryTerm
+}
+
+// loadSubtaskSource reads the subtask's entity and glossaries in one
+// transaction, so that they are consistent with each other
+func (s *TranslationWorkerService) loadSubtaskSource(ctx context.Context, j *job.TranslationJob, subtask *job.TranslationSubtask) (*subtaskSource, error) {
+	src := &subtaskSource{}
+	err := s.uow.Do(ctx, func(provider repository.RepositoryProvider) error {
+		var err error
+		switch subtask.EntityType {
+		case job.EntityTypeChapter:
+			if src.chapter, err = provider.Chapter().GetByID(ctx, subtask.EntityID); err != nil {
+				return fmt.Errorf("unable to load chapter: %w", err)
+			}
+		case job.EntityTypeVolume:
+			if src.volume, err = provider.Volume().GetByID(ctx, subtask.EntityID); err != nil {
+				return fmt.Errorf("unable to load volume: %w", err)
+			}
+		case job.EntityTypeNovel:
+			if src.novel, err = provider.Novel().GetByID(ctx, subtask.EntityID); err != nil {
+				return fmt.Errorf("unable to load novel: %w", err)
+			}
+		default:
+			return permanentError{fmt.Errorf("unknown entity type %q", subtask.EntityType)}
+		}
+
+		fromLang := j.FromLang
+		if j.PivotLang != nil {
+			if src.pivotGlossary, err = provider.Glossary().GetForLanguagePair(ctx, j.NovelID, j.FromLang, *j.PivotLang); err != nil {
+				return fmt.Errorf("unable to load glossary: %w", err)
+			}
+			fromLang = *j.PivotLang
+		}
+
+		if src.glossary, err = provider.Glossary().GetForLanguagePair(ctx, j.NovelID, fromLang, j.TargetLang); err != nil {
+			return fmt.Errorf("unable to load glossary: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+func (s *TranslationWorkerService) translateChapter(ctx context.Context, j *job.TranslationJob, st, pivotSt *subtaskState, c *chapter.Chapter) (*job.StagedResult, error) {
+	source := chapter.SelectTranslation(c.Translations, j.FromLang)
+	if source == nil {
+		return nil, permanentError{errors.New("chapter has no source translation")}
+	}
+
+	// A pivot translation starts from the chapter's translation in the pivot
+	// language, which is created along with the result if it is missing
+	texts := []string{source.Title, source.Content}
+	var pivot *job.StagedResult
+	if pivotSt != nil {
+		if existing := chapter.PivotTranslation(c.Translations, pivotSt.toLang); existing != nil {
+			texts = []string{existing.Title, existing.Content}
+		} else {
+			out, err := s.translate(ctx, j, pivotSt, job.EntityTypeChapter, texts...)
+			if err != nil {
+				return nil, fmt.Errorf("unable to translate chapter to pivot language: %w", err)
+			}
+			texts = out
+			pivot = &job.StagedResult{Title: out[0], Content: out[1]}
+		}
+	}
+
+	out, err := s.translate(ctx, j, st, job.EntityTypeChapter, texts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to translate chapter: %w", err)
+	}
+
+	return &job.StagedResult{Title: out[0], Content: out[1], Pivot: pivot}, nil
+}
+
+func (s *TranslationWorkerService) translateVolume(ctx context.Context, j *job.TranslationJob, st, pivotSt *subtaskState, v *volume.Volume) (*job.StagedResult, error) {
+	source := volume.SelectTranslation(v.Translations, j.FromLang, v.OriginalLanguage)
+	if source == nil {
+		return nil, permanentError{errors.New("volume has no source translation")}
+	}
+
+	var err error
+	title, description := source.Title, source.Description
+	if pivotSt != nil {
+		if existing := volume.PivotTranslation(v.Translations, pivotSt.toLang); existing != nil {
+			title, description = existing.Title, existing.Description
+		} else if title, description, err = s.translateTitleAndDescription(ctx, j, pivotSt, job.EntityTypeVolume, title, description); err != nil {
+			return nil, fmt.Errorf("unable to translate volume to pivot language: %w", err)
+		}
+	}
+
+	title, description, err = s.translateTitleAndDescription(ctx, j, st, job.EntityTypeVolume, title, description)
+	if err != nil {
+		return nil, fmt.Errorf("unable to translate volume: %w", err)
+	}
+
+	return &job.StagedResult{Title: title, Description: description}, nil
+}
+
+func (s *TranslationWorkerService) translateNovel(ctx context.Context, j *job.TranslationJob, st, pivotSt *subtaskState, n *novel.Novel) (*job.StagedResult, error) {
+	source := novel.SelectTranslation(n.Translations, j.FromLang, n.OriginalLanguage)
+	if source == nil {
+		return nil, permanentError{errors.New("novel has no source translation")}
+	}
+
+	var err error
+	title, description := source.Title, source.Description
+	if pivotSt != nil {
+		if existing := novel.PivotTranslation(n.Translations, pivotSt.toLang); existing != nil {
+			title, description = existing.Title, existing.Description
+		} else if title, description, err = s.translateTitleAndDescription(ctx, j, pivotSt, job.EntityTypeNovel, title, description); err != nil {
+			return nil, fmt.Errorf("unable to translate novel to pivot language: %w", err)
+		}
+	}
+
+	title, description, err = s.translateTitleAndDescription(ctx, j, st, job.EntityTypeNovel, title, description)
+	if err != nil {
+		return nil, fmt.Errorf("unable to translate novel: %w", err)
+	}
+
+	return &job.StagedResult{Title: title, Description: description}, nil
+}
+
+// permanentError marks failures that retrying cannot fix, such as missing
+// source data
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+func (e permanentError) Unwrap() error { return e.err }
+
+func isPermanent(err error) bool {
+	var pe permanentError
+	return errors.As(err, &pe)
+}
+
+// subtaskState collects what happens while a subtask is translated from one
+// language to another: the glossary it has to follow, how well the output
+// followed it, how much translation memory was reused and what the QA checks
+// found
+type subtaskState struct {
+	fromLang      string
+	toLang        string
+	glossary      []translator.GlossaryEntry
+	glossaryCheck translator.GlossaryCheck
+	memory        job.MemoryStats
+	qaFindings    []qa.Finding
+}
+
+func newSubtaskState(fromLang, toLang string, terms []glossary.GlossaryTerm) *subtaskState {
+	entries := make([]translator.GlossaryEntry, len(terms))
+	for i, t := range terms {
+		entries[i] = translator.GlossaryEntry{Source: t.SourceTerm, Target: t.TargetTerm}
+	}
+	return &subtaskState{fromLang: fromLang, toLang: toLang, glossary: entries}
+}
+
+// addPivotLeg adds what happened in the source to pivot leg to st, so the
+// subtask reports both legs. QA findings of the pivot leg are located under
+// "pivot".
+func (st *subtaskState) addPivotLeg(pivot *subtaskState) {
+	st.memory.Segments += pivot.memory.Segments
+	st.memory.ExactHits += pivot.memory.ExactHits
+	st.memory.FuzzyHits += pivot.memory.FuzzyHits
+
+	st.glossaryCheck.Hits = append(st.glossaryCheck.Hits, pivot.glossaryCheck.Hits...)
+	st.glossaryCheck.Misses = append(st.glossaryCheck.Misses, pivot.glossaryCheck.Misses...)
+
+	for _, f := range pivot.qaFindings {
+		f.Location = "pivot " + f.Location
+		st.qaFindings = append(st.qaFindings, f)
+	}
+}
+
+// segmentRef locates a translatable segment: texts[text] segment seg
+type segmentRef struct {
+	text int
+	seg  int
+}
+
+// translate translates all texts of one entity across st's language pair. The
+// texts are split into segments; exact translation memory matches are filled
+// in directly and the remaining segments go to the translator in a single
+// batch, together with any fuzzy matches as references. The assembled output
+// is checked against the glossary.
+func (s *TranslationWorkerService) translate(ctx context.Context, j *job.TranslationJob, st *subtaskState, entityType string, texts ...string) ([]string, error) {
+	segmented := make([][]epub.Segment, len(texts))
+	refs := make([]segmentRef, 0)
+	segments := make([]string, 0)
+	for i, text := range texts {
+		segmented[i] = epub.SegmentHTML(text)
+		for k, seg := range segmented[i] {
+			if seg.Translatable {
+				refs = append(refs, segmentRef{text: i, seg: k})
+				segments = append(segments, seg.Text)
+			}
+		}
+	}
+
+	matches, err := s.memory.Match(ctx, st.fromLang, st.toLang, segments)
+	if err != nil {
+		// Translation memory only saves work, so carry on without it
+		logger.Error(err, "failed to look up translation memory")
+		matches = &MemoryMatches{}
+	}
+	st.memory.Segments += len(segments)
+
+	pending := make([]int, 0, len(segments))
+	references := make([]translator.MemoryMatch, 0)
+	for n := range segments {
+		if e, ok := matches.Exact[n]; ok {
+			segmented[refs[n].text][refs[n].seg].Text = e.TargetText
+			st.memory.ExactHits++
+			continue
+		}
+		if m, ok := matches.Fuzzy[n]; ok {
+			st.memory.FuzzyHits++
+			references = append(references, translator.MemoryMatch{
+				Source:     m.SourceText,
+				Target:     m.TargetText,
+				Similarity: m.Similarity,
+			})
+		}
+		pending = append(pending, n)
+	}
+
+	if len(pending) > 0 {
+		batch := make([]string, len(pending))
+		for k, n := range pending {
+			batch[k] = segments[n]
+		}
+
+		out, err := s.translator.Translate(ctx, translator.Request{
+			Texts:      batch,
+			FromLang:   st.fromLang,
+			TargetLang: st.toLang,
+			Context: map[string]string{
+				"entity_type": entityType,
+				"novel_id":    j.NovelID,
+			},
+			Glossary: st.glossary,
+			Memory:   references,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(out) != len(batch) {
+			return nil, fmt.Errorf("translator %s returned %d texts for %d inputs", s.translator.Name(), len(out), len(batch))
+		}
+
+		for k, n := range pending {
+			segmented[refs[n].text][refs[n].seg].Text = out[k]
+		}
+	}
+
+	if len(matches.Exact) > 0 {
+		s.memory.MarkUsed(ctx, matches.Exact)
+	}
+
+	results := make([]string, len(texts))
+	for i := range texts {
+		results[i] = epub.JoinSegments(segmented[i])
+	}
+
+	st.glossaryCheck = translator.CheckGlossary(texts, results, st.glossary)
+
+	checked := make([]qa.Text, len(texts))
+	for i := range texts {
+		checked[i] = qa.Text{Field: fieldName(entityType, i), Source: texts[i], Output: results[i]}
+	}
+	st.qaFindings = append(st.qaFindings, qa.Check(st.fromLang, st.toLang, checked)...)
+
+	return results, nil
+}
+
+// fieldName names the i-th text translate is given for an entity, for QA
+// finding locations
+func fieldName(entityType string, i int) string {
+	switch {
+	case i == 0:
+		return "title"
+	case entityType == job.EntityTypeChapter:
+		return "content"
+	}
+	return "description"
+}
+
+func (s *TranslationWorkerService) translateTitleAndDescription(ctx context.Context, j *job.TranslationJob, st *subtaskState, entityType, title string, description *string) (string, *string, error) {
+	if description == nil || *description == "" {
+		out, err := s.translate(ctx, j, st, entityType, title)
+		if err != nil {
+			return "", nil, err
+		}
+		return out[0], description, nil
+	}
+
+	out, err := s.translate(ctx, j, st, entityType, title, *description)
+	if err != nil {
+		return "", nil, err
+	}
+	return out[0], &out[1], nil
+}
+
+func (s *TranslationWorkerService) updateProgress(ctx context.Context, j *job.TranslationJob) error {
+	completed, err := s.jobRepo.CountSubtasksByStatus(ctx, j.ID, job.TranslationSubtaskStatusDone)
+	if err != nil {
+		return err
+	}
+
+	progress := 0
+	if j.TotalSubtasks > 0 {
+		progress = int(completed) * 100 / j.TotalSubtasks
+	}
+
+	if err := s.jobRepo.UpdateProgress(ctx, j.ID, progress, int(completed)); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, job.NewJobProgressEvent(j.ID, progress, int(completed), j.TotalSubtasks))
+	return nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"simple-go/internal/domain/chapter"
+	"simple-go/internal/domain/glossary"
+	"simple-go/internal/domain/job"
+	translationmemory "simple-go/internal/domain/translation_memory"
+	"simple-go/internal/repository"
+	"simple-go/pkg/translator"
+)
+
+// fakeUnitOfWork runs fn against a fixed provider and tracks whether a
+// transaction is open
+type fakeUnitOfWork struct {
+	provider repository.RepositoryProvider
+	inTx     bool
+}
+
+func (u *fakeUnitOfWork) Do(ctx context.Context, fn func(provider repository.RepositoryProvider) error) error {
+	u.inTx = true
+	defer func() { u.inTx = false }()
+	return fn(u.provider)
+}
+
+// The fakes embed the interfaces they stand in for; calling a method that is
+// not overridden panics, which flags unexpected repository use
+type fakeProvider struct {
+	repository.RepositoryProvider
+	chapters *fakeChapterRepo
+	glossary *fakeGlossaryRepo
+	jobs     *fakeJobRepo
+}
+
+func (p *fakeProvider) Chapter() repository.ChapterRepository               { return p.chapters }
+func (p *fakeProvider) Glossary() repository.GlossaryRepository             { return p.glossary }
+func (p *fakeProvider) TranslationJob() repository.TranslationJobRepository { return p.jobs }
+
+type fakeChapterRepo struct {
+	repository.ChapterRepository
+	chapter *chapter.Chapter
+}
+
+func (r *fakeChapterRepo) GetByID(ctx context.Context, id string) (*chapter.Chapter, error) {
+	return r.chapter, nil
+}
+
+type fakeGlossaryRepo struct {
+	repository.GlossaryRepository
+	terms []glossary.GlossaryTerm
+}
+
+func (r *fakeGlossaryRepo) GetForLanguagePair(ctx context.Context, novelID, sourceLang, targetLang string) ([]glossary.GlossaryTerm, error) {
+	return r.terms, nil
+}
+
+type fakeJobRepo struct {
+	repository.TranslationJobRepository
+	done map[string]job.SubtaskResult
+}
+
+func (r *fakeJobRepo) MarkSubtaskDone(ctx context.Context, id, owner string, result job.SubtaskResult) error {
+	r.done[id] = result
+	return nil
+}
+
+type fakeMemoryRepo struct {
+	repository.TranslationMemoryRepository
+}
+
+func (r *fakeMemoryRepo) FindExact(ctx context.Context, sourceLang, targetLang string, hashes []string) ([]translationmemory.TranslationMemoryEntry, error) {
+	return nil, nil
+}
+
+// txCheckingTranslator fails the test when it is called inside a transaction
+type txCheckingTranslator struct {
+	translator.Translator
+	t   *testing.T
+	uow *fakeUnitOfWork
+}
+
+func (tr *txCheckingTranslator) Translate(ctx context.Context, req translator.Request) ([]string, error) {
+	if tr.uow.inTx {
+		tr.t.Error("translator called inside a transaction")
+	}
+	return tr.Translator.Translate(ctx, req)
+}
+
+func TestProcessSubtaskStagesStubTranslation(t *testing.T) {
+	provider := &fakeProvider{
+		chapters: &fakeChapterRepo{chapter: &chapter.Chapter{
+			ID: "chapter-1",
+			Translations: []chapter.ChapterTranslation{{
+				Lang:    "ja",
+				Title:   "Prologue",
+				Content: "<p>The hero arrives.</p>",
+			}},
+		}},
+		glossary: &fakeGlossaryRepo{terms: []glossary.GlossaryTerm{{SourceTerm: "hero", TargetTerm: "Held"}}},
+		jobs:     &fakeJobRepo{done: make(map[string]job.SubtaskResult)},
+	}
+	uow := &fakeUnitOfWork{provider: provider}
+	engine := &txCheckingTranslator{Translator: translator.NewStubTranslator(), t: t, uow: uow}
+	memory := NewTranslationMemoryService(&fakeMemoryRepo{}, nil, nil, 0)
+
+	worker := NewTranslationWorkerService(uow, nil, nil, nil, nil, nil, engine, memory, nil, TranslationWorkerOptions{WorkerID: "worker-1"})
+
+	j := &job.TranslationJob{ID: "job-1", NovelID: "novel-1", FromLang: "ja", TargetLang: "de"}
+	subtask := &job.TranslationSubtask{ID: "subtask-1", JobID: j.ID, EntityType: job.EntityTypeChapter, EntityID: "chapter-1"}
+
+	if err := worker.processSubtask(context.Background(), j, subtask); err != nil {
+		t.Fatalf("processSubtask: %v", err)
+	}
+
+	result, ok := provider.jobs.done[subtask.ID]
+	if !ok {
+		t.Fatal("subtask was not marked done")
+	}
+	if result.ResultText == nil {
+		t.Fatal("subtask has no staged result")
+	}
+
+	var staged job.StagedResult
+	if err := json.Unmarshal([]byte(*result.ResultText), &staged); err != nil {
+		t.Fatalf("decode staged result: %v", err)
+	}
+	if want := "[de] Prologue"; staged.Title != want {
+		t.Errorf("title = %q, want %q", staged.Title, want)
+	}
+	if want := "<p>[de] The Held arrives.</p>"; staged.Content != want {
+		t.Errorf("content = %q, want %q", staged.Content, want)
+	}
+	if result.GlossaryHits != 1 || len(result.GlossaryMissedTerms) != 0 {
+		t.Errorf("glossary hits = %d, misses = %v, want 1 hit and no misses", result.GlossaryHits, result.GlossaryMissedTerms)
+	}
+	if result.Memory.Segments != 2 {
+		t.Errorf("memory segments = %d, want 2", result.Memory.Segments)
+	}
+}
+
+func TestProcessSubtaskRetranslatesUnreviewedPivot(t *testing.T) {
+	provider := &fakeProvider{
+		chapters: &fakeChapterRepo{chapter: &chapter.Chapter{
+			ID: "chapter-1",
+			Translations: []chapter.ChapterTranslation{{
+				Lang:    "ja",
+				Title:   "Prologue",
+				Content: "<p>The hero arrives.</p>",
+			}, {
+				Lang:    "en",
+				Title:   "Unchecked",
+				Content: "<p>Unchecked draft.</p>",
+				Status:  chapter.TranslationStatusMachineDraft,
+				Origin:  chapter.TranslationOriginMachine,
+			}},
+		}},
+		glossary: &fakeGlossaryRepo{terms: []glossary.GlossaryTerm{{SourceTerm: "hero", TargetTerm: "Held"}}},
+		jobs:     &fakeJobRepo{done: make(map[string]job.SubtaskResult)},
+	}
+	uow := &fakeUnitOfWork{provider: provider}
+	memory := NewTranslationMemoryService(&fakeMemoryRepo{}, nil, nil, 0)
+
+	worker := NewTranslationWorkerService(uow, nil, nil, nil, nil, nil, translator.NewStubTranslator(), memory, nil, TranslationWorkerOptions{WorkerID: "worker-1"})
+
+	pivotLang := "en"
+	j := &job.TranslationJob{ID: "job-1", NovelID: "novel-1", FromLang: "ja", TargetLang: "de", PivotLang: &pivotLang}
+	subtask := &job.TranslationSubtask{ID: "subtask-1", JobID: j.ID, EntityType: job.EntityTypeChapter, EntityID: "chapter-1"}
+
+	if err := worker.processSubtask(context.Background(), j, subtask); err != nil {
+		t.Fatalf("processSubtask: %v", err)
+	}
+
+	result := provider.jobs.done[subtask.ID]
+	var staged job.StagedResult
+	if err := json.Unmarshal([]byte(*result.ResultText), &staged); err != nil {
+		t.Fatalf("decode staged result: %v", err)
+	}
+	if staged.Pivot == nil {
+		t.Fatal("the machine draft was used as the pivot text")
+	}
+	if want := "<p>[en] The Held arrives.</p>"; staged.Pivot.Content != want {
+		t.Errorf("pivot content = %q, want %q", staged.Pivot.Content, want)
+	}
+	// The term only occurs in the source, so its hit comes from the pivot leg
+	if result.GlossaryHits != 1 {
+		t.Errorf("glossary hits = %d, want 1 from the pivot leg", result.GlossaryHits)
+	}
+	if result.Memory.Segments != 4 {
+		t.Errorf("memory segments = %d, want 4 over both legs", result.Memory.Segments)
+	}
+}
@@ -40,6 +40,13 @@ func (s *VolumeService) GetNovelVolumes(ctx context.Context, novelID, lang strin
 		return nil, errors.New("unable to get novel volumes")
 	}
 
+	// Chapter titles awaiting review are not shown in the public listing
+	for i := range volumes {
+		for j := range volumes[i].Chapters {
+			volumes[i].Chapters[j].Translations = chapter.ApprovedTranslations(volumes[i].Chapters[j].Translations)
+		}
+	}
+
 	response := make([]volume.VolumeResponseDTO, len(volumes))
 	for i, v := range volumes {
 		response[i] = volume.MapVolumeToDTO(v, lang)
@@ -48,14 +55,29 @@ func (s *VolumeService) GetNovelVolumes(ctx context.Context, novelID, lang strin
 	return response, nil
 }
 
-// GetChapterWithCrossVolumeNavigation returns a chapter with next/prev IDs including cross-volume navigation
-func (s *VolumeService) GetChapterWithCrossVolumeNavigation(ctx context.Context, chapterID, lang string) (*chapter.ChapterResponseDTO, error) {
+// GetVolumesWithChapters returns a novel's volumes with their chapters and all
+// translations, including those still under review
+func (s *VolumeService) GetVolumesWithChapters(ctx context.Context, novelID string) ([]volume.Volume, error) {
+	volumes, err := s.volumeRepo.GetAllWithChaptersByNovelID(ctx, novelID)
+	if err != nil {
+		logger.Error(err, "failed to get novel volumes with chapters")
+		return nil, errors.New("unable to get novel volumes")
+	}
+	return volumes, nil
+}
+
+// GetChapterWithCrossVolumeNavigation returns a chapter with next/prev IDs including cross-volume navigation.
+// Translations still under review are only considered when includeUnapproved is set.
+func (s *VolumeService) GetChapterWithCrossVolumeNavigation(ctx context.Context, chapterID, lang string, includeUnapproved bool) (*chapter.ChapterResponseDTO, error) {
 	// Get the chapter first
 	c, err := s.chapterRepo.GetByID(ctx, chapterID)
 	if err != nil {
 		logger.Error(err, "failed to get chapter by ID")
 		return nil, errors.New("unable to retrieve chapter")
 	}
+	if !includeUnapproved {
+		c.Translations = chapter.ApprovedTranslations(c.Translations)
+	}
 
 	// Get the volume to access novel_id and volume number
 	vol, err := s.volumeRepo.GetByID(ctx, c.VolumeID)
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"simple-go/internal/domain/job"
+	"simple-go/internal/domain/webhook"
+	"simple-go/internal/repository"
+	"simple-go/pkg/logger"
+	webhooksender "simple-go/pkg/webhook"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrWebhookNotFound is returned for an unknown subscription ID
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// WebhookOptions tunes delivery of webhook events
+type WebhookOptions struct {
+	RetryPolicy      job.RetryPolicy
+	DeliveryInterval time.Duration
+	// DeliveryLease is how long a claimed delivery is hidden from other processes
+	DeliveryLease time.Duration
+	BatchSize     int
+}
+
+// WebhookService manages webhook subscriptions and delivers signed events to
+// them. Events are written to a delivery log first and sent by the delivery
+// loop, which retries failures with backoff.
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	sender      *webhooksender.Sender
+	opts        WebhookOptions
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	sender *webhooksender.Sender,
+	opts WebhookOptions,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		opts:        opts,
+	}
+}
+
+func (s *WebhookService) Create(ctx context.Context, userID string, dto webhook.CreateWebhookDTO) (*webhook.WebhookResponseDTO, error) {
+	if err := webhooksender.ValidateURL(dto.URL); err != nil {
+		return nil, err
+	}
+
+	secret := ""
+	if dto.Secret != nil {
+		secret = *dto.Secret
+	} else {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			logger.Error(err, "failed to generate webhook secret")
+			return nil, errors.New("unable to create webhook")
+		}
+		secret = generated
+	}
+
+	created, err := s.webhookRepo.Create(ctx, &webhook.WebhookSubscription{
+		URL:        dto.URL,
+		Secret:     secret,
+		EventTypes: dto.EventTypes,
+		Active:     true,
+		CreatedBy:  &userID,
+	})
+	if err != nil {
+		logger.Error(err, "failed to create webhook")
+		return nil, errors.New("unable to create webhook")
+	}
+
+	res := webhook.MapWebhookToDTO(*created)
+	res.Secret = &secret
+	return &res, nil
+}
+
+func (s *WebhookService) GetByID(ctx context.Context, id string) (*webhook.WebhookResponseDTO, error) {
+	w, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := webhook.MapWebhookToDTO(*w)
+	return &res, nil
+}
+
+func (s *WebhookService) GetAll(ctx context.Context, limit, offset int) ([]webhook.WebhookResponseDTO, int64, error) {
+	webhooks, err := s.webhookRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		logger.Error(err, "failed to get webhooks")
+		return nil, 0, errors.New("unable to retrieve webhooks")
+	}
+
+	total, err := s.webhookRepo.Count(ctx)
+	if err != nil {
+		logger.Error(err, "failed to count webhooks")
+		return nil, 0, errors.New("unable to count webhooks")
+	}
+
+	return webhook.MapWebhooksToDTOs(webhooks), total, nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, id string, dto webhook.UpdateWebhookDTO) (*webhook.WebhookResponseDTO, error) {
+	w, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.URL != nil {
+		if err := webhooksender.ValidateURL(*dto.URL); err != nil {
+			return nil, err
+		}
+		w.URL = *dto.URL
+	}
+	if dto.Secret != nil {
+		w.Secret = *dto.Secret
+	}
+	if dto.EventTypes != nil {
+		w.EventTypes = dto.EventTypes
+	}
+	if dto.Active != nil {
+		w.Active = *dto.Active
+	}
+
+	updated, err := s.webhookRepo.Update(ctx, w)
+	if err != nil {
+		logger.Error(err, "failed to update webhook")
+		return nil, errors.New("unable to update webhook")
+	}
+
+	res := webhook.MapWebhookToDTO(*updated)
+	return &res, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id string) error {
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWebhookNotFound
+		}
+		logger.Error(err, "failed to delete webhook")
+		return errors.New("unable to delete webhook")
+	}
+	return nil
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, id string, limit, offset int) ([]webhook.WebhookDeliveryResponseDTO, int64, error) {
+	if _, err := s.getSubscription(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, err := s.webhookRepo.GetDeliveriesBySubscriptionID(ctx, id, limit, offset)
+	if err != nil {
+		logger.Error(err, "failed to get webhook deliveries")
+		return nil, 0, errors.New("unable to retrieve webhook deliveries")
+	}
+
+	total, err := s.webhookRepo.CountDeliveriesBySubscriptionID(ctx, id)
+	if err != nil {
+		logger.Error(err, "failed to count webhook deliveries")
+		return nil, 0, errors.New("unable to count webhook deliveries")
+	}
+
+	return webhook.MapDeliveriesToDTOs(deliveries), total, nil
+}
+
+// Redeliver sends the payload of an earlier delivery again as a new delivery
+// and returns it after the first attempt. A failed attempt is retried by the
+// delivery loop like any other delivery.
+func (s *WebhookService) Redeliver(ctx context.Context, id, deliveryID string) (*webhook.WebhookDeliveryResponseDTO, error) {
+	sub, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil || original.SubscriptionID != sub.ID {
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook delivery not found")
+		}
+		logger.Error(err, "failed to get webhook delivery")
+		return nil, errors.New("unable to retrieve webhook delivery")
+	}
+
+	// Hidden from the delivery loop while this request makes the first attempt
+	hiddenUntil := time.Now().Add(s.opts.DeliveryLease)
+	deliveries := []webhook.WebhookDelivery{{
+		SubscriptionID: sub.ID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         webhook.DeliveryStatusPending,
+		NextAttemptAt:  &hiddenUntil,
+		RedeliveryOf:   &original.ID,
+	}}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		logger.Error(err, "failed to create webhook redelivery")
+		return nil, errors.New("unable to redeliver webhook")
+	}
+
+	delivered := s.deliver(ctx, sub, &deliveries[0])
+	res := webhook.MapDeliveryToDTO(*delivered)
+	return &res, nil
+}
+
+// Dispatch records one delivery per active subscription listening to
+// eventType. The deliveries are sent by the delivery loop.
+func (s *WebhookService) Dispatch(ctx context.Context, eventType string, data any) {
+	if s == nil {
+		return
+	}
+
+	subscriptions, err := s.webhookRepo.GetActive(ctx)
+	if err != nil {
+		logger.Error(err, "failed to get webhook subscriptions")
+		return
+	}
+
+	payload, err := json.Marshal(webhook.EventPayloadDTO{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		logger.Error(err, "failed to encode webhook payload")
+		return
+	}
+
+	now := time.Now()
+	deliveries := make([]webhook.WebhookDelivery, 0)
+	for _, sub := range subscriptions {
+		if !sub.Subscribes(eventType) {
+			continue
+		}
+		deliveries = append(deliveries, webhook.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         webhook.DeliveryStatusPending,
+			NextAttemptAt:  &now,
+		})
+	}
+
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to record %s webhook deliveries", eventType))
+	}
+}
+
+// RunDeliveryLoop sends due deliveries until ctx is cancelled
+func (s *WebhookService) RunDeliveryLoop(ctx context.Context) {
+	logger.Info("Webhook delivery loop started")
+
+	ticker := time.NewTicker(s.opts.DeliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Webhook delivery loop stopped")
+			return
+		case <-ticker.C:
+			s.deliverDue(ctx)
+		}
+	}
+}
+
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, s.opts.BatchSize, s.opts.DeliveryLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error(err, "failed to claim webhook deliveries")
+		}
+		return
+	}
+
+	subscriptions := make(map[string]*webhook.WebhookSubscription)
+	for i := range deliveries {
+		d := &deliveries[i]
+
+		sub, ok := subscriptions[d.SubscriptionID]
+		if !ok {
+			sub, err = s.webhookRepo.GetByID(ctx, d.SubscriptionID)
+			if err != nil {
+				logger.Error(err, fmt.Sprintf("failed to load webhook subscription %s", d.SubscriptionID))
+				continue
+			}
+			subscriptions[d.SubscriptionID] = sub
+		}
+
+		s.deliver(ctx, sub, d)
+	}
+}
+
+// deliver makes one attempt and records its outcome on the delivery
+func (s *WebhookService) deliver(ctx context.Context, sub *webhook.WebhookSubscription, d *webhook.WebhookDelivery) *webhook.WebhookDelivery {
+	if !sub.Active {
+		message := "subscription is inactive"
+		d.Status = webhook.DeliveryStatusFailed
+		d.LastError = &message
+		d.NextAttemptAt = nil
+		return s.saveDelivery(ctx, d)
+	}
+
+	d.Attempts++
+	statusCode, err := s.sender.Send(ctx, sub.URL, sub.Secret, d.ID, d.EventType, []byte(d.Payload))
+	if statusCode != 0 {
+		d.LastStatusCode = &statusCode
+	}
+
+	if err == nil {
+		now := time.Now()
+		d.Status = webhook.DeliveryStatusSucceeded
+		d.DeliveredAt = &now
+		d.LastError = nil
+		d.NextAttemptAt = nil
+		return s.saveDelivery(ctx, d)
+	}
+
+	message := err.Error()
+	d.LastError = &message
+	if s.opts.RetryPolicy.ShouldRetry(d.Attempts) {
+		next := s.opts.RetryPolicy.NextAttemptAt(d.Attempts, time.Now())
+		d.Status = webhook.DeliveryStatusPending
+		d.NextAttemptAt = &next
+	} else {
+		d.Status = webhook.DeliveryStatusFailed
+		d.NextAttemptAt = nil
+		logger.Warn(fmt.Sprintf("Webhook delivery %s to %s failed after %d attempts: %v", d.ID, sub.URL, d.Attempts, err))
+	}
+	return s.saveDelivery(ctx, d)
+}
+
+func (s *WebhookService) saveDelivery(ctx context.Context, d *webhook.WebhookDelivery) *webhook.WebhookDelivery {
+	if _, err := s.webhookRepo.UpdateDelivery(ctx, d); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to record webhook delivery %s", d.ID))
+	}
+	return d
+}
+
+func (s *WebhookService) getSubscription(ctx context.Context, id string) (*webhook.WebhookSubscription, error) {
+	w, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		logger.Error(err, "failed to get webhook")
+		return nil, errors.New("unable to retrieve webhook")
+	}
+	return w, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
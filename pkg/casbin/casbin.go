@@ -48,6 +48,9 @@ func InitializeDefaultPolicies(enforcer *casbin.Enforcer) error {
 		{"admin", "novel", "update"},
 		{"admin", "novel", "delete"},
 
+		{"admin", "novel_import", "create"},
+		{"admin", "novel_import", "read"},
+
 		{"admin", "novel_translation", "create"},
 		{"admin", "novel_translation", "read"},
 		{"admin", "novel_translation", "update"},
@@ -62,12 +65,31 @@ func InitializeDefaultPolicies(enforcer *casbin.Enforcer) error {
 		{"admin", "chapter_translation", "read"},
 		{"admin", "chapter_translation", "update"},
 		{"admin", "chapter_translation", "delete"},
+		{"admin", "chapter_translation", "review"},
+		{"admin", "chapter_translation", "assign"},
 
 		{"admin", "translation_job", "create"},
 		{"admin", "translation_job", "read"},
 		{"admin", "translation_job", "list"},
 		{"admin", "translation_job", "update"},
 		{"admin", "translation_job", "delete"},
+		{"admin", "translation_job", "prioritize"},
+		{"admin", "translation_job", "publish"},
+
+		{"admin", "webhook", "create"},
+		{"admin", "webhook", "read"},
+		{"admin", "webhook", "list"},
+		{"admin", "webhook", "update"},
+		{"admin", "webhook", "delete"},
+
+		{"admin", "glossary", "create"},
+		{"admin", "glossary", "read"},
+		{"admin", "glossary", "list"},
+		{"admin", "glossary", "update"},
+		{"admin", "glossary", "delete"},
+
+		{"admin", "translation_memory", "create"},
+		{"admin", "translation_memory", "read"},
 
 		// ============ USER ROLE ============
 		// Basic user can read their own profile
@@ -86,6 +108,9 @@ func InitializeDefaultPolicies(enforcer *casbin.Enforcer) error {
 		{"author", "novel", "update"},
 		{"author", "novel", "delete"},
 
+		{"author", "novel_import", "create"},
+		{"author", "novel_import", "read"},
+
 		{"author", "chapter", "create"},
 		{"author", "chapter", "read"},
 		{"author", "chapter", "update"},
@@ -98,6 +123,16 @@ func InitializeDefaultPolicies(enforcer *casbin.Enforcer) error {
 		{"author", "chapter_translation", "create"},
 		{"author", "chapter_translation", "update"},
 		{"author", "chapter_translation", "delete"},
+		{"author", "chapter_translation", "assign"},
+
+		{"author", "glossary", "create"},
+		{"author", "glossary", "read"},
+		{"author", "glossary", "list"},
+		{"author", "glossary", "update"},
+		{"author", "glossary", "delete"},
+
+		{"author", "translation_memory", "create"},
+		{"author", "translation_memory", "read"},
 
 		// ============ TRANSLATOR ROLE ============
 		// Translator can manage translations only
@@ -110,6 +145,20 @@ func InitializeDefaultPolicies(enforcer *casbin.Enforcer) error {
 		{"translator", "chapter_translation", "read"},
 		{"translator", "chapter_translation", "update"},
 		{"translator", "chapter_translation", "delete"},
+		{"translator", "chapter_translation", "review"},
+
+		{"translator", "glossary", "create"},
+		{"translator", "glossary", "read"},
+		{"translator", "glossary", "list"},
+		{"translator", "glossary", "update"},
+		{"translator", "glossary", "delete"},
+
+		{"translator", "translation_memory", "create"},
+		{"translator", "translation_memory", "read"},
+
+		// Translators check and publish staged job results; listing jobs and
+		// following their events stays with admins
+		{"translator", "translation_job", "publish"},
 
 		// Translators need to read novels and chapters to translate them
 		{"translator", "novel", "read"},
@@ -125,6 +174,17 @@ func InitializeDefaultPolicies(enforcer *casbin.Enforcer) error {
 		}
 	}
 
+	// Policies granted by earlier versions that are too broad. Removing them
+	// here keeps existing databases in line with the list above.
+	revoked := [][]string{
+		{"translator", "translation_job", "read"},
+	}
+	for _, policy := range revoked {
+		if _, err := enforcer.RemovePolicy(policy); err != nil {
+			log.Printf("Warning: Failed to remove policy %v: %v", policy, err)
+		}
+	}
+
 	// Save policies to database
 	if err := enforcer.SavePolicy(); err != nil {
 		return err
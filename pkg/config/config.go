@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +16,14 @@ type Config struct {
 	Casbin   CasbinConfig
 	Media    MediaConfig
 	Redis    RedisConfig
+
+	Worker     WorkerConfig
+	Memory     TranslationMemoryConfig
+	QA         QAConfig
+	Quota      QuotaConfig
+	Translator TranslatorConfig
+	Webhook    WebhookConfig
+	Import     ImportConfig
 }
 
 type ServerConfig struct {
@@ -51,6 +60,88 @@ type RedisConfig struct {
 	QueueName string
 }
 
+type TranslatorConfig struct {
+	// Provider selects a backend registered in pkg/translator (e.g. "stub")
+	Provider string
+	BaseURL  string
+	APIKey   string
+}
+
+type WebhookConfig struct {
+	// TimeoutSeconds bounds a single delivery request
+	TimeoutSeconds int
+	// MaxAttempts is how often a delivery is tried before it is marked FAILED
+	MaxAttempts int
+	// RetryBackoffSeconds is the delay before the first retry; it doubles per attempt
+	RetryBackoffSeconds    int
+	RetryMaxBackoffSeconds int
+	// DeliveryIntervalSeconds is how often the worker sends due deliveries
+	DeliveryIntervalSeconds int
+}
+
+type ImportConfig struct {
+	// PollIntervalSeconds is how often the worker looks for queued EPUB imports
+	PollIntervalSeconds int
+	// LeaseSeconds is how long an import may go without progress before
+	// another worker resumes it
+	LeaseSeconds int
+	// ChunkSize is how many chapters are saved per transaction
+	ChunkSize int
+	// MaxAttempts is how often an interrupted import is resumed before it is marked FAILED
+	MaxAttempts int
+}
+
+type TranslationMemoryConfig struct {
+	// FuzzyMinPercent is the lowest similarity (0-100) reported as a fuzzy
+	// match; 0 disables fuzzy lookups
+	FuzzyMinPercent int
+}
+
+type QAConfig struct {
+	// MaxErrors is how many QA errors a job may have before it fails; -1 never fails
+	MaxErrors int
+	// MaxWarnings is how many QA warnings a job may have before it is flagged; -1 never flags
+	MaxWarnings int
+}
+
+type QuotaConfig struct {
+	// Default applies to users none of whose roles is listed in Roles
+	Default QuotaLimits
+	// Roles overrides the default limits per role name
+	Roles map[string]QuotaLimits
+	// Global caps all users together
+	Global QuotaLimits
+}
+
+// QuotaLimits caps translation job volume; -1 disables a limit
+type QuotaLimits struct {
+	DailyCharacters   int
+	MonthlyCharacters int
+	MaxActiveJobs     int
+}
+
+type WorkerConfig struct {
+	// ID identifies the worker as a lease owner; defaults to hostname-pid
+	ID string
+	// PollTimeoutSeconds is how long a worker blocks on the queue before looping
+	PollTimeoutSeconds int
+	// LeaseSeconds is how long a claimed subtask stays leased without a heartbeat
+	LeaseSeconds int
+	// HeartbeatSeconds is how often a worker renews the lease it holds
+	HeartbeatSeconds int
+	// ReaperIntervalSeconds is how often expired leases are returned to PENDING
+	ReaperIntervalSeconds int
+	// MaxAttempts is how often a subtask is tried before it is dead-lettered
+	MaxAttempts int
+	// RetryBackoffSeconds is the delay before the first retry; it doubles per attempt
+	RetryBackoffSeconds int
+	// RetryMaxBackoffSeconds caps the retry delay
+	RetryMaxBackoffSeconds int
+	// TurnSubtasks is how many subtasks of a job a worker processes before
+	// moving on to the next job in the queue
+	TurnSubtasks int
+}
+
 func Load() (*Config, error) {
 	// Parse media TTL (seconds)
 	var imgbbTTL uint64 = 0
@@ -67,7 +158,7 @@ func Load() (*Config, error) {
 		expirationHours = 24
 	}
 
-	return &Config{
+	cfg := &Config{
 		Server: ServerConfig{
 			Port: getEnv("SERVER_PORT", "8080"),
 			Host: getEnv("SERVER_HOST", "localhost"),
@@ -96,7 +187,89 @@ func Load() (*Config, error) {
 			URL:       getEnv("REDIS_URL", "redis://localhost:6379/0"),
 			QueueName: getEnv("TRANSLATION_QUEUE", "translation_jobs"),
 		},
-	}, nil
+		Worker: WorkerConfig{
+			ID:                     getEnv("WORKER_ID", ""),
+			PollTimeoutSeconds:     getEnvInt("WORKER_POLL_TIMEOUT_SECONDS", 5),
+			LeaseSeconds:           getEnvInt("WORKER_LEASE_SECONDS", 60),
+			HeartbeatSeconds:       getEnvInt("WORKER_HEARTBEAT_SECONDS", 15),
+			ReaperIntervalSeconds:  getEnvInt("WORKER_REAPER_INTERVAL_SECONDS", 30),
+			MaxAttempts:            getEnvInt("WORKER_MAX_ATTEMPTS", 3),
+			RetryBackoffSeconds:    getEnvInt("WORKER_RETRY_BACKOFF_SECONDS", 30),
+			RetryMaxBackoffSeconds: getEnvInt("WORKER_RETRY_MAX_BACKOFF_SECONDS", 600),
+			TurnSubtasks:           getEnvInt("WORKER_TURN_SUBTASKS", 5),
+		},
+		Memory: TranslationMemoryConfig{
+			FuzzyMinPercent: getEnvInt("TM_FUZZY_MIN_PERCENT", 75),
+		},
+		QA: QAConfig{
+			MaxErrors:   getEnvInt("QA_MAX_ERRORS", -1),
+			MaxWarnings: getEnvInt("QA_MAX_WARNINGS", -1),
+		},
+		Quota: QuotaConfig{
+			Default: QuotaLimits{
+				DailyCharacters:   getEnvInt("QUOTA_DAILY_CHARACTERS", -1),
+				MonthlyCharacters: getEnvInt("QUOTA_MONTHLY_CHARACTERS", -1),
+				MaxActiveJobs:     getEnvInt("QUOTA_MAX_ACTIVE_JOBS", -1),
+			},
+			Roles: getEnvQuotaRoles("QUOTA_ROLE_LIMITS"),
+			Global: QuotaLimits{
+				DailyCharacters:   getEnvInt("QUOTA_GLOBAL_DAILY_CHARACTERS", -1),
+				MonthlyCharacters: getEnvInt("QUOTA_GLOBAL_MONTHLY_CHARACTERS", -1),
+				MaxActiveJobs:     getEnvInt("QUOTA_GLOBAL_MAX_ACTIVE_JOBS", -1),
+			},
+		},
+		Webhook: WebhookConfig{
+			TimeoutSeconds:          getEnvInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+			MaxAttempts:             getEnvInt("WEBHOOK_MAX_ATTEMPTS", 6),
+			RetryBackoffSeconds:     getEnvInt("WEBHOOK_RETRY_BACKOFF_SECONDS", 30),
+			RetryMaxBackoffSeconds:  getEnvInt("WEBHOOK_RETRY_MAX_BACKOFF_SECONDS", 3600),
+			DeliveryIntervalSeconds: getEnvInt("WEBHOOK_DELIVERY_INTERVAL_SECONDS", 5),
+		},
+		Import: ImportConfig{
+			PollIntervalSeconds: getEnvInt("IMPORT_POLL_INTERVAL_SECONDS", 5),
+			LeaseSeconds:        getEnvInt("IMPORT_LEASE_SECONDS", 300),
+			ChunkSize:           getEnvInt("IMPORT_CHUNK_SIZE", 100),
+			MaxAttempts:         getEnvInt("IMPORT_MAX_ATTEMPTS", 3),
+		},
+		Translator: TranslatorConfig{
+			Provider: getEnv("TRANSLATOR_PROVIDER", "stub"),
+			BaseURL:  getEnv("TRANSLATOR_BASE_URL", ""),
+			APIKey:   getEnv("TRANSLATOR_API_KEY", ""),
+		},
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validate rejects settings the services cannot run with, such as intervals
+// of zero that would make their tickers panic
+func (c *Config) validate() error {
+	positive := []struct {
+		key   string
+		value int
+	}{
+		{"WORKER_POLL_TIMEOUT_SECONDS", c.Worker.PollTimeoutSeconds},
+		{"WORKER_LEASE_SECONDS", c.Worker.LeaseSeconds},
+		{"WORKER_HEARTBEAT_SECONDS", c.Worker.HeartbeatSeconds},
+		{"WORKER_REAPER_INTERVAL_SECONDS", c.Worker.ReaperIntervalSeconds},
+		{"WORKER_MAX_ATTEMPTS", c.Worker.MaxAttempts},
+		{"WEBHOOK_TIMEOUT_SECONDS", c.Webhook.TimeoutSeconds},
+		{"WEBHOOK_MAX_ATTEMPTS", c.Webhook.MaxAttempts},
+		{"WEBHOOK_DELIVERY_INTERVAL_SECONDS", c.Webhook.DeliveryIntervalSeconds},
+		{"IMPORT_POLL_INTERVAL_SECONDS", c.Import.PollIntervalSeconds},
+		{"IMPORT_LEASE_SECONDS", c.Import.LeaseSeconds},
+		{"IMPORT_MAX_ATTEMPTS", c.Import.MaxAttempts},
+	}
+	for _, p := range positive {
+		if p.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %d", p.key, p.value)
+		}
+	}
+
+	return nil
 }
 
 func (c *DatabaseConfig) DSN() string {
@@ -112,3 +285,45 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	n, err := strconv.Atoi(getEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil {
+		return defaultValue
+	}
+	return n
+}
+
+// getEnvQuotaRoles parses per-role limits written as
+// "role:daily:monthly:active" and separated by commas, e.g.
+// "admin:-1:-1:-1,translator:200000:3000000:3". Malformed entries are skipped.
+func getEnvQuotaRoles(key string) map[string]QuotaLimits {
+	roles := make(map[string]QuotaLimits)
+	for _, entry := range strings.Split(getEnv(key, ""), ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 4 || parts[0] == "" {
+			continue
+		}
+
+		values := make([]int, 3)
+		valid := true
+		for i, p := range parts[1:] {
+			n, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				valid = false
+				break
+			}
+			values[i] = n
+		}
+		if !valid {
+			continue
+		}
+
+		roles[parts[0]] = QuotaLimits{
+			DailyCharacters:   values[0],
+			MonthlyCharacters: values[1],
+			MaxActiveJobs:     values[2],
+		}
+	}
+	return roles
+}
@@ -4,17 +4,22 @@ import (
 	"fmt"
 	"log"
 	"simple-go/internal/domain/chapter"
+	epubimport "simple-go/internal/domain/epub_import"
 	"simple-go/internal/domain/genre"
+	"simple-go/internal/domain/glossary"
 	"simple-go/internal/domain/job"
 	"simple-go/internal/domain/media"
 	"simple-go/internal/domain/novel"
 	novelgenre "simple-go/internal/domain/novel_genre"
 	noveltag "simple-go/internal/domain/novel_tag"
+	"simple-go/internal/domain/quota"
 	"simple-go/internal/domain/role"
 	"simple-go/internal/domain/tag"
+	translationmemory "simple-go/internal/domain/translation_memory"
 	"simple-go/internal/domain/user"
 	userrole "simple-go/internal/domain/user_role"
 	"simple-go/internal/domain/volume"
+	"simple-go/internal/domain/webhook"
 
 	"simple-go/pkg/config"
 
@@ -74,19 +79,53 @@ func migrateDatabase(db *gorm.DB) error {
 		&volume.VolumeTranslation{},
 		&chapter.Chapter{},
 		&chapter.ChapterTranslation{},
+		&chapter.ChapterAlignment{},
+		&chapter.ChapterTranslationRevision{},
+		&job.TranslationJobBatch{},
 		&job.TranslationJob{},
 		&job.TranslationSubtask{},
+		&webhook.WebhookSubscription{},
+		&webhook.WebhookDelivery{},
+		&glossary.GlossaryTerm{},
+		&quota.Usage{},
+		&translationmemory.TranslationMemoryEntry{},
+		&epubimport.EpubImport{},
+		&epubimport.EpubImportFile{},
 	)
 
 	if err != nil {
 		return err
 	}
 
+	if err := migrateTranslationJobIndexes(db); err != nil {
+		return err
+	}
+
 	log.Println("Database migrations completed successfully")
 
 	return nil
 }
 
+// migrateTranslationJobIndexes replaces the unique index that allowed one job
+// per novel and language, so that finished jobs are kept when a new one is
+// created. At most one job per pair may still be active.
+func migrateTranslationJobIndexes(db *gorm.DB) error {
+	if err := db.Exec(`DROP INDEX IF EXISTS idx_translation_job_novel_lang;`).Error; err != nil {
+		return fmt.Errorf("failed to drop translation job index: %w", err)
+	}
+
+	err := db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_translation_jobs_active_novel_lang
+		ON translation_jobs (novel_id, target_lang)
+		WHERE status IN ('PENDING', 'IN_PROGRESS');
+	`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create active translation job index: %w", err)
+	}
+
+	return nil
+}
+
 type TrigramIndex struct {
 	Table  string
 	Column string
@@ -95,6 +134,7 @@ type TrigramIndex struct {
 var trigramIndexes = []TrigramIndex{
 	{"novel_translations", "title"},
 	{"users", "username"},
+	{"translation_memory_entries", "source_text"},
 }
 
 func addTrigramIndexes(db *gorm.DB) error {
@@ -0,0 +1,151 @@
+package epub
+
+import (
+	"math"
+	"unicode/utf8"
+)
+
+// Alignment pairs source segments with the target segments that translate
+// them. Indexes refer to the slices passed to AlignSegments; either side may
+// be empty when a segment has no counterpart.
+type Alignment struct {
+	Source []int `json:"source"`
+	Target []int `json:"target"`
+}
+
+// bead is one allowed alignment shape with its fixed cost. The costs follow
+// Gale & Church: 1-1 is by far the most common, merges and splits are rarer
+// and dropped segments rarer still.
+type bead struct {
+	source, target int
+	penalty        float64
+}
+
+var beads = []bead{
+	{1, 1, 0},
+	{1, 2, 2.3},
+	{2, 1, 2.3},
+	{1, 0, 4.6},
+	{0, 1, 4.6},
+	{2, 2, 4.6},
+}
+
+// alignBand is how far, in segments, an alignment may stray from the
+// diagonal. It bounds the work to O((n+m)·alignBand) instead of O(n·m);
+// translations rarely drift further than a few merged or split paragraphs.
+const alignBand = 32
+
+// AlignSegments aligns translated segments with their source using segment
+// lengths only (Gale & Church). Segment lists of the same length, which is
+// what the translation worker produces, are paired one to one.
+func AlignSegments(source, target []string) []Alignment {
+	n, m := len(source), len(target)
+	if n == m || n == 0 || m == 0 {
+		alignments := make([]Alignment, 0, max(n, m))
+		for i := 0; i < max(n, m); i++ {
+			a := Alignment{Source: []int{}, Target: []int{}}
+			if i < n {
+				a.Source = append(a.Source, i)
+			}
+			if i < m {
+				a.Target = append(a.Target, i)
+			}
+			alignments = append(alignments, a)
+		}
+		return alignments
+	}
+
+	sourceLen, sourceTotal := segmentLengths(source)
+	targetLen, targetTotal := segmentLengths(target)
+
+	// Languages differ in how many characters they need; compare target
+	// lengths against source lengths scaled by the overall ratio
+	ratio := 1.0
+	if sourceTotal > 0 && targetTotal > 0 {
+		ratio = float64(targetTotal) / float64(sourceTotal)
+	}
+
+	// Row i only holds the columns within band of the diagonal through
+	// (0,0) and (n,m); cells outside it cost infinity. The band grows with
+	// the segment ratio so that neighbouring rows always overlap.
+	band := max(alignBand, m/n+1)
+	lo := make([]int, n+1)
+	cost := make([][]float64, n+1)
+	back := make([][]int, n+1)
+	for i := range cost {
+		center := i * m / n
+		lo[i] = max(0, center-band)
+		hi := min(m, center+band+1)
+		cost[i] = make([]float64, hi-lo[i]+1)
+		back[i] = make([]int, hi-lo[i]+1)
+		for j := range cost[i] {
+			cost[i][j] = math.Inf(1)
+		}
+	}
+	cost[0][0] = 0
+	at := func(i, j int) float64 {
+		if i < 0 || j < lo[i] || j-lo[i] >= len(cost[i]) {
+			return math.Inf(1)
+		}
+		return cost[i][j-lo[i]]
+	}
+
+	for i := 0; i <= n; i++ {
+		for j := lo[i]; j-lo[i] < len(cost[i]); j++ {
+			if i == 0 && j == 0 {
+				continue
+			}
+			for b, bd := range beads {
+				pi, pj := i-bd.source, j-bd.target
+				prev := at(pi, pj)
+				if math.IsInf(prev, 1) {
+					continue
+				}
+				ls := sourceLen[i] - sourceLen[pi]
+				lt := targetLen[j] - targetLen[pj]
+				c := prev + bd.penalty + lengthCost(ls, lt, ratio)
+				if c < cost[i][j-lo[i]] {
+					cost[i][j-lo[i]] = c
+					back[i][j-lo[i]] = b
+				}
+			}
+		}
+	}
+
+	alignments := make([]Alignment, 0, max(n, m))
+	for i, j := n, m; i > 0 || j > 0; {
+		bd := beads[back[i][j-lo[i]]]
+		a := Alignment{Source: []int{}, Target: []int{}}
+		for k := i - bd.source; k < i; k++ {
+			a.Source = append(a.Source, k)
+		}
+		for k := j - bd.target; k < j; k++ {
+			a.Target = append(a.Target, k)
+		}
+		alignments = append(alignments, a)
+		i, j = i-bd.source, j-bd.target
+	}
+
+	// Built back to front
+	for l, r := 0, len(alignments)-1; l < r; l, r = l+1, r-1 {
+		alignments[l], alignments[r] = alignments[r], alignments[l]
+	}
+	return alignments
+}
+
+// segmentLengths returns the prefix sums of the visible text lengths
+func segmentLengths(segments []string) ([]int, int) {
+	sums := make([]int, len(segments)+1)
+	for i, seg := range segments {
+		sums[i+1] = sums[i] + utf8.RuneCountInString(PlainText(seg))
+	}
+	return sums, sums[len(segments)]
+}
+
+// lengthCost grows with the squared, variance-normalised difference between
+// the expected and the actual target length
+func lengthCost(sourceLen, targetLen int, ratio float64) float64 {
+	expected := float64(sourceLen) * ratio
+	delta := (float64(targetLen) - expected) / math.Sqrt(expected+float64(targetLen)+1)
+	return delta * delta / 2
+}
@@ -0,0 +1,55 @@
+package epub
+
+import (
+	"strings"
+	"testing"
+)
+
+// checkAlignment fails unless every segment of both sides is used exactly
+// once and in order
+func checkAlignment(t *testing.T, alignments []Alignment, n, m int) {
+	t.Helper()
+	nextSource, nextTarget := 0, 0
+	for _, a := range alignments {
+		for _, i := range a.Source {
+			if i != nextSource {
+				t.Fatalf("source segment %d out of order, want %d", i, nextSource)
+			}
+			nextSource++
+		}
+		for _, j := range a.Target {
+			if j != nextTarget {
+				t.Fatalf("target segment %d out of order, want %d", j, nextTarget)
+			}
+			nextTarget++
+		}
+	}
+	if nextSource != n || nextTarget != m {
+		t.Fatalf("aligned %d source and %d target segments, want %d and %d", nextSource, nextTarget, n, m)
+	}
+}
+
+func TestAlignSegmentsFindsSplitParagraph(t *testing.T) {
+	source := []string{"short one", strings.Repeat("long ", 40), "another short"}
+	target := []string{"short one", strings.Repeat("long ", 20), strings.Repeat("long ", 20), "another short"}
+
+	alignments := AlignSegments(source, target)
+	checkAlignment(t, alignments, len(source), len(target))
+	if len(alignments) != 3 || len(alignments[1].Target) != 2 {
+		t.Fatalf("alignments = %v, want the long paragraph split in two", alignments)
+	}
+}
+
+func TestAlignSegmentsCoversLopsidedInputs(t *testing.T) {
+	segments := func(n int) []string {
+		s := make([]string, n)
+		for i := range s {
+			s[i] = strings.Repeat("x", 10+i%7)
+		}
+		return s
+	}
+
+	for _, size := range [][2]int{{2000, 1900}, {3, 400}, {400, 3}, {0, 5}, {5, 0}} {
+		checkAlignment(t, AlignSegments(segments(size[0]), segments(size[1])), size[0], size[1])
+	}
+}
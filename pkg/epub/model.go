@@ -16,6 +16,8 @@ type OPFManifestItem struct {
 	ID        string `xml:"id,attr"`
 	Href      string `xml:"href,attr"`
 	MediaType string `xml:"media-type,attr"`
+	// Properties marks special items such as the EPUB 3 navigation document
+	Properties string `xml:"properties,attr,omitempty"`
 }
 
 type OPFItemRef struct {
@@ -23,7 +25,7 @@ type OPFItemRef struct {
 }
 
 type OPFSpine struct {
-	Toc      string       `xml:"toc,attr"`
+	Toc      string       `xml:"toc,attr,omitempty"`
 	ItemRefs []OPFItemRef `xml:"itemref"`
 }
 
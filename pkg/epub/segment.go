@@ -0,0 +1,162 @@
+package epub
+
+import (
+	"html"
+	"io"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// Segment is a piece of chapter HTML. Translatable segments hold the inner
+// HTML of one block element (or a run of bare text); everything else is
+// markup that is kept verbatim. Joining the segments of SegmentHTML gives
+// back the input unchanged.
+type Segment struct {
+	Text         string
+	Translatable bool
+}
+
+// segmentElements are the block elements whose content forms one segment
+var segmentElements = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"li": true, "dt": true, "dd": true, "blockquote": true, "pre": true,
+	"td": true, "th": true, "caption": true, "figcaption": true,
+}
+
+// SegmentHTML splits HTML content into block-level segments. Container
+// elements (div, section, ul, table, ...) stay in the markup around the
+// segments, and blocks without any text are not translatable.
+func SegmentHTML(content string) []Segment {
+	segments := make([]Segment, 0)
+	var markup, inner strings.Builder
+
+	flushMarkup := func() {
+		if markup.Len() > 0 {
+			segments = append(segments, Segment{Text: markup.String()})
+			markup.Reset()
+		}
+	}
+
+	// open is the block element being collected and depth counts nested
+	// elements of the same name inside it
+	open, depth := "", 0
+
+	z := xhtml.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			if z.Err() != io.EOF {
+				// Malformed input: keep the rest as markup
+				markup.Write(z.Raw())
+			}
+			break
+		}
+		raw := string(z.Raw())
+
+		if open != "" {
+			name, _ := z.TagName()
+			switch {
+			case tt == xhtml.StartTagToken && string(name) == open:
+				depth++
+			case tt == xhtml.EndTagToken && string(name) == open:
+				depth--
+			}
+
+			if depth > 0 {
+				inner.WriteString(raw)
+				continue
+			}
+
+			// Closing tag of the block
+			if hasText(inner.String()) {
+				flushMarkup()
+				segments = append(segments, Segment{Text: inner.String(), Translatable: true})
+			} else {
+				markup.WriteString(inner.String())
+			}
+			markup.WriteString(raw)
+			inner.Reset()
+			open = ""
+			continue
+		}
+
+		switch tt {
+		case xhtml.StartTagToken:
+			name, _ := z.TagName()
+			if segmentElements[string(name)] {
+				markup.WriteString(raw)
+				open, depth = string(name), 1
+				continue
+			}
+		case xhtml.TextToken:
+			if hasText(raw) {
+				// Bare text outside any block; keep surrounding whitespace as markup
+				trimmed := strings.TrimSpace(raw)
+				start := strings.Index(raw, trimmed)
+				markup.WriteString(raw[:start])
+				flushMarkup()
+				segments = append(segments, Segment{Text: trimmed, Translatable: true})
+				markup.WriteString(raw[start+len(trimmed):])
+				continue
+			}
+		}
+		markup.WriteString(raw)
+	}
+
+	// Unterminated block at the end of the input
+	if open != "" {
+		if hasText(inner.String()) {
+			flushMarkup()
+			segments = append(segments, Segment{Text: inner.String(), Translatable: true})
+		} else {
+			markup.WriteString(inner.String())
+		}
+	}
+	flushMarkup()
+
+	return segments
+}
+
+// JoinSegments reassembles segments into HTML
+func JoinSegments(segments []Segment) string {
+	var sb strings.Builder
+	for _, seg := range segments {
+		sb.WriteString(seg.Text)
+	}
+	return sb.String()
+}
+
+// TranslatableSegments returns the text of the translatable segments in order
+func TranslatableSegments(segments []Segment) []string {
+	texts := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		if seg.Translatable {
+			texts = append(texts, seg.Text)
+		}
+	}
+	return texts
+}
+
+// hasText reports whether an HTML fragment contains any visible text
+func hasText(fragment string) bool {
+	return PlainText(fragment) != ""
+}
+
+// PlainText returns the visible text of an HTML fragment, trimmed and with
+// entities decoded
+func PlainText(fragment string) string {
+	var sb strings.Builder
+	inTag := false
+	for _, r := range fragment {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			sb.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(html.UnescapeString(sb.String()))
+}
@@ -7,6 +7,9 @@ import (
 	"simple-go/pkg/logger"
 )
 
+// ErrUnsupportedSource is returned when no transformer recognizes an EPUB
+var ErrUnsupportedSource = errors.New("unsupported EPUB source format")
+
 type EpubTransformerFactory struct {
 	transformers []EpubTransformer
 }
@@ -16,6 +19,8 @@ func NewEpubTransformerFactory() *EpubTransformerFactory {
 		transformers: []EpubTransformer{
 			NewSource404NovelDownloaderTransformer(),
 			NewSourceDipubdLightnovelCrawlerTransformer(),
+			// Catch-all, keep it last
+			NewSourceGenericTransformer(),
 		},
 	}
 }
@@ -29,7 +34,7 @@ func (f *EpubTransformerFactory) DetectAndGetTransformer(content *epub.RawEpub)
 		}
 	}
 
-	return nil, errors.New("unsupported EPUB source format: no compatible transformer found")
+	return nil, fmt.Errorf("%w: no compatible transformer found", ErrUnsupportedSource)
 }
 
 // GetTransformerByType returns a transformer for a specific source type
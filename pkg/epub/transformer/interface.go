@@ -22,6 +22,13 @@ type EpubTransformer interface {
 	GetSourceType() EpubSourceType
 }
 
+// EpubStructureTransformer is implemented by transformers that derive volumes
+// and chapters from the same pass over the EPUB, so that callers needing both
+// do that work once
+type EpubStructureTransformer interface {
+	TransformToStructure(ctx context.Context, content *epub.RawEpub) ([]VolumeData, []ChapterData, error)
+}
+
 type NovelData struct {
 	Title            string
 	OriginalAuthor   string
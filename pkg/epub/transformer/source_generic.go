@@ -0,0 +1,729 @@
+package transformer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"simple-go/pkg/epub"
+	"simple-go/pkg/logger"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// SourceGenericTransformer handles EPUBs from any other source. Chapters
+// follow the spine, and the table of contents (the EPUB 3 nav document, or
+// the EPUB 2 NCX) names them and groups them into volumes: top-level entries
+// with nested entries become volumes, everything else a chapter. It accepts
+// every EPUB with a readable spine, so it must be registered last.
+type SourceGenericTransformer struct{}
+
+func NewSourceGenericTransformer() *SourceGenericTransformer {
+	return &SourceGenericTransformer{}
+}
+
+// tocEntry is one entry of the table of contents. Path is the content
+// document it points to, relative to the EPUB root, and Fragment the anchor
+// in it, if any.
+type tocEntry struct {
+	Title    string
+	Path     string
+	Fragment string
+	Children []tocEntry
+}
+
+// genericLayout is the structure derived from the spine and the table of
+// contents, shared by TransformToVolumes and TransformToChapters
+type genericLayout struct {
+	volumes  []VolumeData
+	chapters []ChapterData
+}
+
+// target is what a table of contents entry makes of the content it points
+// at: a chapter of a volume, or the volume's title page
+type target struct {
+	volume  int
+	title   string
+	chapter bool
+}
+
+// anchor is a table of contents entry pointing into a content document, at
+// fragment or, without one, at the start of the document
+type anchor struct {
+	fragment string
+	target
+}
+
+// targetIndex lists the entries pointing into each content document, in
+// table of contents order
+type targetIndex map[string][]anchor
+
+// add records tg for the place entry points at unless an earlier entry
+// claimed it. With chapterWins, a chapter entry takes a place over from a
+// volume entry.
+func (ti targetIndex) add(entry tocEntry, tg target, chapterWins bool) {
+	if entry.Path == "" {
+		return
+	}
+	anchors := ti[entry.Path]
+	for i := range anchors {
+		if anchors[i].fragment == entry.Fragment {
+			if chapterWins && tg.chapter && !anchors[i].chapter {
+				anchors[i].target = tg
+			}
+			return
+		}
+	}
+	ti[entry.Path] = append(anchors, anchor{fragment: entry.Fragment, target: tg})
+}
+
+// documentPart is a piece of a content document, along with the table of
+// contents entry it starts at, if any
+type documentPart struct {
+	target    *target
+	body      string
+	plainText string
+	// source is searched for a title when the entry has none
+	source string
+}
+
+func (t *SourceGenericTransformer) DetectSource(content *epub.RawEpub) bool {
+	opfPkg, err := parseRawOPF(content)
+	if err != nil {
+		return false
+	}
+	if len(opfPkg.Spine.ItemRefs) == 0 {
+		return false
+	}
+
+	logger.Info("No specific source detected, falling back to generic EPUB format")
+	return true
+}
+
+func (t *SourceGenericTransformer) GetSourceType() EpubSourceType {
+	return EpubSourceGeneric
+}
+
+func (t *SourceGenericTransformer) TransformToNovelData(ctx context.Context, content *epub.RawEpub) (*NovelData, error) {
+	data := &NovelData{
+		Tags: []string{},
+	}
+
+	opfPkg, err := parseRawOPF(content)
+	if err != nil {
+		logger.Error(err, "failed to parse OPF in generic transformer")
+		return data, nil
+	}
+
+	if len(opfPkg.Metadata.Title) > 0 {
+		data.Title = strings.TrimSpace(opfPkg.Metadata.Title[0])
+	}
+	if len(opfPkg.Metadata.Creator) > 0 {
+		data.OriginalAuthor = strings.Join(opfPkg.Metadata.Creator, ", ")
+	}
+	if len(opfPkg.Metadata.Language) > 0 {
+		data.OriginalLanguage = opfPkg.Metadata.Language[0]
+	}
+	if len(opfPkg.Metadata.Publisher) > 0 {
+		data.Publisher = opfPkg.Metadata.Publisher[0]
+	}
+	if len(opfPkg.Metadata.Description) > 0 {
+		data.Description = epub.ExtractText([]byte(strings.Join(opfPkg.Metadata.Description, " ")))
+	}
+	if opfPkg.Metadata.Subject != nil {
+		data.Tags = opfPkg.Metadata.Subject
+	}
+
+	if coverPath := findCoverPath(content, opfPkg); coverPath != "" {
+		data.CoverImage = content.RawFiles[coverPath]
+		logger.Info(fmt.Sprintf("Extracted cover image from: %s", coverPath))
+	}
+
+	return data, nil
+}
+
+func (t *SourceGenericTransformer) TransformToVolumes(ctx context.Context, content *epub.RawEpub) ([]VolumeData, error) {
+	volumes, _, err := t.TransformToStructure(ctx, content)
+	return volumes, err
+}
+
+func (t *SourceGenericTransformer) TransformToChapters(ctx context.Context, content *epub.RawEpub) ([]ChapterData, error) {
+	_, chapters, err := t.TransformToStructure(ctx, content)
+	return chapters, err
+}
+
+// TransformToStructure derives the volumes and chapters from one walk of the
+// spine and the table of contents
+func (t *SourceGenericTransformer) TransformToStructure(ctx context.Context, content *epub.RawEpub) ([]VolumeData, []ChapterData, error) {
+	layout, err := t.layout(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Info(fmt.Sprintf("Generic source: Extracted %d chapters in %d volumes", len(layout.chapters), len(layout.volumes)))
+	return layout.volumes, layout.chapters, nil
+}
+
+// layout walks the spine and assigns each content document to a volume and
+// chapter using the table of contents. A document several entries point into
+// is cut at their anchors. Documents the table of contents does not list
+// continue the chapter before them, as when a chapter is split over several
+// files; those before the first listed chapter are front matter and skipped.
+// Without a usable table of contents every document is a chapter of a single
+// virtual volume.
+func (t *SourceGenericTransformer) layout(content *epub.RawEpub) (*genericLayout, error) {
+	opfPkg, err := parseRawOPF(content)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestMap := make(map[string]epub.OPFManifestItem)
+	for _, item := range opfPkg.Manifest {
+		manifestMap[item.ID] = item
+	}
+
+	toc, tocPath := readTOC(content, opfPkg, manifestMap)
+
+	// Map content documents to the volume or chapter entry pointing at them
+	hierarchical := false
+	for _, entry := range toc {
+		if len(entry.Children) > 0 {
+			hierarchical = true
+			break
+		}
+	}
+
+	targets := make(targetIndex)
+	var volumes []VolumeData
+
+	if hierarchical {
+		current := 0
+		for _, entry := range toc {
+			if len(entry.Children) == 0 {
+				// Loose entries such as a prologue or afterword join the
+				// volume around them
+				targets.add(entry, target{volume: current, title: entry.Title, chapter: true}, false)
+				continue
+			}
+
+			volumes = append(volumes, VolumeData{Number: len(volumes) + 1, Title: entry.Title})
+			current = len(volumes) - 1
+			targets.add(entry, target{volume: current, title: entry.Title}, false)
+			for _, child := range flattenTOC(entry.Children) {
+				targets.add(child, target{volume: current, title: child.Title, chapter: true}, true)
+			}
+		}
+	} else {
+		volumes = []VolumeData{{Number: 1, Title: "Volume 1", IsVirtual: true}}
+		for _, entry := range flattenTOC(toc) {
+			targets.add(entry, target{volume: 0, title: entry.Title, chapter: true}, false)
+		}
+	}
+
+	type spineDocument struct {
+		item epub.OPFManifestItem
+		path string
+		raw  []byte
+	}
+	var documents []spineDocument
+	listed := false
+	for _, itemRef := range opfPkg.Spine.ItemRefs {
+		manifestItem, exists := manifestMap[itemRef.IDRef]
+		if !exists {
+			logger.Warn(fmt.Sprintf("Manifest item not found for spine ref: %s", itemRef.IDRef))
+			continue
+		}
+		if !strings.Contains(manifestItem.MediaType, "html") {
+			continue
+		}
+
+		fullPath := resolveHref(content.OPFPath, manifestItem.Href)
+		if fullPath == tocPath {
+			continue
+		}
+		raw, exists := content.RawFiles[fullPath]
+		if !exists {
+			logger.Warn(fmt.Sprintf("Content file not found: %s", fullPath))
+			continue
+		}
+
+		documents = append(documents, spineDocument{item: manifestItem, path: fullPath, raw: raw})
+		if len(targets[fullPath]) > 0 {
+			listed = true
+		}
+	}
+
+	// A table of contents pointing at none of the documents is ignored
+	if !listed {
+		if len(toc) > 0 {
+			logger.Info("Table of contents matches no spine document, using spine order only")
+		}
+		targets = targetIndex{}
+		volumes = []VolumeData{{Number: 1, Title: "Volume 1", IsVirtual: true}}
+	}
+
+	chapters := []ChapterData{}
+	orders := make([]int, len(volumes))
+	currentChapter := -1
+
+	for _, doc := range documents {
+		manifestItem, raw := doc.item, doc.raw
+
+		if !listed {
+			// No table of contents: every document but the cover is a chapter
+			if strings.Contains(strings.ToLower(manifestItem.Href), "cover") {
+				logger.Info(fmt.Sprintf("Skipping cover page in chapters: %s", manifestItem.Href))
+				continue
+			}
+			orders[0]++
+			chapters = append(chapters, ChapterData{
+				VolumeIndex: 0,
+				OrderNum:    orders[0],
+				Title:       extractChapterTitle(string(raw), orders[0]),
+				Content:     epub.ExtractBodyContent(raw),
+				PlainText:   epub.ExtractText(raw),
+			})
+			currentChapter = len(chapters) - 1
+			continue
+		}
+
+		for _, part := range splitDocument(raw, targets[doc.path]) {
+			switch tg := part.target; {
+			case tg != nil && tg.chapter:
+				orders[tg.volume]++
+				title := tg.title
+				if title == "" {
+					title = extractChapterTitle(part.source, orders[tg.volume])
+				}
+				chapters = append(chapters, ChapterData{
+					VolumeIndex: tg.volume,
+					OrderNum:    orders[tg.volume],
+					Title:       title,
+					Content:     part.body,
+					PlainText:   part.plainText,
+				})
+				currentChapter = len(chapters) - 1
+			case tg != nil:
+				// A volume's own title page is not a chapter, and does not
+				// continue the previous volume's last chapter either
+				currentChapter = -1
+			case currentChapter >= 0:
+				chapters[currentChapter].Content += "\n" + part.body
+				chapters[currentChapter].PlainText = strings.TrimSpace(chapters[currentChapter].PlainText + " " + part.plainText)
+			default:
+				logger.Info(fmt.Sprintf("Skipping front matter not listed in the table of contents: %s", manifestItem.Href))
+			}
+		}
+	}
+
+	if len(chapters) == 0 {
+		return nil, errors.New("no chapters found in EPUB spine")
+	}
+
+	return &genericLayout{volumes: compactVolumes(volumes, chapters), chapters: chapters}, nil
+}
+
+// splitDocument cuts a content document at the anchors table of contents
+// entries point at, so that chapters sharing a file each get their own part.
+// The part before the first anchor belongs to an entry pointing at the
+// document itself; without one it continues whatever came before, unless it
+// has no text. A single entry takes the whole document, and anchors missing
+// from the document are ignored.
+func splitDocument(raw []byte, anchors []anchor) []documentPart {
+	whole := documentPart{body: epub.ExtractBodyContent(raw), plainText: epub.ExtractText(raw), source: string(raw)}
+	if len(anchors) == 0 {
+		return []documentPart{whole}
+	}
+	if len(anchors) == 1 {
+		whole.target = &anchors[0].target
+		return []documentPart{whole}
+	}
+
+	var lead *target
+	fragments := make([]string, 0, len(anchors))
+	for i := range anchors {
+		if anchors[i].fragment == "" {
+			lead = &anchors[i].target
+		} else {
+			fragments = append(fragments, anchors[i].fragment)
+		}
+	}
+
+	type cut struct {
+		offset int
+		target *target
+	}
+	offsets := anchorOffsets(whole.body, fragments)
+	cuts := make([]cut, 0, len(anchors))
+	for i := range anchors {
+		if anchors[i].fragment == "" {
+			continue
+		}
+		offset, ok := offsets[anchors[i].fragment]
+		if !ok {
+			logger.Warn(fmt.Sprintf("Table of contents anchor #%s not found in its document", anchors[i].fragment))
+			continue
+		}
+		cuts = append(cuts, cut{offset: offset, target: &anchors[i].target})
+	}
+	if len(cuts) == 0 {
+		if lead == nil {
+			lead = &anchors[0].target
+		}
+		whole.target = lead
+		return []documentPart{whole}
+	}
+	sort.SliceStable(cuts, func(i, j int) bool { return cuts[i].offset < cuts[j].offset })
+
+	parts := make([]documentPart, 0, len(cuts)+1)
+	if head := newDocumentPart(lead, whole.body[:cuts[0].offset]); lead != nil || head.plainText != "" {
+		parts = append(parts, head)
+	}
+	for i, c := range cuts {
+		end := len(whole.body)
+		if i+1 < len(cuts) {
+			end = cuts[i+1].offset
+		}
+		parts = append(parts, newDocumentPart(c.target, whole.body[c.offset:end]))
+	}
+	return parts
+}
+
+func newDocumentPart(tg *target, body string) documentPart {
+	return documentPart{target: tg, body: body, plainText: epub.ExtractText([]byte(body)), source: body}
+}
+
+// anchorOffsets finds where the elements with the given IDs start in an HTML
+// fragment; <a name> anchors count as well. An offset is moved back over the
+// start tags directly before the element, so that cutting there keeps
+// wrappers such as a <section> with the heading they open with.
+func anchorOffsets(fragment string, ids []string) map[string]int {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	offsets := make(map[string]int, len(ids))
+
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	pos := 0
+	// runStart is where the run of start tags the current token is part of
+	// began, or -1 outside of one
+	runStart := -1
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return offsets
+		}
+		start := pos
+		pos += len(z.Raw())
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if runStart < 0 {
+				runStart = start
+			}
+			name, hasAttr := z.TagName()
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				if string(key) != "id" && (string(key) != "name" || string(name) != "a") {
+					continue
+				}
+				if _, seen := offsets[string(val)]; wanted[string(val)] && !seen {
+					offsets[string(val)] = runStart
+				}
+			}
+			if tt == html.SelfClosingTagToken {
+				runStart = -1
+			}
+		case html.TextToken:
+			if strings.TrimSpace(string(z.Raw())) != "" {
+				runStart = -1
+			}
+		default:
+			runStart = -1
+		}
+	}
+}
+
+// compactVolumes drops volumes that ended up without chapters and renumbers
+// the rest, updating the chapters' volume indexes to match
+func compactVolumes(volumes []VolumeData, chapters []ChapterData) []VolumeData {
+	used := make([]bool, len(volumes))
+	for _, ch := range chapters {
+		used[ch.VolumeIndex] = true
+	}
+
+	index := make([]int, len(volumes))
+	compacted := make([]VolumeData, 0, len(volumes))
+	for i, v := range volumes {
+		if !used[i] {
+			continue
+		}
+		index[i] = len(compacted)
+		v.Number = len(compacted) + 1
+		compacted = append(compacted, v)
+	}
+
+	for i := range chapters {
+		chapters[i].VolumeIndex = index[chapters[i].VolumeIndex]
+	}
+	return compacted
+}
+
+func flattenTOC(entries []tocEntry) []tocEntry {
+	var flat []tocEntry
+	for _, entry := range entries {
+		flat = append(flat, tocEntry{Title: entry.Title, Path: entry.Path, Fragment: entry.Fragment})
+		flat = append(flat, flattenTOC(entry.Children)...)
+	}
+	return flat
+}
+
+func parseRawOPF(content *epub.RawEpub) (*epub.OPFPackage, error) {
+	opfBytes, ok := content.RawFiles[content.OPFPath]
+	if !ok {
+		return nil, errors.New("OPF not found in raw epub")
+	}
+	return epub.ParseOPF(opfBytes)
+}
+
+// resolveHref resolves an href found in the file at base to a path relative
+// to the EPUB root, dropping any fragment
+func resolveHref(base, href string) string {
+	resolved, _ := splitHref(base, href)
+	return resolved
+}
+
+// splitHref resolves an href like resolveHref and returns its fragment too
+func splitHref(base, href string) (string, string) {
+	fragment := ""
+	if i := strings.Index(href, "#"); i >= 0 {
+		href, fragment = href[:i], href[i+1:]
+	}
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	if href == "" {
+		return "", fragment
+	}
+	return path.Join(path.Dir(base), href), fragment
+}
+
+// findCoverPath looks for the cover image declared by the EPUB 3
+// cover-image property, then by the EPUB 2 cover meta, then by its ID
+func findCoverPath(content *epub.RawEpub, opfPkg *epub.OPFPackage) string {
+	candidates := []epub.OPFManifestItem{}
+	for _, item := range opfPkg.Manifest {
+		if strings.Contains(" "+item.Properties+" ", " cover-image ") {
+			candidates = append(candidates, item)
+		}
+	}
+
+	if coverID := legacyCoverID(content.RawFiles[content.OPFPath]); coverID != "" {
+		for _, item := range opfPkg.Manifest {
+			if item.ID == coverID {
+				candidates = append(candidates, item)
+			}
+		}
+	}
+
+	for _, item := range opfPkg.Manifest {
+		if strings.Contains(strings.ToLower(item.ID), "cover") && strings.HasPrefix(item.MediaType, "image/") {
+			candidates = append(candidates, item)
+		}
+	}
+
+	for _, item := range candidates {
+		coverPath := resolveHref(content.OPFPath, item.Href)
+		if _, exists := content.RawFiles[coverPath]; exists {
+			return coverPath
+		}
+	}
+	return ""
+}
+
+// legacyCoverID reads <meta name="cover" content="..."/> from the OPF
+// metadata, which OPFMetadata does not keep
+func legacyCoverID(opfBytes []byte) string {
+	var pkg struct {
+		Meta []struct {
+			Name    string `xml:"name,attr"`
+			Content string `xml:"content,attr"`
+		} `xml:"metadata>meta"`
+	}
+	if err := xml.Unmarshal(opfBytes, &pkg); err != nil {
+		return ""
+	}
+	for _, meta := range pkg.Meta {
+		if meta.Name == "cover" {
+			return meta.Content
+		}
+	}
+	return ""
+}
+
+// readTOC returns the table of contents from the EPUB 3 nav document, or
+// from the EPUB 2 NCX when there is none, along with the path of the nav
+// document so it can be left out of the chapters
+func readTOC(content *epub.RawEpub, opfPkg *epub.OPFPackage, manifestMap map[string]epub.OPFManifestItem) ([]tocEntry, string) {
+	for _, item := range opfPkg.Manifest {
+		if !strings.Contains(" "+item.Properties+" ", " nav ") {
+			continue
+		}
+		navPath := resolveHref(content.OPFPath, item.Href)
+		if raw, exists := content.RawFiles[navPath]; exists {
+			if entries := parseNav(raw, navPath); len(entries) > 0 {
+				return entries, navPath
+			}
+		}
+	}
+
+	ncxItem, exists := manifestMap[opfPkg.Spine.Toc]
+	if !exists {
+		for _, item := range opfPkg.Manifest {
+			if item.MediaType == "application/x-dtbncx+xml" {
+				ncxItem, exists = item, true
+				break
+			}
+		}
+	}
+	if exists {
+		ncxPath := resolveHref(content.OPFPath, ncxItem.Href)
+		if raw, ok := content.RawFiles[ncxPath]; ok {
+			return parseNCX(raw, ncxPath), ""
+		}
+	}
+
+	return nil, ""
+}
+
+// parseNav reads the toc nav of an EPUB 3 navigation document
+func parseNav(raw []byte, navPath string) []tocEntry {
+	doc, err := html.Parse(bytes.NewReader(raw))
+	if err != nil {
+		return nil
+	}
+
+	var navs []*html.Node
+	var find func(*html.Node)
+	find = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "nav" {
+			navs = append(navs, n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			find(c)
+		}
+	}
+	find(doc)
+	if len(navs) == 0 {
+		return nil
+	}
+
+	nav := navs[0]
+	for _, n := range navs {
+		for _, attr := range n.Attr {
+			if attr.Key == "epub:type" && strings.Contains(" "+attr.Val+" ", " toc ") {
+				nav = n
+			}
+		}
+	}
+
+	for c := nav.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "ol" {
+			return parseNavList(c, navPath)
+		}
+	}
+	return nil
+}
+
+func parseNavList(ol *html.Node, navPath string) []tocEntry {
+	var entries []tocEntry
+	for li := ol.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+
+		var entry tocEntry
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "a":
+				for _, attr := range c.Attr {
+					if attr.Key == "href" {
+						entry.Path, entry.Fragment = splitHref(navPath, attr.Val)
+					}
+				}
+				entry.Title = nodeText(c)
+			case "span":
+				entry.Title = nodeText(c)
+			case "ol":
+				entry.Children = parseNavList(c, navPath)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+type ncxNavPoint struct {
+	Label   string        `xml:"navLabel>text"`
+	Content ncxContent    `xml:"content"`
+	Points  []ncxNavPoint `xml:"navPoint"`
+}
+
+type ncxContent struct {
+	Src string `xml:"src,attr"`
+}
+
+// parseNCX reads the navMap of an EPUB 2 NCX file
+func parseNCX(raw []byte, ncxPath string) []tocEntry {
+	var ncx struct {
+		Points []ncxNavPoint `xml:"navMap>navPoint"`
+	}
+	if err := xml.Unmarshal(raw, &ncx); err != nil {
+		logger.Error(err, "failed to parse NCX table of contents")
+		return nil
+	}
+
+	var convert func([]ncxNavPoint) []tocEntry
+	convert = func(points []ncxNavPoint) []tocEntry {
+		entries := make([]tocEntry, 0, len(points))
+		for _, p := range points {
+			entryPath, fragment := splitHref(ncxPath, p.Content.Src)
+			entries = append(entries, tocEntry{
+				Title:    strings.Join(strings.Fields(p.Label), " "),
+				Path:     entryPath,
+				Fragment: fragment,
+				Children: convert(p.Points),
+			})
+		}
+		return entries
+	}
+	return convert(ncx.Points)
+}
@@ -0,0 +1,79 @@
+package transformer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"simple-go/pkg/epub"
+)
+
+const fragmentOPF = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>Book</dc:title>
+    <dc:language>en</dc:language>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="text" href="text/book.xhtml" media-type="application/xhtml+xml"/>
+  </manifest>
+  <spine>
+    <itemref idref="nav"/>
+    <itemref idref="text"/>
+  </spine>
+</package>`
+
+const fragmentNav = `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops"><body>
+<nav epub:type="toc"><ol>
+  <li><a href="text/book.xhtml">Prologue</a></li>
+  <li><a href="text/book.xhtml#ch1">Chapter 1</a></li>
+  <li><a href="text/book.xhtml#ch2">Chapter 2</a></li>
+</ol></nav>
+</body></html>`
+
+const fragmentBook = `<html xmlns="http://www.w3.org/1999/xhtml"><body>
+<p>Before it all.</p>
+<section><h2 id="ch1">Chapter 1</h2><p>The hero arrives.</p></section>
+<section><h2 id="ch2">Chapter 2</h2><p>The hero leaves.</p></section>
+</body></html>`
+
+func TestGenericSplitsChaptersAtTOCFragments(t *testing.T) {
+	content := &epub.RawEpub{
+		OPFPath: "OEBPS/content.opf",
+		RawFiles: map[string][]byte{
+			"OEBPS/content.opf":     []byte(fragmentOPF),
+			"OEBPS/nav.xhtml":       []byte(fragmentNav),
+			"OEBPS/text/book.xhtml": []byte(fragmentBook),
+		},
+	}
+
+	volumes, chapters, err := NewSourceGenericTransformer().TransformToStructure(context.Background(), content)
+	if err != nil {
+		t.Fatalf("TransformToStructure: %v", err)
+	}
+	if len(volumes) != 1 || !volumes[0].IsVirtual {
+		t.Fatalf("volumes = %+v, want one virtual volume", volumes)
+	}
+
+	want := []struct {
+		title, text string
+	}{
+		{"Prologue", "Before it all."},
+		{"Chapter 1", "Chapter 1 The hero arrives."},
+		{"Chapter 2", "Chapter 2 The hero leaves."},
+	}
+	if len(chapters) != len(want) {
+		t.Fatalf("got %d chapters, want %d", len(chapters), len(want))
+	}
+	for i, w := range want {
+		if chapters[i].Title != w.title || chapters[i].PlainText != w.text || chapters[i].OrderNum != i+1 {
+			t.Errorf("chapter %d = %q (%d) %q, want %q (%d) %q",
+				i, chapters[i].Title, chapters[i].OrderNum, chapters[i].PlainText, w.title, i+1, w.text)
+		}
+	}
+	// The cut keeps each <section> with the heading it opens with
+	if !strings.HasPrefix(chapters[2].Content, "<section>") {
+		t.Errorf("chapter 2 content = %q, want it to start with its <section>", chapters[2].Content)
+	}
+}
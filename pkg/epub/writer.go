@@ -0,0 +1,429 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Book is the content of an EPUB 3 file written by Write. Metadata needs at
+// least a title, a language and an identifier; the first of each is used.
+type Book struct {
+	Metadata OPFMetadata
+	Modified time.Time
+	Cover    *Image
+	Sections []Section
+}
+
+// Image is an image stored in the EPUB, such as its cover
+type Image struct {
+	MediaType string
+	Data      []byte
+}
+
+// Section groups chapters in the navigation document, e.g. a volume. Chapters
+// of an untitled section are listed at the top level.
+type Section struct {
+	Title    string
+	Chapters []Chapter
+}
+
+// Chapter is one content document. Body is HTML, either a fragment or a full
+// document, or plain text with one paragraph per line. Lang is only needed
+// when the chapter is not in the book's language.
+type Chapter struct {
+	Title string
+	Lang  string
+	Body  string
+}
+
+const (
+	contentDir   = "OEBPS/"
+	opfFileName  = "content.opf"
+	navFileName  = "nav.xhtml"
+	bookIDAttr   = "book-id"
+	coverImageID = "cover-image"
+)
+
+// coverExtensions lists the image core media types of EPUB 3, which every
+// reading system supports
+var coverExtensions = map[string]string{
+	"image/jpeg":    "jpg",
+	"image/png":     "png",
+	"image/gif":     "gif",
+	"image/webp":    "webp",
+	"image/svg+xml": "svg",
+}
+
+// imageTypeAliases maps nonstandard names servers use for core media types
+var imageTypeAliases = map[string]string{
+	"image/jpg":   "image/jpeg",
+	"image/pjpeg": "image/jpeg",
+	"image/x-png": "image/png",
+	"image/svg":   "image/svg+xml",
+}
+
+// CoreImageType returns the EPUB core media type mediaType names, resolving
+// common aliases such as image/jpg, or "" when it is not a core media type
+func CoreImageType(mediaType string) string {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if alias, ok := imageTypeAliases[mediaType]; ok {
+		mediaType = alias
+	}
+	if _, ok := coverExtensions[mediaType]; !ok {
+		return ""
+	}
+	return mediaType
+}
+
+// Write streams b as an EPUB 3 file to w
+func Write(w io.Writer, b *Book) error {
+	if len(b.Metadata.Title) == 0 || len(b.Metadata.Language) == 0 || len(b.Metadata.Identifier) == 0 {
+		return errors.New("epub needs a title, a language and an identifier")
+	}
+	if b.Cover != nil && CoreImageType(b.Cover.MediaType) != b.Cover.MediaType {
+		return fmt.Errorf("cover media type %q is not an EPUB core media type", b.Cover.MediaType)
+	}
+
+	modified := b.Modified
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+	modified = modified.UTC().Truncate(time.Second)
+
+	zw := zip.NewWriter(w)
+
+	// The mimetype must come first and be stored uncompressed, without a
+	// data descriptor, so readers can identify the file by its first bytes
+	if err := writeStored(zw, "mimetype", []byte("application/epub+zip"), modified); err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data func() ([]byte, error)
+	}{
+		{"META-INF/container.xml", func() ([]byte, error) { return []byte(containerXML), nil }},
+		{contentDir + opfFileName, func() ([]byte, error) { return packageDocument(b, modified) }},
+		{contentDir + navFileName, func() ([]byte, error) { return navDocument(b), nil }},
+	}
+	for _, f := range files {
+		data, err := f.data()
+		if err != nil {
+			return err
+		}
+		if err := writeDeflated(zw, f.name, data, modified); err != nil {
+			return err
+		}
+	}
+
+	if b.Cover != nil {
+		if err := writeStored(zw, contentDir+coverHref(b.Cover), b.Cover.Data, modified); err != nil {
+			return err
+		}
+	}
+
+	n := 0
+	for _, section := range b.Sections {
+		for _, ch := range section.Chapters {
+			n++
+			if err := writeDeflated(zw, contentDir+chapterHref(n), chapterDocument(b, ch), modified); err != nil {
+				return err
+			}
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeStored(zw *zip.Writer, name string, data []byte, modified time.Time) error {
+	fw, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Store,
+		Modified:           modified,
+		CRC32:              crc32.ChecksumIEEE(data),
+		CompressedSize64:   uint64(len(data)),
+		UncompressedSize64: uint64(len(data)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeDeflated(zw *zip.Writer, name string, data []byte, modified time.Time) error {
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func chapterHref(n int) string {
+	return fmt.Sprintf("text/chapter-%04d.xhtml", n)
+}
+
+func coverHref(img *Image) string {
+	return "images/cover." + coverExtensions[img.MediaType]
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="` + contentDir + opfFileName + `" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// opfMetadataXML writes OPFMetadata with the Dublin Core prefix the
+// package document needs; OPFMetadata itself is matched without prefixes
+// when parsing
+type opfMetadataXML struct {
+	XmlnsDC     string        `xml:"xmlns:dc,attr"`
+	Identifier  opfIdentifier `xml:"dc:identifier"`
+	Title       []string      `xml:"dc:title"`
+	Language    []string      `xml:"dc:language"`
+	Creator     []string      `xml:"dc:creator"`
+	Publisher   []string      `xml:"dc:publisher"`
+	Description []string      `xml:"dc:description"`
+	Subject     []string      `xml:"dc:subject"`
+	Date        []string      `xml:"dc:date"`
+	Rights      []string      `xml:"dc:rights"`
+	Meta        []opfMeta     `xml:"meta"`
+}
+
+type opfIdentifier struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+type opfMeta struct {
+	Property string `xml:"property,attr,omitempty"`
+	Name     string `xml:"name,attr,omitempty"`
+	Content  string `xml:"content,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+type opfPackageXML struct {
+	XMLName          xml.Name          `xml:"package"`
+	Xmlns            string            `xml:"xmlns,attr"`
+	Version          string            `xml:"version,attr"`
+	UniqueIdentifier string            `xml:"unique-identifier,attr"`
+	Lang             string            `xml:"xml:lang,attr"`
+	Metadata         opfMetadataXML    `xml:"metadata"`
+	Manifest         []OPFManifestItem `xml:"manifest>item"`
+	Spine            OPFSpine          `xml:"spine"`
+}
+
+func packageDocument(b *Book, modified time.Time) ([]byte, error) {
+	m := b.Metadata
+	pkg := opfPackageXML{
+		Xmlns:            "http://www.idpf.org/2007/opf",
+		Version:          "3.0",
+		UniqueIdentifier: bookIDAttr,
+		Lang:             m.Language[0],
+		Metadata: opfMetadataXML{
+			XmlnsDC:     "http://purl.org/dc/elements/1.1/",
+			Identifier:  opfIdentifier{ID: bookIDAttr, Value: m.Identifier[0]},
+			Title:       m.Title,
+			Language:    m.Language,
+			Creator:     m.Creator,
+			Publisher:   m.Publisher,
+			Description: m.Description,
+			Subject:     m.Subject,
+			Date:        m.Date,
+			Rights:      m.Rights,
+			Meta: []opfMeta{
+				{Property: "dcterms:modified", Value: modified.Format(time.RFC3339)},
+			},
+		},
+		Manifest: []OPFManifestItem{
+			{ID: "nav", Href: navFileName, MediaType: "application/xhtml+xml", Properties: "nav"},
+		},
+	}
+
+	if b.Cover != nil {
+		pkg.Manifest = append(pkg.Manifest, OPFManifestItem{
+			ID:         coverImageID,
+			Href:       coverHref(b.Cover),
+			MediaType:  b.Cover.MediaType,
+			Properties: "cover-image",
+		})
+		// Older readers look the cover up through this meta instead
+		pkg.Metadata.Meta = append(pkg.Metadata.Meta, opfMeta{Name: "cover", Content: coverImageID})
+	}
+
+	n := 0
+	for _, section := range b.Sections {
+		for range section.Chapters {
+			n++
+			id := fmt.Sprintf("chapter-%04d", n)
+			pkg.Manifest = append(pkg.Manifest, OPFManifestItem{ID: id, Href: chapterHref(n), MediaType: "application/xhtml+xml"})
+			pkg.Spine.ItemRefs = append(pkg.Spine.ItemRefs, OPFItemRef{IDRef: id})
+		}
+	}
+
+	out, err := xml.MarshalIndent(pkg, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode package document: %w", err)
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+func navDocument(b *Book) []byte {
+	var sb strings.Builder
+	writeXHTMLStart(&sb, b.Metadata.Language[0], b.Metadata.Title[0])
+	sb.WriteString("<nav epub:type=\"toc\" id=\"toc\">\n<h1>")
+	sb.WriteString(escapeXML(b.Metadata.Title[0]))
+	sb.WriteString("</h1>\n<ol>\n")
+
+	n := 0
+	for _, section := range b.Sections {
+		if len(section.Chapters) == 0 {
+			continue
+		}
+		if section.Title != "" {
+			// A section links to its first chapter, as every nav entry
+			// needs a target
+			fmt.Fprintf(&sb, "<li><a href=\"%s\">%s</a>\n<ol>\n", chapterHref(n+1), escapeXML(section.Title))
+		}
+		for _, ch := range section.Chapters {
+			n++
+			fmt.Fprintf(&sb, "<li><a href=\"%s\">%s</a></li>\n", chapterHref(n), escapeXML(ch.Title))
+		}
+		if section.Title != "" {
+			sb.WriteString("</ol>\n</li>\n")
+		}
+	}
+
+	sb.WriteString("</ol>\n</nav>\n")
+	writeXHTMLEnd(&sb)
+	return []byte(sb.String())
+}
+
+func chapterDocument(b *Book, ch Chapter) []byte {
+	lang := ch.Lang
+	if lang == "" {
+		lang = b.Metadata.Language[0]
+	}
+
+	var sb strings.Builder
+	writeXHTMLStart(&sb, lang, ch.Title)
+	sb.WriteString("<section epub:type=\"chapter\">\n")
+	sb.WriteString(xhtmlBody(ch.Body))
+	sb.WriteString("\n</section>\n")
+	writeXHTMLEnd(&sb)
+	return []byte(sb.String())
+}
+
+func writeXHTMLStart(sb *strings.Builder, lang, title string) {
+	sb.WriteString(xml.Header)
+	sb.WriteString("<!DOCTYPE html>\n")
+	fmt.Fprintf(sb, "<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" xml:lang=\"%s\" lang=\"%s\">\n", escapeXML(lang), escapeXML(lang))
+	fmt.Fprintf(sb, "<head>\n<meta charset=\"UTF-8\"/>\n<title>%s</title>\n</head>\n<body>\n", escapeXML(title))
+}
+
+func writeXHTMLEnd(sb *strings.Builder) {
+	sb.WriteString("</body>\n</html>\n")
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// Elements dropped from chapter bodies: scripts are not allowed, and images
+// and stylesheets would point at files the EPUB does not contain
+var droppedElements = map[string]bool{
+	"script": true,
+	"style":  true,
+	"link":   true,
+	"img":    true,
+}
+
+// xhtmlBody turns stored chapter content into well-formed XHTML. HTML is
+// re-rendered, which closes open tags and escapes stray characters; plain
+// text is split into paragraphs.
+func xhtmlBody(content string) string {
+	doc, err := html.Parse(strings.NewReader(content))
+	if err != nil {
+		return plainTextBody(content)
+	}
+
+	body := findElement(doc, "body")
+	if body == nil || !hasElementChild(body) {
+		return plainTextBody(content)
+	}
+
+	var clean func(*html.Node)
+	clean = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; {
+			next := c.NextSibling
+			if c.Type == html.CommentNode || (c.Type == html.ElementNode && droppedElements[c.Data]) {
+				n.RemoveChild(c)
+			} else {
+				clean(c)
+			}
+			c = next
+		}
+	}
+	clean(body)
+
+	var buf bytes.Buffer
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return plainTextBody(ExtractText([]byte(content)))
+		}
+	}
+	return buf.String()
+}
+
+func plainTextBody(content string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(escapeXML(line))
+		sb.WriteString("</p>\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func findElement(n *html.Node, name string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == name {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func hasElementChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func TestCoreImageTypeNormalizesAliases(t *testing.T) {
+	cases := map[string]string{
+		"image/jpeg":  "image/jpeg",
+		"image/jpg":   "image/jpeg",
+		"Image/JPG":   "image/jpeg",
+		"image/x-png": "image/png",
+		"image/webp":  "image/webp",
+		"image/bmp":   "",
+		"text/html":   "",
+	}
+	for in, want := range cases {
+		if got := CoreImageType(in); got != want {
+			t.Errorf("CoreImageType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriteRejectsNonCoreCover(t *testing.T) {
+	book := &Book{
+		Metadata: OPFMetadata{Title: []string{"Book"}, Language: []string{"en"}, Identifier: []string{"urn:uuid:1"}},
+		Cover:    &Image{MediaType: "image/bmp", Data: []byte("BM")},
+	}
+	if err := Write(&bytes.Buffer{}, book); err == nil {
+		t.Fatal("expected an error for a non-core cover media type")
+	}
+
+	book.Cover.MediaType = "image/png"
+	var buf bytes.Buffer
+	if err := Write(&buf, book); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("read written EPUB: %v", err)
+	}
+	found := false
+	for _, f := range zr.File {
+		if f.Name == contentDir+"images/cover.png" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("cover was not stored as images/cover.png")
+	}
+}
@@ -1,9 +1,8 @@
 package miscellaneous
 
 import (
+	_ "embed"
 	"encoding/json"
-	"os"
-	"path/filepath"
 	"strings"
 )
 
@@ -19,27 +18,14 @@ func init() {
 	loadLanguages()
 }
 
-func loadLanguages() {
-	// Get the path to list.json
-	ex, err := os.Executable()
-	if err != nil {
-		panic(err)
-	}
-	exePath := filepath.Dir(ex)
-	jsonPath := filepath.Join(exePath, "pkg/miscellaneous/list.json")
-
-	// Try loading from executable directory first
-	data, err := os.ReadFile(jsonPath)
-	if err != nil {
-		// Fallback to relative path from current working directory
-		data, err = os.ReadFile("pkg/miscellaneous/list.json")
-		if err != nil {
-			panic("Could not load language list: " + err.Error())
-		}
-	}
+// list.json is embedded so that the list loads regardless of the working
+// directory, including in tests
+//
+//go:embed list.json
+var languageList []byte
 
-	err = json.Unmarshal(data, &languages)
-	if err != nil {
+func loadLanguages() {
+	if err := json.Unmarshal(languageList, &languages); err != nil {
 		panic("Could not parse language list: " + err.Error())
 	}
 }
@@ -0,0 +1,53 @@
+package qa
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// voidElements never have a closing tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// tagProblem returns a description of the first unbalanced tag in an HTML
+// fragment, or "" when every element is closed in order
+func tagProblem(fragment string) string {
+	open := make([]string, 0)
+
+	z := xhtml.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tt := z.Next()
+		switch tt {
+		case xhtml.ErrorToken:
+			if z.Err() != io.EOF {
+				return "HTML could not be parsed"
+			}
+			if len(open) > 0 {
+				return fmt.Sprintf("<%s> is never closed", open[len(open)-1])
+			}
+			return ""
+		case xhtml.StartTagToken:
+			name, _ := z.TagName()
+			if !voidElements[string(name)] {
+				open = append(open, string(name))
+			}
+		case xhtml.EndTagToken:
+			name, _ := z.TagName()
+			if voidElements[string(name)] {
+				continue
+			}
+			if len(open) == 0 {
+				return fmt.Sprintf("</%s> has no opening tag", name)
+			}
+			if top := open[len(open)-1]; top != string(name) {
+				return fmt.Sprintf("</%s> closes <%s>", name, top)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+}
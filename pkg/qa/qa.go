@@ -0,0 +1,208 @@
+// Package qa runs automated quality checks on machine translations. The
+// checks are heuristics: they point reviewers at likely problems and do not
+// judge the translation itself.
+package qa
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"simple-go/pkg/epub"
+	"simple-go/pkg/script"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+
+	RuleEmptyOutput     = "empty_output"
+	RuleUntranslated    = "untranslated_text"
+	RuleSegmentMismatch = "segment_count_mismatch"
+	RuleTruncated       = "truncated"
+	RuleBrokenHTML      = "broken_html"
+	RuleGlossaryMiss    = "glossary_miss"
+)
+
+const (
+	// untranslatedErrorRatio is the share of source-script letters in an
+	// output segment from which it counts as not translated at all
+	untranslatedErrorRatio = 0.5
+	// truncatedErrorRatio and truncatedWarningRatio bound the weighted
+	// length of an output relative to its source
+	truncatedErrorRatio   = 0.3
+	truncatedWarningRatio = 0.5
+	// minTruncationLength is the weighted source length below which length
+	// ratios are too noisy to check
+	minTruncationLength = 40
+)
+
+// Finding is one problem found in a translation. Location names the field
+// and, for multi-paragraph content, the 1-based segment.
+type Finding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// Text is a source text and its translation
+type Text struct {
+	Field  string
+	Source string
+	Output string
+}
+
+// Check runs every check on the texts of one entity
+func Check(sourceLang, targetLang string, texts []Text) []Finding {
+	c := checker{
+		foreign:      script.Foreign(sourceLang, targetLang),
+		sourceWeight: script.Weight(sourceLang),
+		targetWeight: script.Weight(targetLang),
+		findings:     make([]Finding, 0),
+	}
+	for _, t := range texts {
+		c.checkText(t)
+	}
+	return c.findings
+}
+
+// GlossaryMisses reports glossary terms the translation did not use
+func GlossaryMisses(terms []string) []Finding {
+	findings := make([]Finding, len(terms))
+	for i, term := range terms {
+		findings[i] = Finding{
+			Severity: SeverityWarning,
+			Rule:     RuleGlossaryMiss,
+			Location: "glossary",
+			Message:  fmt.Sprintf("glossary term %q was not used", term),
+		}
+	}
+	return findings
+}
+
+// Count returns the number of errors and warnings in findings
+func Count(findings []Finding) (errors, warnings int) {
+	for _, f := range findings {
+		switch f.Severity {
+		case SeverityError:
+			errors++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+type checker struct {
+	foreign      []script.Script
+	sourceWeight float64
+	targetWeight float64
+	findings     []Finding
+}
+
+func (c *checker) add(severity, rule, location, format string, args ...any) {
+	c.findings = append(c.findings, Finding{
+		Severity: severity,
+		Rule:     rule,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) checkText(t Text) {
+	if epub.PlainText(t.Source) == "" {
+		return
+	}
+	if epub.PlainText(t.Output) == "" {
+		c.add(SeverityError, RuleEmptyOutput, t.Field, "translation is empty")
+		return
+	}
+
+	if problem := tagProblem(t.Output); problem != "" && tagProblem(t.Source) == "" {
+		c.add(SeverityError, RuleBrokenHTML, t.Field, "%s", problem)
+	}
+
+	sourceLen := c.weightedLength(t.Source, c.sourceWeight)
+	if sourceLen >= minTruncationLength {
+		ratio := c.weightedLength(t.Output, c.targetWeight) / sourceLen
+		switch {
+		case ratio < truncatedErrorRatio:
+			c.add(SeverityError, RuleTruncated, t.Field, "translation is %.0f%% of the expected length", ratio*100)
+		case ratio < truncatedWarningRatio:
+			c.add(SeverityWarning, RuleTruncated, t.Field, "translation is %.0f%% of the expected length", ratio*100)
+		}
+	}
+
+	sources := epub.TranslatableSegments(epub.SegmentHTML(t.Source))
+	outputs := epub.TranslatableSegments(epub.SegmentHTML(t.Output))
+	if len(sources) != len(outputs) {
+		c.add(SeverityError, RuleSegmentMismatch, t.Field, "source has %d segments, translation has %d", len(sources), len(outputs))
+		// Segments cannot be compared one by one; check the text as a whole
+		c.checkSegment(t.Field, t.Source, t.Output, false)
+		return
+	}
+
+	for i := range sources {
+		location := t.Field
+		if len(sources) > 1 {
+			location = fmt.Sprintf("%s segment %d", t.Field, i+1)
+		}
+		c.checkSegment(location, sources[i], outputs[i], i == len(sources)-1)
+	}
+}
+
+func (c *checker) checkSegment(location, source, output string, last bool) {
+	sourceText := epub.PlainText(source)
+	outputText := epub.PlainText(output)
+	if sourceText == "" {
+		return
+	}
+	if outputText == "" {
+		c.add(SeverityError, RuleEmptyOutput, location, "segment is empty")
+		return
+	}
+
+	if len(c.foreign) > 0 {
+		counts := script.Count(outputText)
+		letters, foreign := 0, 0
+		for s, n := range counts {
+			letters += n
+			for _, f := range c.foreign {
+				if s == f {
+					foreign += n
+				}
+			}
+		}
+		switch {
+		case foreign > 0 && float64(foreign) >= untranslatedErrorRatio*float64(letters):
+			c.add(SeverityError, RuleUntranslated, location, "segment is mostly in the source script")
+		case foreign > 0:
+			c.add(SeverityWarning, RuleUntranslated, location, "%d letters in the source script remain", foreign)
+		}
+	}
+
+	// A model that runs out of output tokens stops mid-sentence
+	if last && endsSentence(sourceText) && !endsSentence(outputText) {
+		c.add(SeverityWarning, RuleTruncated, location, "translation ends mid-sentence")
+	}
+}
+
+func (c *checker) weightedLength(content string, weight float64) float64 {
+	return float64(utf8.RuneCountInString(epub.PlainText(content))) * weight
+}
+
+// sentenceEnds are characters that end a sentence; closingMarks may follow them
+const (
+	sentenceEnds = ".!?…。！？"
+	closingMarks = "\"'”’」』）)]»"
+)
+
+func endsSentence(text string) bool {
+	trimmed := strings.TrimRight(text, closingMarks+" ")
+	if trimmed == "" {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(trimmed)
+	return strings.ContainsRune(sentenceEnds, r)
+}
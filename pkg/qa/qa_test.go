@@ -0,0 +1,100 @@
+package qa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	// 94 letters of plain text, long enough for the length ratios
+	long := "<p>" + strings.Repeat("The hero walks on. ", 5) + "</p>"
+
+	tests := []struct {
+		name           string
+		from, to       string
+		source, output string
+		// want lists findings as "severity rule location"
+		want []string
+	}{
+		{
+			name: "clean translation", from: "en", to: "de",
+			source: "<p>The hero arrives.</p><p>He leaves.</p>",
+			output: "<p>Der Held kommt.</p><p>Er geht.</p>",
+		},
+		{
+			name: "empty source is not checked", from: "en", to: "de",
+			source: "<p> </p>", output: "",
+		},
+		{
+			name: "empty output", from: "en", to: "de",
+			source: "<p>The hero arrives.</p>", output: "<p> </p>",
+			want: []string{"error empty_output content"},
+		},
+		{
+			name: "broken HTML", from: "en", to: "de",
+			source: "<p>The hero arrives.</p>", output: "<p>Der Held <b>kommt.</p>",
+			want: []string{"error broken_html content"},
+		},
+		{
+			name: "HTML broken in the source already", from: "en", to: "de",
+			source: "<p>The hero <b>arrives.</p>", output: "<p>Der Held <b>kommt.</p>",
+		},
+		{
+			name: "segment count mismatch", from: "en", to: "de",
+			source: "<p>The hero arrives.</p><p>He leaves.</p>",
+			output: "<p>Der Held kommt. Er geht.</p>",
+			want:   []string{"error segment_count_mismatch content"},
+		},
+		{
+			name: "untranslated segment", from: "ja", to: "en",
+			source: "<p>勇者が来た。</p>", output: "<p>勇者が来た。</p>",
+			want: []string{"error untranslated_text content"},
+		},
+		{
+			name: "source script left in a segment", from: "ja", to: "en",
+			source: "<p>勇者が来た。</p>", output: "<p>The 勇者 arrives.</p>",
+			want: []string{"warning untranslated_text content"},
+		},
+		{
+			name: "far too short", from: "en", to: "de",
+			source: long, output: "<p>Der Held geht.</p>",
+			want: []string{"error truncated content"},
+		},
+		{
+			name: "somewhat too short", from: "en", to: "de",
+			source: long, output: "<p>Der Held geht immer weiter und weiter fort.</p>",
+			want: []string{"warning truncated content"},
+		},
+		{
+			name: "ends mid-sentence in the last segment", from: "en", to: "de",
+			source: "<p>The hero arrives.</p><p>He leaves.</p>",
+			output: "<p>Der Held kommt</p><p>Er geht und</p>",
+			want:   []string{"warning truncated content segment 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Check(tt.from, tt.to, []Text{{Field: "content", Source: tt.source, Output: tt.output}})
+
+			got := make([]string, len(findings))
+			for i, f := range findings {
+				got[i] = f.Severity + " " + f.Rule + " " + f.Location
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("findings = %q (%+v), want %q", got, findings, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlossaryMissesAndCount(t *testing.T) {
+	findings := append(GlossaryMisses([]string{"hero", "king"}), Finding{Severity: SeverityError, Rule: RuleEmptyOutput})
+
+	if findings[0].Rule != RuleGlossaryMiss || findings[0].Severity != SeverityWarning || !strings.Contains(findings[0].Message, `"hero"`) {
+		t.Errorf("glossary miss = %+v, want a warning naming the term", findings[0])
+	}
+	if errors, warnings := Count(findings); errors != 1 || warnings != 2 {
+		t.Errorf("Count = %d errors, %d warnings, want 1 and 2", errors, warnings)
+	}
+}
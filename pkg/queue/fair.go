@@ -0,0 +1,150 @@
+package queue
+
+import (
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Jobs are scheduled by start-time fair queuing. Every owner (usually the user
+// who created the job) has a virtual clock that advances by one each time one
+// of their jobs is queued for a turn, and the queue's clock follows the turns
+// handed out. A job is scored by the later of the two clocks, so owners take
+// turns regardless of how many jobs or subtasks they have, and an owner who
+// was idle rejoins at the current turn instead of catching up.
+//
+// Priority splits the scores into bands: any queued job of a higher priority
+// is taken before all jobs of a lower one.
+
+const (
+	// MinPriority and MaxPriority bound job priorities; DefaultPriority is
+	// normal work
+	MinPriority     = -5
+	MaxPriority     = 5
+	DefaultPriority = 0
+
+	// bandWidth separates priority bands in the score; it is far above any
+	// virtual time reached in practice
+	bandWidth = 1e11
+)
+
+// pushScript queues a job unless it is already queued, in which case only its
+// message is refreshed.
+//
+// KEYS: jobs, messages, owners, clock, signal
+// ARGV: job ID, message, owner, priority band
+var pushScript = redis.NewScript(`
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+if redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+	return 0
+end
+
+local now = tonumber(redis.call('GET', KEYS[4]) or '0')
+local start = math.max(tonumber(redis.call('HGET', KEYS[3], ARGV[3]) or '0'), now)
+redis.call('HSET', KEYS[3], ARGV[3], string.format('%.0f', start + 1))
+redis.call('ZADD', KEYS[1], string.format('%.0f', tonumber(ARGV[4]) + start), ARGV[1])
+redis.call('RPUSH', KEYS[5], '1')
+return 1
+`)
+
+// popScript takes the job with the lowest score and advances the clock to it.
+// It also drops the job's signal entry unless the caller already consumed one
+// while waiting, and the clock of a job without an owner, which rejoins at
+// the current turn when it is queued again anyway.
+//
+// KEYS: jobs, messages, clock, signal, owners
+// ARGV: band width, "1" to drop a signal entry
+var popScript = redis.NewScript(`
+local popped = redis.call('ZPOPMIN', KEYS[1])
+if #popped == 0 then
+	return false
+end
+
+local id = popped[1]
+local start = tonumber(popped[2]) % tonumber(ARGV[1])
+if start > tonumber(redis.call('GET', KEYS[3]) or '0') then
+	redis.call('SET', KEYS[3], string.format('%.0f', start))
+end
+if ARGV[2] == '1' then
+	redis.call('LPOP', KEYS[4])
+end
+redis.call('HDEL', KEYS[5], 'job:' .. id)
+
+local message = redis.call('HGET', KEYS[2], id)
+redis.call('HDEL', KEYS[2], id)
+return message or false
+`)
+
+// removeScript drops a queued or scheduled job and returns how many entries
+// were removed. A queued job also takes one signal entry with it, so that
+// waiting consumers are not woken for it, and the clock it had without an
+// owner.
+//
+// KEYS: jobs, messages, delayed, delayed messages, signal, owners
+// ARGV: job ID
+var removeScript = redis.NewScript(`
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[4], ARGV[1])
+redis.call('HDEL', KEYS[6], 'job:' .. ARGV[1])
+local queued = redis.call('ZREM', KEYS[1], ARGV[1])
+if queued == 1 then
+	redis.call('LPOP', KEYS[5])
+end
+return queued + redis.call('ZREM', KEYS[3], ARGV[1])
+`)
+
+// delayScript schedules a job for a later turn, keeping the earlier time if it
+// is already scheduled.
+//
+// KEYS: delayed, delayed messages
+// ARGV: job ID, message, due time in Unix milliseconds
+var delayScript = redis.NewScript(`
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+local due = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if due and tonumber(due) <= tonumber(ARGV[3]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+return 1
+`)
+
+// promoteScript unschedules a job and returns its message, or nil if another
+// consumer promoted it first.
+//
+// KEYS: delayed, delayed messages
+// ARGV: job ID
+var promoteScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return false
+end
+local message = redis.call('HGET', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+return message or false
+`)
+
+// ClampPriority limits p to the supported range
+func ClampPriority(p int) int {
+	return min(max(p, MinPriority), MaxPriority)
+}
+
+// priorityBand is the score offset of a priority; higher priorities get lower
+// scores and are popped first
+func priorityBand(p int) string {
+	return strconv.FormatInt(int64(MaxPriority-ClampPriority(p))*int64(bandWidth), 10)
+}
+
+func (q *RedisQueue) jobsKey() string     { return q.queueName + ":jobs" }
+func (q *RedisQueue) messagesKey() string { return q.queueName + ":messages" }
+func (q *RedisQueue) ownersKey() string   { return q.queueName + ":owners" }
+func (q *RedisQueue) clockKey() string    { return q.queueName + ":clock" }
+
+// delayedKey holds jobs scheduled for a later turn, scored by due time
+func (q *RedisQueue) delayedKey() string         { return q.queueName + ":delayed" }
+func (q *RedisQueue) delayedMessagesKey() string { return q.queueName + ":delayed_messages" }
+
+// legacyKey is the plain list jobs were pushed to before fair scheduling.
+// Consumers drain it into the fair queue, see drainLegacy.
+func (q *RedisQueue) legacyKey() string { return q.queueName }
+
+// signalKey is a list with one entry per queued job that consumers block on
+func (q *RedisQueue) signalKey() string { return q.queueName + ":signal" }
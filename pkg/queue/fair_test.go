@@ -0,0 +1,236 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestQueue returns a queue backed by an in-memory Redis. The clock starts
+// at one turn because miniredis formats round scores such as 5e11 in a way its
+// Lua cannot parse back, which Redis does not.
+func newTestQueue(t *testing.T) (*RedisQueue, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	q := &RedisQueue{client: client, queueName: "translation_jobs"}
+	server.Set(q.clockKey(), "1")
+	return q, server
+}
+
+func publish(t *testing.T, q *RedisQueue, msgs ...TranslationJobMessage) {
+	t.Helper()
+	for _, msg := range msgs {
+		if err := q.Publish(context.Background(), msg); err != nil {
+			t.Fatalf("Publish(%s): %v", msg.JobID, err)
+		}
+	}
+}
+
+// consumeAll takes jobs without blocking until the queue is empty and returns
+// their IDs in order
+func consumeAll(t *testing.T, q *RedisQueue) []string {
+	t.Helper()
+	ids := []string{}
+	for {
+		msg, err := q.Consume(context.Background(), 0)
+		if err != nil {
+			t.Fatalf("Consume: %v", err)
+		}
+		if msg == nil {
+			return ids
+		}
+		ids = append(ids, msg.JobID)
+	}
+}
+
+func TestOwnersTakeTurns(t *testing.T) {
+	q, _ := newTestQueue(t)
+	publish(t, q,
+		TranslationJobMessage{JobID: "a1", Owner: "alice"},
+		TranslationJobMessage{JobID: "a2", Owner: "alice"},
+		TranslationJobMessage{JobID: "a3", Owner: "alice"},
+		TranslationJobMessage{JobID: "b1", Owner: "bob"},
+		TranslationJobMessage{JobID: "b2", Owner: "bob"},
+	)
+
+	want := []string{"a1", "b1", "a2", "b2", "a3"}
+	if got := consumeAll(t, q); !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestIdleOwnerRejoinsAtCurrentTurn(t *testing.T) {
+	q, _ := newTestQueue(t)
+	publish(t, q,
+		TranslationJobMessage{JobID: "a1", Owner: "alice"},
+		TranslationJobMessage{JobID: "a2", Owner: "alice"},
+		TranslationJobMessage{JobID: "a3", Owner: "alice"},
+	)
+	if got := consumeAll(t, q); len(got) != 3 {
+		t.Fatalf("consumed = %v, want alice's three jobs", got)
+	}
+
+	// Bob does not get a backlog of turns for the time he was idle
+	publish(t, q,
+		TranslationJobMessage{JobID: "a4", Owner: "alice"},
+		TranslationJobMessage{JobID: "a5", Owner: "alice"},
+		TranslationJobMessage{JobID: "b1", Owner: "bob"},
+		TranslationJobMessage{JobID: "b2", Owner: "bob"},
+	)
+
+	want := []string{"b1", "a4", "b2", "a5"}
+	if got := consumeAll(t, q); !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestHigherPriorityJumpsAhead(t *testing.T) {
+	q, _ := newTestQueue(t)
+	publish(t, q,
+		TranslationJobMessage{JobID: "normal-1", Owner: "alice"},
+		TranslationJobMessage{JobID: "normal-2", Owner: "bob"},
+		TranslationJobMessage{JobID: "low", Owner: "carol", Priority: MinPriority},
+		TranslationJobMessage{JobID: "urgent", Owner: "alice", Priority: MaxPriority},
+		// Out of range priorities are clamped
+		TranslationJobMessage{JobID: "too-urgent", Owner: "bob", Priority: MaxPriority + 10},
+	)
+
+	want := []string{"too-urgent", "urgent", "normal-1", "normal-2", "low"}
+	if got := consumeAll(t, q); !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestRepublishKeepsPlace(t *testing.T) {
+	q, _ := newTestQueue(t)
+	publish(t, q,
+		TranslationJobMessage{JobID: "a1", Owner: "alice"},
+		TranslationJobMessage{JobID: "b1", Owner: "bob"},
+		TranslationJobMessage{JobID: "a1", Owner: "alice", TargetLang: "de"},
+	)
+
+	length, err := q.GetQueueLength(context.Background())
+	if err != nil || length != 2 {
+		t.Fatalf("length = %d (%v), want 2", length, err)
+	}
+
+	msg, err := q.Consume(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if msg == nil || msg.JobID != "a1" || msg.TargetLang != "de" {
+		t.Errorf("first = %+v, want a1 with the refreshed message", msg)
+	}
+}
+
+func TestDelayedJobsArePromotedWhenDue(t *testing.T) {
+	q, _ := newTestQueue(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := q.PublishAt(ctx, TranslationJobMessage{JobID: "later"}, now.Add(time.Hour)); err != nil {
+		t.Fatalf("PublishAt: %v", err)
+	}
+	if err := q.PublishAt(ctx, TranslationJobMessage{JobID: "due"}, now.Add(-time.Second)); err != nil {
+		t.Fatalf("PublishAt: %v", err)
+	}
+	// Scheduling again keeps the earlier time
+	if err := q.PublishAt(ctx, TranslationJobMessage{JobID: "due"}, now.Add(time.Hour)); err != nil {
+		t.Fatalf("PublishAt: %v", err)
+	}
+
+	if got, want := consumeAll(t, q), []string{"due"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("consumed = %v, want %v", got, want)
+	}
+
+	scheduled, err := q.client.ZRange(ctx, q.delayedKey(), 0, -1).Result()
+	if err != nil || !reflect.DeepEqual(scheduled, []string{"later"}) {
+		t.Errorf("scheduled = %v (%v), want [later]", scheduled, err)
+	}
+}
+
+func TestRemoveJob(t *testing.T) {
+	q, server := newTestQueue(t)
+	ctx := context.Background()
+	publish(t, q,
+		TranslationJobMessage{JobID: "a1"},
+		TranslationJobMessage{JobID: "b1"},
+	)
+	if err := q.PublishAt(ctx, TranslationJobMessage{JobID: "a1"}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PublishAt: %v", err)
+	}
+
+	removed, err := q.RemoveJob(ctx, "a1")
+	if err != nil || removed != 2 {
+		t.Fatalf("removed = %d (%v), want the queued and the scheduled turn", removed, err)
+	}
+
+	// One signal entry is left for b1
+	signals, err := server.List(q.signalKey())
+	if err != nil || len(signals) != 1 {
+		t.Errorf("signals = %v (%v), want one entry", signals, err)
+	}
+	if got, want := consumeAll(t, q), []string{"b1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("consumed = %v, want %v", got, want)
+	}
+}
+
+func TestDrainLegacyMigratesListItems(t *testing.T) {
+	q, server := newTestQueue(t)
+	publish(t, q, TranslationJobMessage{JobID: "fair-1", Owner: "alice"})
+
+	for _, msg := range []TranslationJobMessage{{JobID: "legacy-1", TargetLang: "de"}, {JobID: "legacy-2"}} {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		server.RPush(q.legacyKey(), string(data))
+	}
+	server.RPush(q.legacyKey(), "not a job")
+	server.RPush(q.legacyKey(), `{"target_lang":"de"}`)
+
+	msg, err := q.Consume(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if msg == nil || msg.JobID != "fair-1" {
+		t.Fatalf("first = %+v, want fair-1", msg)
+	}
+	if server.Exists(q.legacyKey()) {
+		items, _ := server.List(q.legacyKey())
+		t.Errorf("legacy list = %v, want it drained", items)
+	}
+
+	length, err := q.GetQueueLength(context.Background())
+	if err != nil || length != 2 {
+		t.Errorf("length = %d (%v), want the two legacy jobs", length, err)
+	}
+	if got, want := consumeAll(t, q), []string{"legacy-1", "legacy-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("consumed = %v, want %v", got, want)
+	}
+}
+
+func TestConsumeWaitsForPublish(t *testing.T) {
+	q, _ := newTestQueue(t)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		q.Publish(context.Background(), TranslationJobMessage{JobID: "a1"})
+	}()
+
+	msg, err := q.Consume(context.Background(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if msg == nil || msg.JobID != "a1" {
+		t.Errorf("consumed = %+v, want a1", msg)
+	}
+}
@@ -3,13 +3,15 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// RedisQueue handles publishing jobs to Redis queue
+// RedisQueue handles publishing and consuming jobs on a Redis queue
 type RedisQueue struct {
 	client    *redis.Client
 	queueName string
@@ -22,6 +24,11 @@ type TranslationJobMessage struct {
 	SourceLang       string   `json:"source_lang,omitempty"`
 	TargetFields     []string `json:"target_fields,omitempty"`
 	EnableCodeFilter bool     `json:"enable_code_filter,omitempty"`
+	// Priority orders jobs between MinPriority and MaxPriority; higher runs first
+	Priority int `json:"priority"`
+	// Owner is who the job's turns are shared with; jobs without an owner
+	// each get their own share
+	Owner string `json:"owner,omitempty"`
 }
 
 // NewRedisQueue creates a new Redis queue publisher
@@ -47,49 +54,249 @@ func NewRedisQueue(redisURL, queueName string) (*RedisQueue, error) {
 	}, nil
 }
 
-// Publish pushes a translation job to the Redis queue
+// Publish queues a translation job for a turn. A job that is already queued
+// keeps its place; only its message is updated.
 func (q *RedisQueue) Publish(ctx context.Context, msg TranslationJobMessage) error {
-	// Marshal message to JSON
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal job message: %w", err)
 	}
 
-	// Push to Redis list (queue)
-	if err := q.client.RPush(ctx, q.queueName, data).Err(); err != nil {
+	owner := msg.Owner
+	if owner == "" {
+		owner = "job:" + msg.JobID
+	}
+
+	keys := []string{q.jobsKey(), q.messagesKey(), q.ownersKey(), q.clockKey(), q.signalKey()}
+	if err := pushScript.Run(ctx, q.client, keys, msg.JobID, data, owner, priorityBand(msg.Priority)).Err(); err != nil {
 		return fmt.Errorf("failed to push to Redis queue: %w", err)
 	}
 
 	return nil
 }
 
-// PublishBatch pushes multiple translation jobs to the Redis queue
-func (q *RedisQueue) PublishBatch(ctx context.Context, messages []TranslationJobMessage) error {
-	if len(messages) == 0 {
-		return nil
+// PublishAt queues a translation job for a turn once at has passed, e.g. when
+// its next subtask waits for a retry. A job scheduled twice keeps the earlier
+// time.
+func (q *RedisQueue) PublishAt(ctx context.Context, msg TranslationJobMessage, at time.Time) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job message: %w", err)
 	}
 
-	// Prepare all messages
-	values := make([]interface{}, len(messages))
-	for i, msg := range messages {
-		data, err := json.Marshal(msg)
+	keys := []string{q.delayedKey(), q.delayedMessagesKey()}
+	if err := delayScript.Run(ctx, q.client, keys, msg.JobID, data, at.UnixMilli()).Err(); err != nil {
+		return fmt.Errorf("failed to schedule job on Redis queue: %w", err)
+	}
+	return nil
+}
+
+// promoteDue queues the scheduled jobs whose time has come. Every scheduled
+// job is promoted by exactly one consumer.
+func (q *RedisQueue) promoteDue(ctx context.Context) error {
+	due, err := q.client.ZRangeByScore(ctx, q.delayedKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read scheduled jobs: %w", err)
+	}
+
+	keys := []string{q.delayedKey(), q.delayedMessagesKey()}
+	for _, jobID := range due {
+		data, err := promoteScript.Run(ctx, q.client, keys, jobID).Text()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				// Promoted by another consumer
+				continue
+			}
+			return fmt.Errorf("failed to promote scheduled job: %w", err)
+		}
+
+		var msg TranslationJobMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			return fmt.Errorf("failed to unmarshal job message: %w", err)
+		}
+		if err := q.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drainLegacy moves jobs from the plain list that producers used before fair
+// scheduling into the fair queue, so that jobs queued before an upgrade, or by
+// an API instance not yet upgraded, are not stranded. Entries that are not a
+// job message are dropped.
+func (q *RedisQueue) drainLegacy(ctx context.Context) error {
+	for {
+		data, err := q.client.LPop(ctx, q.legacyKey()).Result()
 		if err != nil {
-			return fmt.Errorf("failed to marshal job message at index %d: %w", i, err)
+			if errors.Is(err, redis.Nil) {
+				return nil
+			}
+			return fmt.Errorf("failed to drain legacy Redis queue: %w", err)
+		}
+
+		var msg TranslationJobMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil || msg.JobID == "" {
+			continue
+		}
+		if err := q.Publish(ctx, msg); err != nil {
+			// Put it back for the next attempt
+			q.client.LPush(ctx, q.legacyKey(), data)
+			return err
+		}
+	}
+}
+
+// PublishBatch queues multiple translation jobs
+func (q *RedisQueue) PublishBatch(ctx context.Context, messages []TranslationJobMessage) error {
+	for i, msg := range messages {
+		if err := q.Publish(ctx, msg); err != nil {
+			return fmt.Errorf("failed to publish job message at index %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Consume takes the job whose turn is next, blocking for up to timeout while
+// the queue is empty. It returns nil without an error when the timeout
+// elapses with an empty queue; a timeout of zero does not block.
+func (q *RedisQueue) Consume(ctx context.Context, timeout time.Duration) (*TranslationJobMessage, error) {
+	if err := q.drainLegacy(ctx); err != nil {
+		return nil, err
+	}
+	if err := q.promoteDue(ctx); err != nil {
+		return nil, err
+	}
+
+	msg, err := q.pop(ctx, true)
+	if err != nil || msg != nil || timeout <= 0 {
+		return msg, err
+	}
+
+	// Wait for a job to be pushed, then compete for it
+	if err := q.client.BLPop(ctx, timeout, q.signalKey()).Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
 		}
-		values[i] = data
+		return nil, fmt.Errorf("failed to wait on Redis queue: %w", err)
+	}
+
+	return q.pop(ctx, false)
+}
+
+func (q *RedisQueue) pop(ctx context.Context, dropSignal bool) (*TranslationJobMessage, error) {
+	flag := "0"
+	if dropSignal {
+		flag = "1"
+	}
+
+	keys := []string{q.jobsKey(), q.messagesKey(), q.clockKey(), q.signalKey(), q.ownersKey()}
+	data, err := popScript.Run(ctx, q.client, keys, int64(bandWidth), flag).Text()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to pop from Redis queue: %w", err)
+	}
+
+	var msg TranslationJobMessage
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job message: %w", err)
+	}
+
+	return &msg, nil
+}
+
+// RemoveJob drops jobID from the queue, including a scheduled turn, and
+// returns how many entries were removed
+func (q *RedisQueue) RemoveJob(ctx context.Context, jobID string) (int64, error) {
+	keys := []string{q.jobsKey(), q.messagesKey(), q.delayedKey(), q.delayedMessagesKey(), q.signalKey(), q.ownersKey()}
+	removed, err := removeScript.Run(ctx, q.client, keys, jobID).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove job from Redis queue: %w", err)
 	}
+	return removed, nil
+}
+
+// cancelChannel is the pub/sub channel used to broadcast job cancellations
+func (q *RedisQueue) cancelChannel() string {
+	return q.queueName + ":cancel"
+}
 
-	// Push all messages in one operation
-	if err := q.client.RPush(ctx, q.queueName, values...).Err(); err != nil {
-		return fmt.Errorf("failed to push batch to Redis queue: %w", err)
+// PublishCancellation notifies subscribed workers that jobID was cancelled
+func (q *RedisQueue) PublishCancellation(ctx context.Context, jobID string) error {
+	if err := q.client.Publish(ctx, q.cancelChannel(), jobID).Err(); err != nil {
+		return fmt.Errorf("failed to publish job cancellation: %w", err)
 	}
+	return nil
+}
 
+// SubscribeCancellations streams the IDs of cancelled jobs until ctx is done.
+// Delivery is best effort; workers also notice cancellation through their leases.
+func (q *RedisQueue) SubscribeCancellations(ctx context.Context) (<-chan string, error) {
+	return q.subscribe(ctx, q.cancelChannel())
+}
+
+// eventChannel is the pub/sub channel carrying progress events of one job
+func (q *RedisQueue) eventChannel(jobID string) string {
+	return q.queueName + ":events:" + jobID
+}
+
+// PublishJobEvent broadcasts an already encoded event to the job's subscribers
+func (q *RedisQueue) PublishJobEvent(ctx context.Context, jobID string, payload []byte) error {
+	if err := q.client.Publish(ctx, q.eventChannel(jobID), payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish job event: %w", err)
+	}
 	return nil
 }
 
-// GetQueueLength returns the current number of jobs in the queue
+// SubscribeJobEvents streams the events published for jobID until ctx is done
+func (q *RedisQueue) SubscribeJobEvents(ctx context.Context, jobID string) (<-chan string, error) {
+	return q.subscribe(ctx, q.eventChannel(jobID))
+}
+
+// subscribe forwards the payloads of a pub/sub channel until ctx is done. It
+// waits for the subscription to be confirmed so that no message published
+// after it returns is missed.
+func (q *RedisQueue) subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	pubsub := q.client.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+
+	payloads := make(chan string)
+	go func() {
+		defer close(payloads)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case payloads <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return payloads, nil
+}
+
+// GetQueueLength returns the current number of jobs waiting for a turn
 func (q *RedisQueue) GetQueueLength(ctx context.Context) (int64, error) {
-	return q.client.LLen(ctx, q.queueName).Result()
+	return q.client.ZCard(ctx, q.jobsKey()).Result()
 }
 
 // Close closes the Redis connection
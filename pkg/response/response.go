@@ -41,6 +41,10 @@ const (
 	ErrCodeUserDeletionFailed     = "USER005"
 	ErrCodeUserInvalidCredentials = "USER006"
 	ErrCodeUserValidation         = "USER007"
+
+	ErrCodeQuotaDailyExceeded      = "QUOTA001"
+	ErrCodeQuotaMonthlyExceeded    = "QUOTA002"
+	ErrCodeQuotaActiveJobsExceeded = "QUOTA003"
 )
 
 // Success sends a successful response
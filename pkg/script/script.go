@@ -0,0 +1,178 @@
+// Package script classifies text by writing system. It knows enough about
+// languages to tell which scripts a text in a given language is written in.
+package script
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Script is a writing system
+type Script string
+
+const (
+	Latin      Script = "Latin"
+	Cyrillic   Script = "Cyrillic"
+	Greek      Script = "Greek"
+	Arabic     Script = "Arabic"
+	Hebrew     Script = "Hebrew"
+	Thai       Script = "Thai"
+	Devanagari Script = "Devanagari"
+	Han        Script = "Han"
+	Hiragana   Script = "Hiragana"
+	Katakana   Script = "Katakana"
+	Hangul     Script = "Hangul"
+)
+
+var tables = []struct {
+	script Script
+	table  *unicode.RangeTable
+}{
+	{Latin, unicode.Latin},
+	{Han, unicode.Han},
+	{Hiragana, unicode.Hiragana},
+	{Katakana, unicode.Katakana},
+	{Hangul, unicode.Hangul},
+	{Cyrillic, unicode.Cyrillic},
+	{Greek, unicode.Greek},
+	{Arabic, unicode.Arabic},
+	{Hebrew, unicode.Hebrew},
+	{Thai, unicode.Thai},
+	{Devanagari, unicode.Devanagari},
+}
+
+// languages maps a base language code to the scripts its texts use
+var languages = map[string][]Script{
+	"ja": {Han, Hiragana, Katakana},
+	"zh": {Han},
+	"ko": {Hangul, Han},
+	"ru": {Cyrillic}, "uk": {Cyrillic}, "be": {Cyrillic}, "bg": {Cyrillic}, "sr": {Cyrillic}, "mk": {Cyrillic}, "kk": {Cyrillic},
+	"el": {Greek},
+	"ar": {Arabic}, "fa": {Arabic}, "ur": {Arabic},
+	"he": {Hebrew},
+	"th": {Thai},
+	"hi": {Devanagari}, "mr": {Devanagari}, "ne": {Devanagari},
+}
+
+// Of returns the script of a letter, or "" for anything that is not a
+// letter of a known script (digits, punctuation, spaces, ...)
+func Of(r rune) Script {
+	if r < 0x80 {
+		if unicode.IsLetter(r) {
+			return Latin
+		}
+		return ""
+	}
+	for _, t := range tables {
+		if unicode.Is(t.table, r) {
+			return t.script
+		}
+	}
+	return ""
+}
+
+// Count returns how many letters of each script text contains
+func Count(text string) map[Script]int {
+	counts := make(map[Script]int)
+	for _, r := range text {
+		if s := Of(r); s != "" {
+			counts[s]++
+		}
+	}
+	return counts
+}
+
+// ForLanguage returns the scripts texts in lang are written in. Region and
+// script subtags are ignored ("zh-TW" is "zh"). Languages not listed
+// explicitly are assumed to use the Latin script.
+func ForLanguage(lang string) []Script {
+	base := strings.ToLower(lang)
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	if scripts, ok := languages[base]; ok {
+		return scripts
+	}
+	return []Script{Latin}
+}
+
+// Foreign returns the scripts of sourceLang that targetLang does not use,
+// i.e. those whose letters should not survive translation
+func Foreign(sourceLang, targetLang string) []Script {
+	target := make(map[Script]bool)
+	for _, s := range ForLanguage(targetLang) {
+		target[s] = true
+	}
+
+	foreign := make([]Script, 0)
+	for _, s := range ForLanguage(sourceLang) {
+		if !target[s] {
+			foreign = append(foreign, s)
+		}
+	}
+	return foreign
+}
+
+// Weight is roughly how many Latin letters one letter of lang stands for.
+// It makes text lengths comparable across languages: the same sentence takes
+// about three times as many letters in English as in Japanese.
+func Weight(lang string) float64 {
+	scripts := ForLanguage(lang)
+	switch scripts[0] {
+	case Han:
+		return 3
+	case Hangul:
+		return 2
+	}
+	return 1
+}
+
+// Guess returns the language text is most likely written in, judged by its
+// letters. Only scripts used by a single language (or, for Han, the most
+// likely one) identify a language; for a shared script such as Latin, Guess
+// confirms hint when hint uses that script and returns "" otherwise. Scripts
+// tied for the most letters are decided by their order in tables, so the
+// same text always gets the same guess.
+func Guess(text, hint string) string {
+	counts := Count(text)
+	total := 0
+	var dominant Script
+	for _, t := range tables {
+		n := counts[t.script]
+		total += n
+		if n > counts[dominant] {
+			dominant = t.script
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+
+	// Japanese mixes kana into mostly Han text
+	if kana := counts[Hiragana] + counts[Katakana]; kana*10 >= total {
+		return "ja"
+	}
+
+	switch dominant {
+	case Hangul:
+		return "ko"
+	case Greek:
+		return "el"
+	case Hebrew:
+		return "he"
+	case Thai:
+		return "th"
+	}
+
+	if hint != "" {
+		for _, s := range ForLanguage(hint) {
+			if s == dominant {
+				return hint
+			}
+		}
+	}
+	if dominant == Han {
+		return "zh"
+	}
+	return ""
+}
@@ -0,0 +1,12 @@
+package script
+
+import "testing"
+
+func TestGuessBreaksTiesByTableOrder(t *testing.T) {
+	// As many Han as Hangul letters: Han comes first in tables
+	for run := 0; run < 20; run++ {
+		if got := Guess("漢字한글", ""); got != "zh" {
+			t.Fatalf("run %d: Guess = %q, want %q", run, got, "zh")
+		}
+	}
+}
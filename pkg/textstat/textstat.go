@@ -0,0 +1,88 @@
+// Package textstat measures texts the way translation engines bill them
+package textstat
+
+import (
+	"unicode"
+
+	"simple-go/pkg/epub"
+	"simple-go/pkg/script"
+)
+
+// charsPerToken is the usual ratio of characters to tokens for alphabetic
+// scripts in LLM tokenizers. CJK characters are counted as one token each.
+const charsPerToken = 4
+
+// Stats describes the size of a text. Characters and Tokens count the text as
+// it is sent to the engine, inline markup included; Words counts its visible
+// words, with every Chinese or Japanese character counting as a word.
+type Stats struct {
+	Characters int `json:"characters"`
+	Words      int `json:"words"`
+	Tokens     int `json:"tokens"`
+}
+
+// Add accumulates o into s
+func (s *Stats) Add(o Stats) {
+	s.Characters += o.Characters
+	s.Words += o.Words
+	s.Tokens += o.Tokens
+}
+
+// Measure returns the stats of an HTML fragment
+func Measure(fragment string) Stats {
+	var stats Stats
+
+	wide, other := 0, 0
+	for _, r := range fragment {
+		stats.Characters++
+		if isWide(r) {
+			wide++
+		} else {
+			other++
+		}
+	}
+	stats.Tokens = wide + (other+charsPerToken-1)/charsPerToken
+	stats.Words = Words(epub.PlainText(fragment))
+
+	return stats
+}
+
+// Words counts the words of plain text, every Chinese or Japanese character
+// counting as a word
+func Words(text string) int {
+	words := 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isLogographic(r):
+			words++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		case r == '\'' || r == '’' || r == '-':
+			// Part of words like don't and well-known
+		default:
+			inWord = false
+		}
+	}
+	return words
+}
+
+// isWide reports whether r is a CJK character, which tokenizers treat as a
+// unit of its own
+func isWide(r rune) bool {
+	return isLogographic(r) || script.Of(r) == script.Hangul
+}
+
+// isLogographic reports whether r is written without spaces between words,
+// so that each character is counted as a word
+func isLogographic(r rune) bool {
+	switch script.Of(r) {
+	case script.Han, script.Hiragana, script.Katakana:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package translator
+
+import (
+	"sort"
+	"strings"
+)
+
+// GlossaryEntry pins the translation of a source term. Engines that support
+// terminology use it directly; the others are checked afterwards with
+// CheckGlossary.
+type GlossaryEntry struct {
+	Source string
+	Target string
+}
+
+// GlossaryCheck lists the glossary source terms found in a batch, split by
+// whether the translation used the pinned target term
+type GlossaryCheck struct {
+	Hits   []string
+	Misses []string
+}
+
+// CheckGlossary verifies outputs against the glossary. A term is a hit when
+// every output whose source contains it also contains its target term, and a
+// miss otherwise. Terms absent from all sources are ignored. Matching ignores
+// case and HTML markup.
+func CheckGlossary(sources, outputs []string, glossary []GlossaryEntry) GlossaryCheck {
+	check := GlossaryCheck{Hits: []string{}, Misses: []string{}}
+	if len(glossary) == 0 {
+		return check
+	}
+
+	plainSources := make([]string, len(sources))
+	plainOutputs := make([]string, len(sources))
+	for i := range sources {
+		plainSources[i] = strings.ToLower(stripTags(sources[i]))
+		if i < len(outputs) {
+			plainOutputs[i] = strings.ToLower(stripTags(outputs[i]))
+		}
+	}
+
+	for _, entry := range glossary {
+		source := strings.ToLower(entry.Source)
+		target := strings.ToLower(entry.Target)
+		if source == "" {
+			continue
+		}
+
+		found, missed := false, false
+		for i := range plainSources {
+			if !strings.Contains(plainSources[i], source) {
+				continue
+			}
+			found = true
+			if !strings.Contains(plainOutputs[i], target) {
+				missed = true
+			}
+		}
+
+		switch {
+		case missed:
+			check.Misses = append(check.Misses, entry.Source)
+		case found:
+			check.Hits = append(check.Hits, entry.Source)
+		}
+	}
+	return check
+}
+
+// glossaryReplacer substitutes source terms with their targets, preferring the
+// longest term when several match at the same position
+func glossaryReplacer(glossary []GlossaryEntry) *strings.Replacer {
+	if len(glossary) == 0 {
+		return nil
+	}
+
+	entries := make([]GlossaryEntry, 0, len(glossary))
+	for _, entry := range glossary {
+		if entry.Source != "" {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return len(entries[i].Source) > len(entries[j].Source)
+	})
+
+	pairs := make([]string, 0, len(entries)*2)
+	for _, entry := range entries {
+		pairs = append(pairs, entry.Source, entry.Target)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+func stripTags(text string) string {
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	inTag := false
+	for _, r := range text {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+			sb.WriteByte(' ')
+		case !inTag:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
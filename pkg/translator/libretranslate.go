@@ -0,0 +1,107 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"simple-go/pkg/config"
+)
+
+// ProviderLibreTranslate talks to a LibreTranslate-compatible HTTP API
+const ProviderLibreTranslate = "libretranslate"
+
+func init() {
+	Register(ProviderLibreTranslate, func(cfg config.TranslatorConfig) (Translator, error) {
+		if cfg.BaseURL == "" {
+			return nil, errors.New("libretranslate provider requires TRANSLATOR_BASE_URL")
+		}
+		return NewLibreTranslateTranslator(&http.Client{Timeout: 60 * time.Second}, cfg.BaseURL, cfg.APIKey), nil
+	})
+}
+
+type LibreTranslateTranslator struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+}
+
+func NewLibreTranslateTranslator(httpClient *http.Client, baseURL, apiKey string) *LibreTranslateTranslator {
+	return &LibreTranslateTranslator{
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+	}
+}
+
+func (t *LibreTranslateTranslator) Name() string {
+	return ProviderLibreTranslate
+}
+
+type libreTranslateRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	APIKey string   `json:"api_key,omitempty"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText []string `json:"translatedText"`
+	Error          string   `json:"error"`
+}
+
+func (t *LibreTranslateTranslator) Translate(ctx context.Context, req Request) ([]string, error) {
+	if len(req.Texts) == 0 {
+		return []string{}, nil
+	}
+
+	body, err := json.Marshal(libreTranslateRequest{
+		Q:      req.Texts,
+		Source: req.FromLang,
+		Target: req.TargetLang,
+		Format: "html",
+		APIKey: t.apiKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("libretranslate request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var parsed libreTranslateResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("libretranslate returned status %d: %s", resp.StatusCode, parsed.Error)
+	}
+
+	if len(parsed.TranslatedText) != len(req.Texts) {
+		return nil, fmt.Errorf("libretranslate returned %d texts for %d inputs", len(parsed.TranslatedText), len(req.Texts))
+	}
+
+	return parsed.TranslatedText, nil
+}
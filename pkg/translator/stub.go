@@ -0,0 +1,88 @@
+package translator
+
+import (
+	"context"
+	"strings"
+
+	"simple-go/pkg/config"
+)
+
+// ProviderStub is the built-in deterministic pseudo-translation backend
+const ProviderStub = "stub"
+
+func init() {
+	Register(ProviderStub, func(cfg config.TranslatorConfig) (Translator, error) {
+		return NewStubTranslator(), nil
+	})
+}
+
+// StubTranslator produces a deterministic pseudo-translation so the job
+// pipeline can run end to end without an external engine. Every run of text
+// outside HTML tags is prefixed with the target language code, e.g.
+// "<p>Hello</p>" becomes "<p>[en] Hello</p>". Markup is preserved as-is and
+// glossary terms are replaced by their targets.
+type StubTranslator struct{}
+
+func NewStubTranslator() *StubTranslator {
+	return &StubTranslator{}
+}
+
+func (t *StubTranslator) Name() string {
+	return ProviderStub
+}
+
+func (t *StubTranslator) Translate(ctx context.Context, req Request) ([]string, error) {
+	replacer := glossaryReplacer(req.Glossary)
+
+	out := make([]string, len(req.Texts))
+	for i, text := range req.Texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out[i] = pseudoTranslate(text, "["+req.TargetLang+"] ", replacer)
+	}
+	return out, nil
+}
+
+func pseudoTranslate(text, prefix string, replacer *strings.Replacer) string {
+	var sb strings.Builder
+	sb.Grow(len(text) + len(prefix))
+
+	for len(text) > 0 {
+		if text[0] == '<' {
+			end := strings.IndexByte(text, '>')
+			if end < 0 {
+				// Unterminated tag: treat the remainder as markup
+				sb.WriteString(text)
+				break
+			}
+			sb.WriteString(text[:end+1])
+			text = text[end+1:]
+			continue
+		}
+
+		next := strings.IndexByte(text, '<')
+		if next < 0 {
+			next = len(text)
+		}
+		run := text[:next]
+		text = text[next:]
+
+		trimmed := strings.TrimSpace(run)
+		if trimmed == "" {
+			sb.WriteString(run)
+			continue
+		}
+
+		lead := run[:strings.Index(run, trimmed)]
+		sb.WriteString(lead)
+		sb.WriteString(prefix)
+		if replacer != nil {
+			sb.WriteString(replacer.Replace(run[len(lead):]))
+		} else {
+			sb.WriteString(run[len(lead):])
+		}
+	}
+
+	return sb.String()
+}
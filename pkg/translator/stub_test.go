@@ -0,0 +1,47 @@
+package translator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestStubTranslatorIsDeterministic(t *testing.T) {
+	req := Request{
+		Texts: []string{
+			"Hello",
+			"<p>The hero  arrives.</p>\n<p><em>Again</em> and again</p>",
+			"  <br/>  ",
+			"<p>unterminated <b",
+		},
+		FromLang:   "ja",
+		TargetLang: "en",
+		Glossary:   []GlossaryEntry{{Source: "hero", Target: "Yuusha"}, {Source: "again", Target: "once more"}},
+	}
+	want := []string{
+		"[en] Hello",
+		"<p>[en] The Yuusha  arrives.</p>\n<p><em>[en] Again</em> [en] and once more</p>",
+		"  <br/>  ",
+		"<p>[en] unterminated <b",
+	}
+
+	stub := NewStubTranslator()
+	for run := 0; run < 3; run++ {
+		got, err := stub.Translate(context.Background(), req)
+		if err != nil {
+			t.Fatalf("run %d: Translate: %v", run, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %d: got %q, want %q", run, got, want)
+		}
+	}
+}
+
+func TestStubTranslatorStopsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewStubTranslator().Translate(ctx, Request{Texts: []string{"Hello"}, TargetLang: "en"}); err == nil {
+		t.Fatal("expected an error for a cancelled context")
+	}
+}
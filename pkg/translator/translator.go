@@ -0,0 +1,86 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"simple-go/pkg/config"
+)
+
+// Request is a batch of texts translated between a single language pair
+type Request struct {
+	Texts      []string
+	FromLang   string
+	TargetLang string
+	// Context carries optional hints (entity type, novel title, ...) for
+	// engines that can make use of them
+	Context map[string]string
+	// Glossary lists terms whose translation is fixed for this novel
+	Glossary []GlossaryEntry
+	// Memory holds earlier translations of segments similar to the texts,
+	// for engines that accept reference translations
+	Memory []MemoryMatch
+}
+
+// MemoryMatch is an accepted translation of a segment similar to one in the
+// request. Similarity ranges from 0 to 1.
+type MemoryMatch struct {
+	Source     string
+	Target     string
+	Similarity float64
+}
+
+// Translator is implemented by every translation engine adapter.
+// Translate must return exactly one output per input text, in the same order.
+type Translator interface {
+	Name() string
+	Translate(ctx context.Context, req Request) ([]string, error)
+}
+
+// Factory builds a Translator from configuration
+type Factory func(cfg config.TranslatorConfig) (Translator, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a provider available under the given name.
+// Adapters call it from their init function.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("translator provider %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// New builds the provider selected by cfg.Provider
+func New(cfg config.TranslatorConfig) (Translator, error) {
+	registryMu.RLock()
+	factory, ok := registry[cfg.Provider]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown translator provider %q (available: %v)", cfg.Provider, Providers())
+	}
+
+	return factory(cfg)
+}
+
+// Providers returns the names of all registered providers
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Sender POSTs signed webhook payloads. It only connects to public addresses
+// and does not follow redirects, so a receiver cannot point a delivery at
+// this machine or the internal network through DNS or a 302.
+type Sender struct {
+	httpClient *http.Client
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	return newSender(timeout, dialPublicOnly)
+}
+
+// newSender builds a Sender whose connections are checked by control; tests
+// pass nil to reach their local servers
+func newSender(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *Sender {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would make the dialer check the proxy instead of the receiver
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// Redirects are not followed; the 3xx is reported as a failure
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// dialPublicOnly refuses connections to the addresses ValidateURL rejects.
+// It runs once the host name is resolved, for every connection made.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook delivery refused: %w", err)
+	}
+	if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("webhook delivery to %s refused: not a public address", host)
+	}
+	return nil
+}
+
+// Send delivers body to url and returns the response status code. Any non-2xx
+// response is reported as an error together with its status code.
+func (s *Sender) Send(ctx context.Context, url, secret, deliveryID, eventType string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "silan-webhooks/1.0")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded amount so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
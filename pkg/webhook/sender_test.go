@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSenderSignsRequest(t *testing.T) {
+	body := []byte(`{"type":"job.completed","job_id":"job-1"}`)
+
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	status, err := newSender(5*time.Second, nil).Send(context.Background(), server.URL, "secret-secret-secret", "delivery-1", "job.completed", body)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", status, http.StatusNoContent)
+	}
+
+	if received.Method != http.MethodPost {
+		t.Errorf("method = %s, want POST", received.Method)
+	}
+	if got := received.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if got := received.Header.Get(HeaderEvent); got != "job.completed" {
+		t.Errorf("%s = %q, want job.completed", HeaderEvent, got)
+	}
+	if got := received.Header.Get(HeaderDelivery); got != "delivery-1" {
+		t.Errorf("%s = %q, want delivery-1", HeaderDelivery, got)
+	}
+	if string(receivedBody) != string(body) {
+		t.Errorf("body = %s, want %s", receivedBody, body)
+	}
+
+	timestamp, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid %s header: %v", HeaderTimestamp, err)
+	}
+	if !Verify("secret-secret-secret", timestamp, receivedBody, received.Header.Get(HeaderSignature)) {
+		t.Error("receiver could not verify the signature")
+	}
+}
+
+func TestSenderReportsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	status, err := newSender(5*time.Second, nil).Send(context.Background(), server.URL, "secret-secret-secret", "delivery-1", "job.failed", []byte(`{}`))
+	if err == nil {
+		t.Fatal("expected an error for a 503 response")
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+}
+
+func TestSenderTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	status, err := newSender(50*time.Millisecond, nil).Send(context.Background(), server.URL, "secret-secret-secret", "delivery-1", "job.failed", []byte(`{}`))
+	if err == nil {
+		t.Fatal("expected an error when the receiver does not answer")
+	}
+	if status != 0 {
+		t.Fatalf("status = %d, want 0", status)
+	}
+}
+
+func TestSenderRefusesInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// httptest listens on 127.0.0.1, as would a host name resolving there
+	status, err := NewSender(5*time.Second).Send(context.Background(), server.URL, "secret-secret-secret", "delivery-1", "job.failed", []byte(`{}`))
+	if err == nil {
+		t.Fatal("expected delivery to a loopback address to be refused")
+	}
+	if status != 0 || called {
+		t.Fatalf("status = %d, called = %v, want no request", status, called)
+	}
+}
+
+func TestSenderDoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	status, err := newSender(5*time.Second, nil).Send(context.Background(), server.URL, "secret-secret-secret", "delivery-1", "job.failed", []byte(`{}`))
+	if err == nil {
+		t.Fatal("expected an error for a redirect")
+	}
+	if status != http.StatusFound || followed {
+		t.Fatalf("status = %d, followed = %v, want the 302 reported unfollowed", status, followed)
+	}
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignIsVerified(t *testing.T) {
+	body := []byte(`{"type":"job.completed"}`)
+	signature := Sign("secret-secret-secret", 1700000000, body)
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Fatalf("signature %q lacks the sha256= prefix", signature)
+	}
+	if signature != Sign("secret-secret-secret", 1700000000, body) {
+		t.Fatal("signing the same request twice gave different signatures")
+	}
+	if !Verify("secret-secret-secret", 1700000000, body, signature) {
+		t.Fatal("valid signature was rejected")
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	body := []byte(`{"type":"job.completed"}`)
+	signature := Sign("secret-secret-secret", 1700000000, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		signature string
+	}{
+		{"wrong secret", "another-secret-value", 1700000000, body, signature},
+		{"replayed timestamp", "secret-secret-secret", 1700000001, body, signature},
+		{"changed body", "secret-secret-secret", 1700000000, []byte(`{"type":"job.failed"}`), signature},
+		{"missing prefix", "secret-secret-secret", 1700000000, body, strings.TrimPrefix(signature, "sha256=")},
+		{"empty signature", "secret-secret-secret", 1700000000, body, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Verify(tt.secret, tt.timestamp, tt.body, tt.signature) {
+				t.Fatal("tampered request was accepted")
+			}
+		})
+	}
+}
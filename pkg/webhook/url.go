@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ValidateURL checks that a subscription URL can be delivered to: an absolute
+// http(s) URL whose host is not this machine or a private, link-local or
+// unspecified address. Host names are not resolved here; Sender checks the
+// address it actually connects to.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return errors.New("webhook URL is not a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("webhook URL must use http or https")
+	}
+	if u.User != nil {
+		return errors.New("webhook URL must not contain credentials")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" {
+		return errors.New("webhook URL must have a host")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.New("webhook URL must not point to localhost")
+	}
+
+	if ip := net.ParseIP(host); ip != nil && isInternalIP(ip) {
+		return errors.New("webhook URL must not point to a loopback, private or link-local address")
+	}
+
+	return nil
+}
+
+// isInternalIP reports whether ip belongs to this machine or a network that
+// webhooks must not reach, such as cloud metadata at 169.254.169.254
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast()
+}